@@ -18,16 +18,22 @@ type AniService interface {
 }
 
 // creates a new fiber app with template engine
-// and setup middlewares
-func InitApp() *fiber.App {
-	f := fiber.New(fiber.Config{
+// and setup middlewares, the client ip, host and protocol are read from the
+// X-Forwarded-* headers of the trusted proxies (ips or cidr ranges)
+func InitApp(trustedProxies []string) *fiber.App {
+	cfg := fiber.Config{
 		AppName:                 "Ani-ar",
 		EnableTrustedProxyCheck: true,
+		TrustedProxies:          trustedProxies,
 		PassLocalsToViews:       true,
 		EnableIPValidation:      true,
 		JSONEncoder:             json.Marshal,
 		JSONDecoder:             json.Unmarshal,
-	})
+	}
+	if len(trustedProxies) > 0 {
+		cfg.ProxyHeader = fiber.HeaderXForwardedFor
+	}
+	f := fiber.New(cfg)
 	var once sync.Once
 
 	once.Do(func() {
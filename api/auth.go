@@ -0,0 +1,127 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ani/ani-ar/apikey"
+	"github.com/ani/ani-ar/party"
+	"github.com/goccy/go-json"
+)
+
+// the query parameter of the api key, for the players that can't send headers
+const apiKeyQuery = "api_key"
+
+// withAuth passes the id of the request key to the routes in this header, eg. for the rate limits
+const apiKeyIdHeader = "X-Ani-Api-Key-Id"
+
+// requestApiKey returns the key of the request from the Authorization
+// bearer, the X-Api-Key header or the api_key query parameter
+func requestApiKey(r *http.Request) string {
+	if token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); found {
+		return strings.TrimSpace(token)
+	}
+	if token := r.Header.Get("X-Api-Key"); token != "" {
+		return token
+	}
+	return r.URL.Query().Get(apiKeyQuery)
+}
+
+// requiredScope returns the scope the request needs: the streams and the
+// party need stream, the reads need read, the changes of the jellyfin library
+// and the watchlist need library:write and the rest (profiles, settings and
+// the downloads, they write anywhere on the disk) admin
+func requiredScope(r *http.Request) string {
+	path := r.URL.Path
+	if rest, found := strings.CutPrefix(path, v1BaseUrl+"/"); found {
+		path = baseUrl + "/" + rest
+	}
+	switch {
+	case strings.HasPrefix(path, streamBaseUrl+"/"), path == party.Path:
+		return apikey.ScopeStream
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		return apikey.ScopeRead
+	case strings.HasPrefix(path, JellyfinBaseUrl+"/"),
+		strings.HasPrefix(path, baseUrl+"/add/"),
+		path == watchlistBaseUrl,
+		strings.HasPrefix(path, watchlistBaseUrl+"/"):
+		return apikey.ScopeLibraryWrite
+	}
+	return apikey.ScopeAdmin
+}
+
+type authError struct {
+	Message string `json:"message"`
+	Error   string `json:"error"`
+	Scope   string `json:"scope,omitempty"`
+}
+
+// writeAuthError writes the error, in the error object of the v1 routes for them
+func writeAuthError(w http.ResponseWriter, r *http.Request, status int, e authError) {
+	if r.URL.Path == v1BaseUrl || strings.HasPrefix(r.URL.Path, v1BaseUrl+"/") {
+		writeJSON(w, status, v1ErrorResponse{Error: Error{Code: e.Error, Message: e.Message, Scope: e.Scope}})
+		return
+	}
+	writeJSON(w, status, e)
+}
+
+// the api description is public so the docs can load it, and the web ui
+// asks for the key itself
+func isPublicPath(path string) bool {
+	return path == openapiUrl || path == docsUrl || strings.HasPrefix(path, swaggerUiUrl+"/") ||
+		path == webUrl || strings.HasPrefix(path, webAssetsUrl+"/")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	b, _ := json.Marshal(v)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(b)
+}
+
+// withAuth requires an api key with the scope of the request once a key is
+// created, without keys the api stays open as before
+func withAuth(next http.Handler, keys *apikey.Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// only set by a valid key
+		r.Header.Del(apiKeyIdHeader)
+		// the cors preflight requests don't carry the credentials
+		if r.Method == http.MethodOptions || isPublicPath(r.URL.Path) || !keys.Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if st := r.URL.Query().Get(StreamTokenQuery); st != "" {
+			key, err := authenticateStreamToken(keys, st, r.URL.Path)
+			if err != nil {
+				writeAuthError(w, r, http.StatusUnauthorized, authError{Message: err.Error(), Error: "unauthorized"})
+				return
+			}
+			r.Header.Set(apiKeyIdHeader, key.Id)
+			next.ServeHTTP(w, r)
+			return
+		}
+		token := requestApiKey(r)
+		if token == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="ani-ar"`)
+			writeAuthError(w, r, http.StatusUnauthorized, authError{Message: "an api key is required", Error: "unauthorized"})
+			return
+		}
+		key, err := keys.Authenticate(token)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="ani-ar", error="invalid_token"`)
+			writeAuthError(w, r, http.StatusUnauthorized, authError{Message: err.Error(), Error: "unauthorized"})
+			return
+		}
+		scope := requiredScope(r)
+		if !key.HasScope(scope) {
+			writeAuthError(w, r, http.StatusForbidden, authError{
+				Message: "the api key doesn't have the " + scope + " scope",
+				Error:   "forbidden",
+				Scope:   scope,
+			})
+			return
+		}
+		r.Header.Set(apiKeyIdHeader, key.Id)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/ani/ani-ar/apikey"
+)
+
+func TestRequiredScope(t *testing.T) {
+	tests := []struct {
+		method, path, want string
+	}{
+		{"GET", "/stream/naruto/1", apikey.ScopeStream},
+		{"GET", "/stream/naruto/1/hls", apikey.ScopeStream},
+		{"GET", "/party/ws", apikey.ScopeStream},
+		{"GET", "/api/ani-results/search", apikey.ScopeRead},
+		{"HEAD", "/api/v1/anime/naruto", apikey.ScopeRead},
+		{"GET", "/api/downloads", apikey.ScopeRead},
+		{"POST", "/api/add/naruto", apikey.ScopeLibraryWrite},
+		{"DELETE", "/api/jellyfin/items/naruto", apikey.ScopeLibraryWrite},
+		{"POST", "/api/v1/jellyfin/refresh", apikey.ScopeLibraryWrite},
+		{"POST", "/api/watchlist", apikey.ScopeLibraryWrite},
+		{"DELETE", "/api/v1/watchlist/anime3rb/naruto", apikey.ScopeLibraryWrite},
+		{"POST", "/api/downloads", apikey.ScopeAdmin},
+		{"PUT", "/api/v1/settings", apikey.ScopeAdmin},
+		{"POST", "/api/profiles", apikey.ScopeAdmin},
+		// not the watchlist route
+		{"POST", "/api/watchlists", apikey.ScopeAdmin},
+	}
+	for _, test := range tests {
+		if got := requiredScope(httptest.NewRequest(test.method, test.path, nil)); got != test.want {
+			t.Errorf("%s %s needs %s, want %s", test.method, test.path, got, test.want)
+		}
+	}
+}
+
+func TestAuthIsRequiredOnceAKeyIsCreated(t *testing.T) {
+	keys := apikey.NewStore(filepath.Join(t.TempDir(), "apikeys.json"))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get(apiKeyIdHeader)))
+	})
+	handler := withAuth(mux, keys)
+
+	// without keys the api stays open, and the key id header can't be forged
+	r := httptest.NewRequest("POST", "/api/downloads", nil)
+	r.Header.Set(apiKeyIdHeader, "forged")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != 200 || w.Body.String() != "" {
+		t.Fatalf("the api should be open without keys, got %d %q", w.Code, w.Body.String())
+	}
+
+	token, key, err := keys.Create("tv", []string{apikey.ScopeRead, apikey.ScopeStream})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		method, target, auth string
+		want                 int
+	}{
+		{"GET", "/api/ani-results/search", "", http.StatusUnauthorized},
+		{"GET", "/api/ani-results/search", "Bearer ani_00000000_00", http.StatusUnauthorized},
+		{"GET", "/api/ani-results/search", "Bearer " + token, http.StatusOK},
+		{"GET", "/stream/naruto/1?api_key=" + token, "", http.StatusOK},
+		{"POST", "/api/watchlist", "Bearer " + token, http.StatusForbidden},
+		{"POST", "/api/downloads", "Bearer " + token, http.StatusForbidden},
+		// public
+		{"GET", "/api/openapi.json", "", http.StatusOK},
+		{"OPTIONS", "/api/downloads", "", http.StatusOK},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest(test.method, test.target, nil)
+		if test.auth != "" {
+			r.Header.Set("Authorization", test.auth)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != test.want {
+			t.Errorf("%s %s got %d, want %d: %s", test.method, test.target, w.Code, test.want, w.Body.String())
+		}
+		if w.Code == http.StatusOK && test.auth != "" && w.Body.String() != key.Id {
+			t.Errorf("%s %s should pass the key id, got %q", test.method, test.target, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+)
+
+type basePathKey struct{}
+
+// cleanBasePath returns the base path as /prefix without a trailing slash,
+// an empty or / base path is no base path
+func cleanBasePath(basePath string) (string, error) {
+	if basePath == "" || basePath == "/" {
+		return "", nil
+	}
+	if strings.ContainsAny(basePath, "?#") {
+		return "", errors.New("invalid base path, it should be a path like /ani")
+	}
+	return path.Clean("/" + basePath), nil
+}
+
+// withBasePath serves the routes under the base path, the prefix is removed
+// before routing and the requests outside of it aren't found
+func withBasePath(next http.Handler, basePath string) http.Handler {
+	if basePath == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest, found := strings.CutPrefix(r.URL.Path, basePath)
+		if !found || (rest != "" && !strings.HasPrefix(rest, "/")) {
+			http.NotFound(w, r)
+			return
+		}
+		if rest == "" {
+			rest = "/"
+		}
+		r = r.WithContext(context.WithValue(r.Context(), basePathKey{}, basePath))
+		r.URL.Path = rest
+		r.URL.RawPath = ""
+		// the fiber adaptor routes by the request uri
+		r.RequestURI = r.URL.RequestURI()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// basePathOf returns the base path the request came through, the urls
+// written in the responses (eg. the hls playlists) should start with it
+func basePathOf(r *http.Request) string {
+	basePath, _ := r.Context().Value(basePathKey{}).(string)
+	return basePath
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ani/ani-ar/cache"
+	"github.com/ani/ani-ar/profile"
+	"github.com/gofiber/fiber/v2"
+)
+
+type cachedResponse struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"contentType"`
+	Body        []byte `json:"body"`
+	ETag        string `json:"etag"`
+}
+
+// responseCacheKey identifies the response by the profile (it picks the
+// fetcher), the path and the query without the api key
+func responseCacheKey(c *fiber.Ctx) string {
+	query := url.Values{}
+	for k, v := range c.Queries() {
+		if k != apiKeyQuery {
+			query.Set(k, v)
+		}
+	}
+	return "response:" + requestProfile(c) + ":" + c.Path() + "?" + query.Encode()
+}
+
+// withCache caches the ok json responses of the handler for the ttl of the
+// resource unless it sets Cache-Control to no-store, the identical requests arriving while it runs wait for its
+// response instead of calling the upstream again. The cached responses have
+// an ETag and a Last-Modified so the clients can revalidate them
+func withCache(resource cache.Resource, handler fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var res cachedResponse
+		entry, err := cache.Default().Do(resource, responseCacheKey(c), &res, func() (interface{}, error) {
+			if err := handler(c); err != nil {
+				return nil, err
+			}
+			res := cachedResponse{
+				Status:      c.Response().StatusCode(),
+				ContentType: string(c.Response().Header.ContentType()),
+				Body:        append([]byte(nil), c.Response().Body()...),
+			}
+			// eg. the text "Anime not found" or the episodes without videos, they can be temporary
+			noStore := string(c.Response().Header.Peek(fiber.HeaderCacheControl)) == "no-store"
+			if res.Status != fiber.StatusOK || noStore || !strings.HasPrefix(res.ContentType, fiber.MIMEApplicationJSON) {
+				return res, cache.ErrNotCached
+			}
+			sum := sha256.Sum256(res.Body)
+			res.ETag = `"` + hex.EncodeToString(sum[:16]) + `"`
+			return res, nil
+		})
+		if err != nil && !errors.Is(err, cache.ErrNotCached) {
+			return err
+		}
+
+		c.Status(res.Status)
+		c.Set(fiber.HeaderContentType, res.ContentType)
+		if errors.Is(err, cache.ErrNotCached) {
+			c.Set(fiber.HeaderCacheControl, "no-store")
+		} else {
+			c.Set(fiber.HeaderVary, profile.Header)
+			c.Set(fiber.HeaderETag, res.ETag)
+			c.Set(fiber.HeaderLastModified, entry.StoredAt.UTC().Format(http.TimeFormat))
+			if entry.Expires.IsZero() {
+				// the resource isn't cached
+				c.Set(fiber.HeaderCacheControl, "no-cache")
+			} else {
+				maxAge := max(int(time.Until(entry.Expires).Seconds()), 0)
+				c.Set(fiber.HeaderCacheControl, fmt.Sprintf("private, max-age=%d", maxAge))
+			}
+			if notModified(c, res.ETag, entry.StoredAt) {
+				c.Response().ResetBody()
+				c.Status(fiber.StatusNotModified)
+				return nil
+			}
+		}
+		return c.Send(res.Body)
+	}
+}
+
+// notModified reports whether the client already has the response, the
+// If-None-Match header wins over If-Modified-Since
+func notModified(c *fiber.Ctx, etag string, storedAt time.Time) bool {
+	if match := c.Get(fiber.HeaderIfNoneMatch); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+	since, err := http.ParseTime(c.Get(fiber.HeaderIfModifiedSince))
+	return err == nil && !storedAt.Truncate(time.Second).After(since)
+}
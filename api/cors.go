@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+const (
+	corsAllowMethods = "GET, POST, HEAD, PUT, DELETE, PATCH"
+	// the web pages of other origins can wait for the rate limits
+	corsExposeHeaders = "Retry-After, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset"
+)
+
+// originAllowed reports whether the origin is in the list, the entries can
+// allow the subdomains of a site (eg. https://*.example.com)
+func originAllowed(origin string, origins []string) bool {
+	for _, allowed := range origins {
+		if strings.EqualFold(origin, allowed) {
+			return true
+		}
+		scheme, host, found := strings.Cut(allowed, "://*.")
+		if found && strings.HasPrefix(strings.ToLower(origin), strings.ToLower(scheme)+"://") &&
+			strings.HasSuffix(strings.ToLower(origin), "."+strings.ToLower(host)) {
+			return true
+		}
+	}
+	return false
+}
+
+// withCors applies the allowed origins to every route, the stream proxy and
+// the event stream are served next to the fiber app. The preflight requests
+// are answered here before they reach the auth
+func withCors(next http.Handler, origins []string) http.Handler {
+	allowAll := slices.Contains(origins, "*")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		allowed := ""
+		if allowAll {
+			allowed = "*"
+		} else {
+			w.Header().Add("Vary", "Origin")
+			if origin != "" && originAllowed(origin, origins) {
+				allowed = origin
+			}
+		}
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			if allowed != "" {
+				w.Header().Set("Access-Control-Allow-Origin", allowed)
+				w.Header().Set("Access-Control-Allow-Methods", corsAllowMethods)
+				if headers := r.Header.Get("Access-Control-Request-Headers"); headers != "" {
+					w.Header().Set("Access-Control-Allow-Headers", headers)
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if allowed != "" {
+			w.Header().Set("Access-Control-Allow-Origin", allowed)
+			w.Header().Set("Access-Control-Expose-Headers", corsExposeHeaders)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
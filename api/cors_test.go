@@ -0,0 +1,62 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCors(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /stream/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("video"))
+	})
+	handler := withCors(mux, []string{"https://example.com", "https://*.ani.dev"})
+
+	tests := []struct {
+		origin string
+		want   string
+	}{
+		{"https://example.com", "https://example.com"},
+		{"https://watch.ani.dev", "https://watch.ani.dev"},
+		{"https://ani.dev.evil.com", ""},
+		{"http://watch.ani.dev", ""},
+		{"https://other.com", ""},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", "/stream/1", nil)
+		r.Header.Set("Origin", test.origin)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != test.want {
+			t.Errorf("origin %s allowed %q, want %q", test.origin, got, test.want)
+		}
+		if w.Body.String() != "video" {
+			t.Errorf("origin %s: the route should answer, got %q", test.origin, w.Body.String())
+		}
+	}
+
+	// the preflight is answered without reaching the route
+	r := httptest.NewRequest("OPTIONS", "/stream/1", nil)
+	r.Header.Set("Origin", "https://example.com")
+	r.Header.Set("Access-Control-Request-Method", "GET")
+	r.Header.Set("Access-Control-Request-Headers", "authorization")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusNoContent ||
+		w.Header().Get("Access-Control-Allow-Origin") != "https://example.com" ||
+		w.Header().Get("Access-Control-Allow-Headers") != "authorization" {
+		t.Fatalf("unexpected preflight answer %d %v", w.Code, w.Header())
+	}
+}
+
+func TestCorsAllowAll(t *testing.T) {
+	handler := withCors(http.NotFoundHandler(), []string{"*"})
+	r := httptest.NewRequest("GET", "/api/events", nil)
+	r.Header.Set("Origin", "https://example.com")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Header().Get("Access-Control-Allow-Origin") != "*" || w.Header().Get("Access-Control-Expose-Headers") == "" {
+		t.Fatalf("every origin should be allowed, %v", w.Header())
+	}
+}
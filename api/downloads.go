@@ -0,0 +1,133 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/ani/ani-ar/download"
+	"github.com/ani/ani-ar/fetcher"
+	"github.com/ani/ani-ar/profile"
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	ErrInvalidJobId    = errors.New("invalid download job id")
+	ErrInvalidDir      = errors.New("the download folder should be an absolute path inside the downloads folder or ANI_AR_DOWNLOADS_ROOTS")
+	errInvalidEpisodes = errors.New("invalid episodes range")
+)
+
+func InitiateDownloadRoutes(app *fiber.App, queue *download.Queue) {
+	app.Get(downloadsBaseUrl, func(c *fiber.Ctx) error {
+		return c.JSON(queue.Jobs())
+	})
+
+	// queues the episodes from..to of the anime (every episode by default),
+	// eg. {"id": "hunter-x-hunter-2011", "from": 1, "to": 12, "quality": "720", "dir": "/mnt/anime"}
+	app.Post(downloadsBaseUrl, func(c *fiber.Ctx) error {
+		var body struct {
+			Id      string `json:"id"`
+			Source  string `json:"source"`
+			From    int    `json:"from"`
+			To      int    `json:"to"`
+			Quality string `json:"quality"`
+			Dir     string `json:"dir"`
+		}
+		if err := c.BodyParser(&body); err != nil || body.Id == "" {
+			return c.Status(400).JSON(map[string]string{"message": "the anime id is required"})
+		}
+		name := requestProfile(c)
+		if body.Quality == "" {
+			body.Quality = profile.GetSettings(name).Quality
+		}
+		if err := (profile.Settings{Quality: body.Quality}).Validate(); err != nil {
+			return downloadError(c, err)
+		}
+		// the folders are limited to the roots, the clients can't write the
+		// episodes anywhere the server user can
+		if body.Dir != "" {
+			if !queue.InRoots(body.Dir) {
+				return downloadError(c, ErrInvalidDir)
+			}
+			body.Dir = filepath.Clean(body.Dir)
+		}
+		f := requestFetcher(c)
+		if body.Source != "" {
+			var err error
+			if f, err = fetcher.GetFetcherByName(body.Source); err != nil {
+				return c.Status(400).JSON(map[string]string{"message": err.Error()})
+			}
+		}
+		anime := f.GetAnimeResult(body.Id)
+		if anime == nil {
+			return downloadError(c, ErrAnimeNotFound)
+		}
+		episodes := f.GetEpisodes(*anime)
+		from, to := max(body.From, 1), body.To
+		if to == 0 {
+			to = len(episodes)
+		}
+		if from > to || to > len(episodes) {
+			return downloadError(c, fmt.Errorf("%w, the anime has %d episodes", errInvalidEpisodes, len(episodes)))
+		}
+
+		jobs, err := queue.AddWith(download.JobOptions{
+			Profile: name,
+			Source:  fetcher.GetFetcherName(f),
+			Quality: body.Quality,
+			Dir:     body.Dir,
+		}, episodes[from-1:to]...)
+		if err != nil {
+			return downloadError(c, err)
+		}
+		if jobs == nil {
+			// the episodes are already in the queue
+			jobs = []download.Job{}
+		}
+		return c.Status(201).JSON(jobs)
+	})
+
+	jobHandler := func(action func(id int) (download.Job, error)) fiber.Handler {
+		return func(c *fiber.Ctx) error {
+			id, err := c.ParamsInt("jobId")
+			if err != nil {
+				return downloadError(c, ErrInvalidJobId)
+			}
+			job, err := action(id)
+			if err != nil {
+				return downloadError(c, err)
+			}
+			return c.JSON(job)
+		}
+	}
+	app.Get(downloadUrl, jobHandler(queue.Get))
+	app.Post(downloadPauseUrl, jobHandler(queue.Pause))
+	app.Post(downloadResumeUrl, jobHandler(queue.Resume))
+
+	// cancels the job and removes it from the queue
+	app.Delete(downloadUrl, func(c *fiber.Ctx) error {
+		id, err := c.ParamsInt("jobId")
+		if err != nil {
+			return downloadError(c, ErrInvalidJobId)
+		}
+		if err := queue.Remove(id); err != nil {
+			return downloadError(c, err)
+		}
+		return c.SendStatus(204)
+	})
+}
+
+func downloadError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, download.ErrJobNotFound), errors.Is(err, ErrAnimeNotFound):
+		return c.Status(404).JSON(map[string]string{"message": err.Error()})
+	case errors.Is(err, download.ErrJobNotPaused), errors.Is(err, download.ErrJobNotPending):
+		return c.Status(409).JSON(map[string]string{"message": err.Error()})
+	case errors.Is(err, ErrInvalidJobId),
+		errors.Is(err, ErrInvalidDir),
+		errors.Is(err, profile.ErrInvalidQuality),
+		errors.Is(err, errInvalidEpisodes):
+		return c.Status(400).JSON(map[string]string{"message": err.Error()})
+	}
+	return err
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ani/ani-ar/events"
+	"github.com/goccy/go-json"
+)
+
+// the comments keep the idle connections open through the proxies
+const eventsPingInterval = 15 * time.Second
+
+// eventsHandler streams the events with server-sent events until the client
+// leaves or the server shuts down. The types query parameter filters them by
+// type or group (eg. ?types=download,jellyfin.revision) and the reconnecting
+// clients get the events they missed after their Last-Event-ID
+func eventsHandler(shutdown <-chan struct{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming isn't supported", http.StatusInternalServerError)
+			return
+		}
+		var filters []string
+		for _, f := range strings.Split(r.URL.Query().Get("types"), ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				filters = append(filters, f)
+			}
+		}
+		lastId := r.Header.Get("Last-Event-ID")
+		if lastId == "" {
+			lastId = r.URL.Query().Get("lastEventId")
+		}
+		id, _ := strconv.ParseUint(lastId, 10, 64)
+
+		sub := events.Subscribe(id, filters)
+		defer sub.Close()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		// nginx buffers the responses by default
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, "retry: 3000\n\n")
+		flusher.Flush()
+
+		ping := time.NewTicker(eventsPingInterval)
+		defer ping.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-shutdown:
+				return
+			case <-ping.C:
+				fmt.Fprint(w, ": ping\n\n")
+			case e := <-sub.C:
+				b, err := json.Marshal(e)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.Id, e.Type, b)
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ani/ani-ar/events"
+	"github.com/goccy/go-json"
+)
+
+type sseEvent struct {
+	id, event string
+	data      events.Event
+}
+
+// readEvent reads the next event of the stream, the comments are skipped
+func readEvent(t *testing.T, r *bufio.Reader) sseEvent {
+	t.Helper()
+	var e sseEvent
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "" && e.id != "":
+			return e
+		case strings.HasPrefix(line, "id: "):
+			e.id = line[len("id: "):]
+		case strings.HasPrefix(line, "event: "):
+			e.event = line[len("event: "):]
+		case strings.HasPrefix(line, "data: "):
+			if err := json.Unmarshal([]byte(line[len("data: "):]), &e.data); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestEventsReplayAfterTheLastEventId(t *testing.T) {
+	shutdown := make(chan struct{})
+	srv := httptest.NewServer(eventsHandler(shutdown))
+	defer srv.Close()
+
+	// the ids of the events on the default bus, the client saw the first one
+	sub := events.Subscribe(0, nil)
+	defer sub.Close()
+	events.Publish(events.DownloadStarted, "naruto 0")
+	events.Publish(events.DownloadQueued, "naruto 1")
+	events.Publish(events.JellyfinRevisionDone, nil)
+	events.Publish(events.DownloadDone, "naruto 1")
+	var ids []uint64
+	for range 4 {
+		ids = append(ids, (<-sub.C).Id)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", srv.URL+"?types=download", nil)
+	req.Header.Set("Last-Event-ID", strconv.FormatUint(ids[0], 10))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("got %s", resp.Header.Get("Content-Type"))
+	}
+	r := bufio.NewReader(resp.Body)
+
+	// the missed download events, then the new ones
+	for _, want := range []struct {
+		id        uint64
+		eventType string
+	}{{ids[1], events.DownloadQueued}, {ids[3], events.DownloadDone}} {
+		e := readEvent(t, r)
+		if e.id != strconv.FormatUint(want.id, 10) || e.event != want.eventType || e.data.Id != want.id || e.data.Data != "naruto 1" {
+			t.Fatalf("got %+v, want the event %d of %s", e, want.id, want.eventType)
+		}
+	}
+	events.Publish(events.JellyfinItemAdded, nil)
+	events.Publish(events.DownloadFailed, "naruto 2")
+	if e := readEvent(t, r); e.event != events.DownloadFailed || e.data.Data != "naruto 2" {
+		t.Fatalf("got %+v", e)
+	}
+
+	// the stream ends with the server
+	close(shutdown)
+	if _, err := r.ReadString('\n'); err == nil {
+		t.Fatal("the stream should end once the server shuts down")
+	}
+}
@@ -3,17 +3,16 @@ package api
 import (
 	"encoding/json"
 	"fmt"
-	"net/http"
-	"time"
 
-	cache "github.com/patrickmn/go-cache"
+	"github.com/ani/ani-ar/cache"
+	"github.com/ani/ani-ar/scraper"
+	"github.com/ani/ani-ar/types"
 )
 
 const jikanBaseUrl = "https://api.jikan.moe/v4"
 
-type JikanApi struct {
-	C *cache.Cache
-}
+// the jikan responses are cached in the default cache, see cache.Fetch
+type JikanApi struct{}
 
 var jikanApi *JikanApi
 
@@ -21,9 +20,7 @@ func GetJikanApi() *JikanApi {
 	if jikanApi != nil {
 		return jikanApi
 	}
-	jikanApi = &JikanApi{
-		C: cache.New(5*time.Minute, 10*time.Minute),
-	}
+	jikanApi = &JikanApi{}
 	return jikanApi
 }
 
@@ -151,11 +148,18 @@ type Genre struct {
 }
 
 func (j *JikanApi) getBestMatchAnimeInfo(animeTitleOrId string) *JikanAnimeInfo {
-	cacheKey := "jikan.result." + animeTitleOrId
-	if v, found := j.C.Get(cacheKey); found {
-		return v.(*JikanAnimeInfo)
-	}
-	res, err := http.Get(fmt.Sprintf("%s/anime?q=%s", jikanBaseUrl, animeTitleOrId))
+	info, _ := cache.Fetch(cache.JikanAnime, animeTitleOrId, func() (*JikanAnimeInfo, error) {
+		info := j.fetchBestMatchAnimeInfo(animeTitleOrId)
+		if info == nil {
+			return nil, cache.ErrNotCached
+		}
+		return info, nil
+	})
+	return info
+}
+
+func (j *JikanApi) fetchBestMatchAnimeInfo(animeTitleOrId string) *JikanAnimeInfo {
+	res, err := scraper.Get(fmt.Sprintf("%s/anime?q=%s", jikanBaseUrl, animeTitleOrId))
 	if err != nil {
 		println(err.Error())
 		return nil
@@ -167,18 +171,23 @@ func (j *JikanApi) getBestMatchAnimeInfo(animeTitleOrId string) *JikanAnimeInfo
 	}
 	var response Response
 	json.NewDecoder(res.Body).Decode(&response)
-	bestMatch := response.Data[0]
-	j.C.Set(cacheKey, bestMatch, time.Hour*24*6)
-	return bestMatch
+	if len(response.Data) == 0 {
+		return nil
+	}
+	return response.Data[0]
 }
 
-func (j *JikanApi) getEpisodesWithPagination(episodes []*JikanAnimeEpisode, animeMalId int, page int) []*JikanAnimeEpisode {
-	cacheKey := "jikan.episodes." + fmt.Sprintf("%v", animeMalId)
-	if v, found := j.C.Get(cacheKey); found {
-		return v.([]*JikanAnimeEpisode)
+// GetMalId returns the MyAnimeList id of the best match for the anime title or id, 0 when there is no match
+func (j *JikanApi) GetMalId(animeTitleOrId string) int {
+	info := j.getBestMatchAnimeInfo(animeTitleOrId)
+	if info == nil {
+		return 0
 	}
+	return info.MalID
+}
 
-	res, err := http.Get(fmt.Sprintf("%s/anime/%v/episodes?page=%v", jikanBaseUrl, animeMalId, page))
+func (j *JikanApi) getEpisodesWithPagination(episodes []*JikanAnimeEpisode, animeMalId int, page int) []*JikanAnimeEpisode {
+	res, err := scraper.Get(fmt.Sprintf("%s/anime/%v/episodes?page=%v", jikanBaseUrl, animeMalId, page))
 	if err != nil {
 		println(err.Error())
 		return []*JikanAnimeEpisode{}
@@ -195,8 +204,7 @@ func (j *JikanApi) getEpisodesWithPagination(episodes []*JikanAnimeEpisode, anim
 	json.NewDecoder(res.Body).Decode(&response)
 	episodes = append(episodes, response.Data...)
 
-	if page == response.Pagination.LastVisiblePage {
-		j.C.Set(cacheKey, episodes, time.Hour*24*1)
+	if page >= response.Pagination.LastVisiblePage {
 		return episodes
 	}
 	return j.getEpisodesWithPagination(episodes, animeMalId, page+1)
@@ -204,17 +212,53 @@ func (j *JikanApi) getEpisodesWithPagination(episodes []*JikanAnimeEpisode, anim
 }
 
 func (j *JikanApi) getEpisodes(animeMalId int) []*JikanAnimeEpisode {
-	return j.getEpisodesWithPagination([]*JikanAnimeEpisode{}, animeMalId, 1)
+	episodes, _ := cache.Fetch(cache.JikanEpisodes, fmt.Sprint(animeMalId), func() ([]*JikanAnimeEpisode, error) {
+		episodes := j.getEpisodesWithPagination([]*JikanAnimeEpisode{}, animeMalId, 1)
+		if len(episodes) == 0 {
+			return episodes, cache.ErrNotCached
+		}
+		return episodes, nil
+	})
+	return episodes
 }
 
-func (j *JikanApi) getSingleEpisode(animeMalId, episodeNum int) *JikanAnimeEpisode {
-	cacheKey := "jikan.episodes." + fmt.Sprintf("%v", animeMalId)
-	if v, found := j.C.Get(cacheKey); found {
-		allCachedEpisodes := v.([]*JikanAnimeEpisode)
-		return allCachedEpisodes[episodeNum-1]
+// AddEpisodesDetails fills the episodes title, airing date and filler/recap flags
+// from the best MyAnimeList match, they are left untouched when there is no match
+func (j *JikanApi) AddEpisodesDetails(anime types.AniResult, episodes []types.AniEpisode) {
+	bestMatch := j.getBestMatchAnimeInfo(anime.Id)
+	if bestMatch == nil || bestMatch.Episodes != anime.Episodes {
+		return
+	}
+	byNumber := make(map[int]*JikanAnimeEpisode)
+	for _, e := range j.getEpisodes(bestMatch.MalID) {
+		byNumber[e.MalID] = e
+	}
+	for i := range episodes {
+		e, found := byNumber[episodes[i].Number]
+		if !found {
+			continue
+		}
+		episodes[i].Title = e.Title
+		episodes[i].Aired = e.Aired
+		episodes[i].Filler = e.Filler
+		episodes[i].Recap = e.Recap
 	}
+}
+
+func (j *JikanApi) getSingleEpisode(animeMalId, episodeNum int) *JikanAnimeEpisode {
+	key := fmt.Sprintf("%d/%d", animeMalId, episodeNum)
+	episode, _ := cache.Fetch(cache.JikanEpisodes, key, func() (*JikanAnimeEpisode, error) {
+		episode := j.fetchSingleEpisode(animeMalId, episodeNum)
+		if episode == nil {
+			return nil, cache.ErrNotCached
+		}
+		return episode, nil
+	})
+	return episode
+}
 
-	res, err := http.Get(fmt.Sprintf("%s/anime/%d/episodes/%d", jikanBaseUrl, animeMalId, episodeNum))
+func (j *JikanApi) fetchSingleEpisode(animeMalId, episodeNum int) *JikanAnimeEpisode {
+	res, err := scraper.Get(fmt.Sprintf("%s/anime/%d/episodes/%d", jikanBaseUrl, animeMalId, episodeNum))
 	if err != nil {
 		println(err.Error())
 		return nil
@@ -228,3 +272,58 @@ func (j *JikanApi) getSingleEpisode(animeMalId, episodeNum int) *JikanAnimeEpiso
 	json.NewDecoder(res.Body).Decode(&response)
 	return response.Data
 }
+
+// GetSeasonNow returns the anime airing this season
+func (j *JikanApi) GetSeasonNow() []*JikanAnimeInfo {
+	return j.getAnimeList("/seasons/now")
+}
+
+// GetSeasonUpcoming returns the anime announced for the next seasons
+func (j *JikanApi) GetSeasonUpcoming() []*JikanAnimeInfo {
+	return j.getAnimeList("/seasons/upcoming")
+}
+
+// GetTopAnime returns the top anime, filter is one of airing, upcoming,
+// bypopularity or favorite (empty for the top rated)
+func (j *JikanApi) GetTopAnime(filter string) []*JikanAnimeInfo {
+	return j.getAnimeList("/top/anime?filter=" + filter)
+}
+
+// returns the first page of a jikan anime list, the same anime can show up
+// twice in the seasons lists so they are deduplicated
+func (j *JikanApi) getAnimeList(path string) []*JikanAnimeInfo {
+	list, _ := cache.Fetch(cache.JikanLists, path, func() ([]*JikanAnimeInfo, error) {
+		list := j.fetchAnimeList(path)
+		if len(list) == 0 {
+			return list, cache.ErrNotCached
+		}
+		return list, nil
+	})
+	return list
+}
+
+func (j *JikanApi) fetchAnimeList(path string) []*JikanAnimeInfo {
+	res, err := scraper.Get(jikanBaseUrl + path)
+	if err != nil {
+		println(err.Error())
+		return []*JikanAnimeInfo{}
+	}
+
+	defer res.Body.Close()
+	type Response struct {
+		Data []*JikanAnimeInfo `json:"data"`
+	}
+	var response Response
+	json.NewDecoder(res.Body).Decode(&response)
+
+	seen := make(map[int]bool)
+	list := []*JikanAnimeInfo{}
+	for _, info := range response.Data {
+		if seen[info.MalID] {
+			continue
+		}
+		seen[info.MalID] = true
+		list = append(list, info)
+	}
+	return list
+}
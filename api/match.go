@@ -0,0 +1,95 @@
+package api
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/ani/ani-ar/fetcher"
+	"github.com/ani/ani-ar/types"
+)
+
+// results scoring less than this are different shows that only share some words
+const minTitleSimilarity = 0.4
+
+// FindInFetcher searches the fetcher with the MyAnimeList titles of the anime
+// and returns the result with the closest title, nil when nothing is close enough
+func FindInFetcher(f fetcher.Fetcher, info *JikanAnimeInfo) *types.AniResult {
+	titles := []string{info.Title}
+	if info.TitleEnglish != "" && info.TitleEnglish != info.Title {
+		titles = append(titles, info.TitleEnglish)
+	}
+	titles = append(titles, info.TitleSynonyms...)
+
+	var best *types.AniResult
+	bestScore := 0.0
+	// synonyms are only compared, searching with them would be too slow
+	for _, query := range titles[:min(len(titles), 2)] {
+		for _, result := range f.Search(query) {
+			score := 0.0
+			for _, title := range titles {
+				score = max(score, titleSimilarity(title, result.DisplayName))
+			}
+			// the episodes count breaks the ties between seasons
+			if info.Episodes > 0 && info.Episodes == result.Episodes {
+				score += 0.1
+			}
+			if score > bestScore {
+				r := result
+				best, bestScore = &r, score
+			}
+		}
+		if bestScore >= 1 {
+			break
+		}
+	}
+	if bestScore < minTitleSimilarity {
+		return nil
+	}
+	return best
+}
+
+// titleSimilarity is the dice coefficient of the bigrams of the normalized titles
+func titleSimilarity(a, b string) float64 {
+	a, b = normalizeTitle(a), normalizeTitle(b)
+	if a == b {
+		return 1
+	}
+	bigramsA, bigramsB := bigrams(a), bigrams(b)
+	if len(bigramsA) == 0 || len(bigramsB) == 0 {
+		return 0
+	}
+	counts := make(map[string]int)
+	for _, g := range bigramsA {
+		counts[g]++
+	}
+	shared := 0
+	for _, g := range bigramsB {
+		if counts[g] > 0 {
+			counts[g]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(bigramsA)+len(bigramsB))
+}
+
+// lowercase letters and digits separated by single spaces
+func normalizeTitle(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func bigrams(s string) []string {
+	r := []rune(s)
+	var grams []string
+	for i := 0; i+1 < len(r); i++ {
+		grams = append(grams, string(r[i:i+2]))
+	}
+	return grams
+}
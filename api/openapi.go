@@ -0,0 +1,41 @@
+package api
+
+import (
+	"embed"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/filesystem"
+)
+
+// the description of the v1 routes, its server is relative to it so it
+// works under a base path
+//
+//go:embed openapi.json
+var openapiSpec []byte
+
+// the docs page and swagger ui 5.18.2 (swagger-ui-dist), it's vendored so the
+// docs work offline and don't run a script of another origin
+var (
+	//go:embed docs.html
+	docsPage []byte
+	//go:embed swagger-ui
+	swaggerUiFiles embed.FS
+)
+
+func InitiateOpenapiRoutes(app *fiber.App) {
+	app.Get(openapiUrl, func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return c.Send(openapiSpec)
+	})
+
+	app.Get(docsUrl, func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return c.Send(docsPage)
+	})
+
+	app.Use(swaggerUiUrl, filesystem.New(filesystem.Config{
+		Root:       http.FS(swaggerUiFiles),
+		PathPrefix: "swagger-ui",
+	}))
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ani/ani-ar/apikey"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+// specOperations returns the methods of the paths of the api description
+func specOperations(t *testing.T) map[string]map[string]json.RawMessage {
+	t.Helper()
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(openapiSpec, &spec); err != nil {
+		t.Fatal(err)
+	}
+	return spec.Paths
+}
+
+// specPath turns the fiber params of the route into the openapi ones
+func specPath(route string) string {
+	return regexp.MustCompile(`:(\w+)`).ReplaceAllString(route, "{$1}")
+}
+
+func TestSpecDescribesTheRoutes(t *testing.T) {
+	t.Setenv("ANI_AR_CONFIG_DIR", t.TempDir())
+	paths := specOperations(t)
+	app, err := newApp(&ServerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	served := map[string]bool{}
+	for _, route := range app.GetRoutes(true) {
+		if route.Method == "HEAD" {
+			continue
+		}
+		// the v1 anime routes and the unversioned routes shared with v1, the
+		// legacy routes, the web ui and the api description aren't described
+		path, found := strings.CutPrefix(route.Path, v1BaseUrl)
+		if !found {
+			rest, found := strings.CutPrefix(route.Path, baseUrl)
+			if !found || !isV1Alias(rest) {
+				continue
+			}
+			path = rest
+		}
+		path = specPath(path)
+		method := strings.ToLower(route.Method)
+		served[method+" "+path] = true
+		if _, found := paths[path][method]; !found {
+			t.Errorf("%s %s isn't in the api description", route.Method, path)
+		}
+	}
+
+	// the event stream and the stream proxy are served next to the fiber app
+	served["get /events"] = true
+	served["get /stream/{animeId}/{episodeNum}"] = true
+	for path, methods := range paths {
+		// the jellyfin routes are registered by the jellyfin package
+		if strings.HasPrefix(path, "/jellyfin/") {
+			continue
+		}
+		for _, method := range []string{"get", "post", "put", "patch", "delete"} {
+			if _, found := methods[method]; found && !served[method+" "+path] {
+				t.Errorf("%s %s is described but not served", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+func TestDocsAreServedWithoutAKey(t *testing.T) {
+	t.Setenv("ANI_AR_CONFIG_DIR", t.TempDir())
+	keys := apikey.GetStore()
+	if _, _, err := keys.Create("tv", []string{apikey.ScopeRead}); err != nil {
+		t.Fatal(err)
+	}
+	app, err := newApp(&ServerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := withAuth(adaptor.FiberApp(app), keys)
+
+	status, page := get(t, handler, docsUrl)
+	if status != 200 || strings.Contains(page, "https://") {
+		t.Fatalf("the docs should be served without another origin, got %d\n%s", status, page)
+	}
+	for _, file := range []string{"swagger-ui.css", "swagger-ui-bundle.js"} {
+		if status, _ := get(t, handler, swaggerUiUrl+"/"+file); status != 200 {
+			t.Errorf("%s: got %d", file, status)
+		}
+	}
+}
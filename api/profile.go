@@ -0,0 +1,133 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/ani/ani-ar/fetcher"
+	"github.com/ani/ani-ar/profile"
+	"github.com/gofiber/fiber/v2"
+)
+
+// withProfile picks the profile of the request from the X-Ani-Profile header,
+// the profile query parameter or a /profiles/<name> path prefix, the prefix
+// is removed so every route is also served under it (eg. /profiles/sara/api/watchlist)
+func withProfile(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rest, found := strings.CutPrefix(r.URL.Path, profilesPathPrefix); found {
+			name, path, _ := strings.Cut(rest, "/")
+			r.Header.Set(profile.Header, name)
+			r.URL.Path = "/" + path
+			r.URL.RawPath = ""
+			// the fiber adaptor routes by the request uri
+			r.RequestURI = r.URL.RequestURI()
+		}
+		name := r.Header.Get(profile.Header)
+		if name == "" {
+			name = r.URL.Query().Get("profile")
+		}
+		if name != "" {
+			if !profile.Exists(name) {
+				writeJSON(w, http.StatusNotFound, map[string]string{"message": profile.ErrNotFound.Error()})
+				return
+			}
+			r.Header.Set(profile.Header, name)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// the profile picked by the request, the active profile of the server by default
+func requestProfile(c *fiber.Ctx) string {
+	if name := c.Get(profile.Header); name != "" {
+		return name
+	}
+	return profile.Active()
+}
+
+func requestProfileOf(r *http.Request) string {
+	if name := r.Header.Get(profile.Header); name != "" {
+		return name
+	}
+	return profile.Active()
+}
+
+// the fetcher picked in the settings of the request profile
+func requestFetcher(c *fiber.Ctx) fetcher.Fetcher {
+	return fetcher.GetProfileFetcher(requestProfile(c))
+}
+
+func InitiateProfileRoutes(app *fiber.App) {
+	app.Get(profilesBaseUrl, func(c *fiber.Ctx) error {
+		type profileType struct {
+			Name     string           `json:"name"`
+			Settings profile.Settings `json:"settings"`
+		}
+		profiles := []profileType{}
+		for _, name := range profile.List() {
+			profiles = append(profiles, profileType{Name: name, Settings: profile.GetSettings(name)})
+		}
+		return c.JSON(profiles)
+	})
+
+	app.Post(profilesBaseUrl, func(c *fiber.Ctx) error {
+		var body struct {
+			Name string `json:"name"`
+		}
+		if err := c.BodyParser(&body); err != nil || body.Name == "" {
+			return c.Status(400).JSON(map[string]string{"message": "the profile name is required"})
+		}
+		if err := profile.Create(body.Name); err != nil {
+			return profileError(c, err)
+		}
+		return c.Status(201).JSON(map[string]string{"name": body.Name})
+	})
+
+	app.Get(settingsUrl, func(c *fiber.Ctx) error {
+		return c.JSON(profile.GetSettings(requestProfile(c)))
+	})
+
+	app.Patch(settingsUrl, func(c *fiber.Ctx) error {
+		var body struct {
+			Source      *string `json:"source"`
+			Quality     *string `json:"quality"`
+			Translation *string `json:"translation"`
+		}
+		if err := c.BodyParser(&body); err != nil {
+			return c.Status(400).JSON(map[string]string{"message": "invalid body, reason: " + err.Error()})
+		}
+		name := requestProfile(c)
+		s := profile.GetSettings(name)
+		if body.Source != nil {
+			s.Source = *body.Source
+		}
+		if body.Quality != nil {
+			s.Quality = *body.Quality
+		}
+		if body.Translation != nil {
+			s.Translation = *body.Translation
+		}
+		if err := fetcher.ValidateSettings(s); err != nil {
+			return c.Status(400).JSON(map[string]string{"message": err.Error()})
+		}
+		if err := profile.SaveSettings(name, s); err != nil {
+			return profileError(c, err)
+		}
+		return c.JSON(s)
+	})
+}
+
+func profileError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, profile.ErrNotFound):
+		return c.Status(404).JSON(map[string]string{"message": err.Error()})
+	case errors.Is(err, profile.ErrExists):
+		return c.Status(409).JSON(map[string]string{"message": err.Error()})
+	case errors.Is(err, profile.ErrInvalidName),
+		errors.Is(err, profile.ErrInvalidQuality),
+		errors.Is(err, profile.ErrInvalidTranslation):
+		return c.Status(400).JSON(map[string]string{"message": err.Error()})
+	}
+	return err
+}
@@ -0,0 +1,285 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ani/ani-ar/party"
+)
+
+// the route groups of the rate limits
+const (
+	// the anime searches, each one scrapes up to 3 pages of the source
+	RateGroupSearch = "search"
+	// the anime, their episodes and videos
+	RateGroupAnime = "anime"
+	// the rest of the api
+	RateGroupApi = "api"
+	// the stream proxy and the watch party, the episodes are resolved again
+	// once their cached video expires
+	RateGroupStream = "stream"
+)
+
+var rateGroups = []string{RateGroupSearch, RateGroupAnime, RateGroupApi, RateGroupStream}
+
+var ErrUnknownRateGroup = errors.New("unknown rate limit group, it should be search, anime, api or stream")
+
+// RateLimit is the number of requests a client can send in the period, no
+// requests means no limit
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// RateLimits are the limits of the route groups
+type RateLimits map[string]RateLimit
+
+// DefaultRateLimits are the limits of a client ip
+var DefaultRateLimits = RateLimits{
+	RateGroupSearch: {Requests: 30, Period: time.Minute},
+	RateGroupAnime:  {Requests: 120, Period: time.Minute},
+	RateGroupApi:    {Requests: 600, Period: time.Minute},
+	// the players request every hls segment and seek with range requests
+	RateGroupStream: {Requests: 300, Period: time.Minute},
+}
+
+// DefaultKeyRateLimits are the limits of an api key, its clients are trusted more than an ip
+var DefaultKeyRateLimits = RateLimits{
+	RateGroupSearch: {Requests: 60, Period: time.Minute},
+	RateGroupAnime:  {Requests: 300, Period: time.Minute},
+	RateGroupApi:    {Requests: 1200, Period: time.Minute},
+	RateGroupStream: {Requests: 600, Period: time.Minute},
+}
+
+// ParseRateLimits returns the defaults changed by a list like
+// search=10/1m,anime=100/1m, a group set to 0 isn't limited
+func ParseRateLimits(list string, defaults RateLimits) (RateLimits, error) {
+	limits := RateLimits{}
+	for group, limit := range defaults {
+		limits[group] = limit
+	}
+	for _, pair := range strings.Split(list, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		group, value, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid rate limit %q, it should look like search=10/1m", pair)
+		}
+		group = strings.TrimSpace(group)
+		if _, found := defaults[group]; !found {
+			return nil, ErrUnknownRateGroup
+		}
+		requests, period, _ := strings.Cut(strings.TrimSpace(value), "/")
+		limit := RateLimit{Period: time.Minute}
+		var err error
+		if limit.Requests, err = strconv.Atoi(requests); err != nil || limit.Requests < 0 {
+			return nil, fmt.Errorf("invalid rate limit %q, the requests should be a positive number", pair)
+		}
+		if period != "" {
+			if limit.Period, err = time.ParseDuration(period); err != nil || limit.Period <= 0 {
+				return nil, fmt.Errorf("invalid rate limit %q, the period should be a duration like 1m", pair)
+			}
+		}
+		limits[group] = limit
+	}
+	return limits, nil
+}
+
+// rateGroup returns the group of the route, the web ui and the api
+// description aren't limited
+func rateGroup(path string) string {
+	switch {
+	case path == searchAniResultsBaseUrl, path == v1AnimeUrl:
+		return RateGroupSearch
+	case strings.HasPrefix(path, aniResultsBaseUrl+"/"),
+		strings.HasPrefix(path, aniEpisodesBaseUrl+"/"),
+		strings.HasPrefix(path, v1AnimeUrl+"/"),
+		strings.HasPrefix(path, baseUrl+"/add/"):
+		return RateGroupAnime
+	case path == openapiUrl, path == docsUrl, strings.HasPrefix(path, swaggerUiUrl+"/"):
+		return ""
+	case strings.HasPrefix(path, baseUrl+"/"):
+		return RateGroupApi
+	case strings.HasPrefix(path, streamBaseUrl+"/"), path == party.Path:
+		return RateGroupStream
+	}
+	return ""
+}
+
+// rateWindow counts the requests of a client in the current period and the previous one
+type rateWindow struct {
+	start       time.Time
+	count, prev int
+}
+
+// rateLimiter counts the requests of the clients in a sliding window of its period
+type rateLimiter struct {
+	limit RateLimit
+
+	mu      sync.Mutex
+	windows map[string]*rateWindow
+	swept   time.Time
+}
+
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	if limit.Requests <= 0 {
+		return nil
+	}
+	return &rateLimiter{limit: limit, windows: make(map[string]*rateWindow)}
+}
+
+// take counts a request of the client, it returns false once the client is
+// over the limit. reset is when the current window ends
+func (l *rateLimiter) take(client string, now time.Time) (remaining int, reset time.Duration, ok bool) {
+	period := l.limit.Period
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// the windows of the clients that stopped sending requests
+	if now.Sub(l.swept) > period {
+		for key, w := range l.windows {
+			if now.Sub(w.start) >= 2*period {
+				delete(l.windows, key)
+			}
+		}
+		l.swept = now
+	}
+
+	w := l.windows[client]
+	if w == nil {
+		w = &rateWindow{start: now}
+		l.windows[client] = w
+	}
+	elapsed := now.Sub(w.start)
+	switch {
+	case elapsed >= 2*period:
+		*w = rateWindow{start: now}
+		elapsed = 0
+	case elapsed >= period:
+		*w = rateWindow{start: w.start.Add(period), prev: w.count}
+		elapsed -= period
+	}
+	// the requests of the previous window count for the part of it that's still in the period
+	used := int(float64(w.prev)*float64(period-elapsed)/float64(period)) + w.count
+	reset = period - elapsed
+	if used >= l.limit.Requests {
+		return 0, reset, false
+	}
+	w.count++
+	return l.limit.Requests - used - 1, reset, true
+}
+
+// clientIp returns the ip of the request, behind the trusted proxies (ips or
+// cidr ranges) it's the first hop of X-Forwarded-For from the right that isn't
+// one of them, the hops on its left are written by the client and can be forged
+func clientIp(r *http.Request, trustedProxies []*net.IPNet) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if !isTrustedProxy(net.ParseIP(ip), trustedProxies) {
+		return ip
+	}
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(hops[i]))
+		if hop == nil {
+			// a hop the proxies didn't write, the last trusted one sent it
+			break
+		}
+		ip = hop.String()
+		if !isTrustedProxy(hop, trustedProxies) {
+			break
+		}
+	}
+	return ip
+}
+
+func isTrustedProxy(ip net.IP, trustedProxies []*net.IPNet) bool {
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range trustedProxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseTrustedProxies(proxies []string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
+
+// writeRateLimited answers with the error object of the v1 routes for them
+func writeRateLimited(w http.ResponseWriter, r *http.Request, retryAfter int) {
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	message := "too many requests, retry in " + strconv.Itoa(retryAfter) + " seconds"
+	if r.URL.Path == v1BaseUrl || strings.HasPrefix(r.URL.Path, v1BaseUrl+"/") {
+		writeJSON(w, http.StatusTooManyRequests, v1ErrorResponse{Error: Error{Code: errorCode(http.StatusTooManyRequests), Message: message}})
+		return
+	}
+	writeJSON(w, http.StatusTooManyRequests, map[string]string{"message": message})
+}
+
+// withRateLimit limits the requests of every route group by api key, or by
+// client ip (read from the X-Forwarded-For of the trusted proxies) for the
+// requests without one. It covers the fiber app and the routes of the mux
+// (eg. the streams and the events), withAuth should run first so the key of
+// the request is known
+func withRateLimit(next http.Handler, ipLimits, keyLimits RateLimits, trustedProxies []string) http.Handler {
+	type groupLimiters struct {
+		ip, key *rateLimiter
+	}
+	limiters := make(map[string]groupLimiters)
+	for _, group := range rateGroups {
+		limiters[group] = groupLimiters{ip: newRateLimiter(ipLimits[group]), key: newRateLimiter(keyLimits[group])}
+	}
+	proxies := parseTrustedProxies(trustedProxies)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		group := rateGroup(r.URL.Path)
+		if group == "" || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		limiter, client := limiters[group].ip, clientIp(r, proxies)
+		if keyId := r.Header.Get(apiKeyIdHeader); keyId != "" {
+			limiter, client = limiters[group].key, keyId
+		}
+		if limiter == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		remaining, reset, ok := limiter.take(client, time.Now())
+		seconds := int(math.Ceil(reset.Seconds()))
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limiter.limit.Requests))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		w.Header().Set("X-RateLimit-Reset", strconv.Itoa(seconds))
+		if !ok {
+			writeRateLimited(w, r, seconds)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
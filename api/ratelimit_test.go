@@ -0,0 +1,135 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterSlidingWindow(t *testing.T) {
+	l := newRateLimiter(RateLimit{Requests: 2, Period: time.Minute})
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		if _, _, ok := l.take("1.2.3.4", now); !ok {
+			t.Fatalf("request %d should be allowed", i+1)
+		}
+	}
+	if _, reset, ok := l.take("1.2.3.4", now); ok || reset != time.Minute {
+		t.Fatalf("the third request should wait for the window, reset %v", reset)
+	}
+	if _, _, ok := l.take("5.6.7.8", now); !ok {
+		t.Fatal("the clients have their own limit")
+	}
+	// half of the previous window is still in the period
+	if _, _, ok := l.take("1.2.3.4", now.Add(90*time.Second)); !ok {
+		t.Fatal("a request should be allowed once the window slides")
+	}
+	if _, _, ok := l.take("1.2.3.4", now.Add(90*time.Second)); ok {
+		t.Fatal("the requests of the previous window should still count")
+	}
+	if newRateLimiter(RateLimit{}) != nil {
+		t.Fatal("no requests means no limit")
+	}
+}
+
+func TestMuxRoutesAreRateLimited(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /stream/{animeId}/{episode}", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("GET /api/events", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("GET /api/docs", func(w http.ResponseWriter, r *http.Request) {})
+	limits := RateLimits{
+		RateGroupApi:    {Requests: 1, Period: time.Minute},
+		RateGroupStream: {Requests: 2, Period: time.Minute},
+	}
+	handler := withRateLimit(mux, limits, RateLimits{}, []string{"10.0.0.0/8"})
+
+	send := func(target, remoteAddr, forwardedFor string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", target, nil)
+		r.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			r.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	// the resolutions of an episode share the limit
+	for _, target := range []string{"/stream/naruto/1", "/stream/naruto/1?res=720"} {
+		if w := send(target, "1.2.3.4:1000", ""); w.Code != 200 {
+			t.Fatalf("%s: got %d", target, w.Code)
+		}
+	}
+	w := send("/stream/naruto/1?res=480", "1.2.3.4:1000", "")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Fatalf("the stream should be limited, got %d %v", w.Code, w.Header())
+	}
+
+	// the events are in the api group
+	send("/api/events", "1.2.3.4:1000", "")
+	if w := send("/api/events", "1.2.3.4:1000", ""); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("the events should be limited, got %d", w.Code)
+	}
+	if w := send("/api/docs", "1.2.3.4:1000", ""); w.Code != 200 {
+		t.Fatalf("the docs aren't limited, got %d", w.Code)
+	}
+
+	// the forwarded ip is only read from the trusted proxies
+	if w := send("/api/events", "5.6.7.8:1000", "1.2.3.4"); w.Code != 200 {
+		t.Fatalf("the forged header should be ignored, got %d", w.Code)
+	}
+	if w := send("/api/events", "10.0.0.1:1000", "1.2.3.4"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("the ip of the trusted proxy should be the forwarded one, got %d", w.Code)
+	}
+}
+
+func TestClientIpOfTheForwardedFor(t *testing.T) {
+	proxies := parseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	tests := []struct {
+		name, remoteAddr, forwardedFor, want string
+	}{
+		{"direct", "1.2.3.4:1000", "", "1.2.3.4"},
+		{"untrusted remote", "5.6.7.8:1000", "1.2.3.4", "5.6.7.8"},
+		{"trusted proxy", "10.0.0.1:1000", "1.2.3.4", "1.2.3.4"},
+		{"chained proxies", "10.0.0.1:1000", "1.2.3.4, 192.168.1.1", "1.2.3.4"},
+		// the client sends its own header with a forged ip, the proxy appends the real one
+		{"spoofed left hop", "10.0.0.1:1000", "9.9.9.9, 1.2.3.4", "1.2.3.4"},
+		{"spoofed trusted hop", "10.0.0.1:1000", "10.0.0.2, 1.2.3.4", "1.2.3.4"},
+		{"invalid hop", "10.0.0.1:1000", "garbage, 192.168.1.1", "192.168.1.1"},
+		{"only proxies", "10.0.0.1:1000", "10.0.0.2", "10.0.0.2"},
+		{"no header", "10.0.0.1:1000", "", "10.0.0.1"},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = test.remoteAddr
+		if test.forwardedFor != "" {
+			r.Header.Set("X-Forwarded-For", test.forwardedFor)
+		}
+		if got := clientIp(r, proxies); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestApiKeysHaveTheirOwnLimits(t *testing.T) {
+	limits := RateLimits{RateGroupApi: {Requests: 1, Period: time.Minute}}
+	handler := withRateLimit(http.NotFoundHandler(), limits, RateLimits{}, nil)
+
+	r := httptest.NewRequest("GET", "/api/v1/watchlist", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusTooManyRequests || !strings.Contains(w.Body.String(), `"code":"rate_limited"`) {
+		t.Fatalf("got %d", w.Code)
+	}
+
+	// the key group isn't limited
+	r.Header.Set(apiKeyIdHeader, "abcd")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("the requests of the key should get through, got %d", w.Code)
+	}
+}
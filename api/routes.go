@@ -4,32 +4,33 @@ import (
 	"errors"
 	"strconv"
 
+	"github.com/ani/ani-ar/cache"
 	"github.com/ani/ani-ar/fetcher"
 	"github.com/ani/ani-ar/types"
 	"github.com/gofiber/fiber/v2"
 )
 
 func InitiateRoutes(app *fiber.App) {
-	fetcher := fetcher.GetDefaultFetcher()
 	jikan := GetJikanApi()
 
-	app.Get(searchAniResultsBaseUrl, func(c *fiber.Ctx) error {
+	app.Get(searchAniResultsBaseUrl, withCache(cache.Search, func(c *fiber.Ctx) error {
 		search := c.Query("q")
-		results := fetcher.Search(search)
+		results := requestFetcher(c).Search(search)
 		return c.JSON(results)
-	})
+	}))
 
-	app.Get(getResultByIdUrl, func(c *fiber.Ctx) error {
+	app.Get(getResultByIdUrl, withCache(cache.Anime, func(c *fiber.Ctx) error {
 		animeId := c.Params("animeId")
-		enhanced, err := GetAnimeEnhancedResults(animeId, fetcher)
+		enhanced, err := GetAnimeEnhancedResults(animeId, requestFetcher(c))
 		if err != nil {
 			return err
 		}
 		return c.JSON(enhanced)
-	})
+	}))
 
-	app.Get(getEpisodesBaseUrl, func(c *fiber.Ctx) error {
+	app.Get(getEpisodesBaseUrl, withCache(cache.Episodes, func(c *fiber.Ctx) error {
 		animeIdOrTitle := c.Params("animeId")
+		fetcher := requestFetcher(c)
 		anime := fetcher.GetAnimeResult(animeIdOrTitle)
 		if anime == nil {
 			return c.Send([]byte("Anime not found"))
@@ -47,27 +48,39 @@ func InitiateRoutes(app *fiber.App) {
 		// if no match we can return the fetcher episodes instead
 		fetcherEpisodes := fetcher.GetEpisodes(*anime)
 		return c.JSON(fetcherEpisodes)
-	})
-	app.Get(getSingleEpisodeBaseUrl, func(c *fiber.Ctx) error {
+	}))
+	app.Get(getSingleEpisodeBaseUrl, withCache(cache.Videos, func(c *fiber.Ctx) error {
 		animeIdOrTitle := c.Params("animeId")
 		episodeNumParam := c.Params("episodeNum")
 
+		fetcher := requestFetcher(c)
 		fetcherAnime := fetcher.GetAnimeResult(animeIdOrTitle)
 		if fetcherAnime == nil {
 			return c.Send([]byte("Anime not found"))
 		}
 
 		episodeNum, err := strconv.Atoi(episodeNumParam)
-		if err != nil {
-			return c.Status(400).JSON(map[string]string{"message": "invalid episode number"})
+		if err != nil || episodeNum < 1 {
+			return c.Status(400).JSON(map[string]string{"message": ErrInvalidEpisode.Error()})
 		}
 
-		bestMatch := jikan.getBestMatchAnimeInfo(animeIdOrTitle)
-		jikanEpisode := jikan.getSingleEpisode(bestMatch.MalID, episodeNum)
 		fetcherEpisodes := fetcher.GetEpisodes(*fetcherAnime)
+		if episodeNum > len(fetcherEpisodes) {
+			return c.Status(404).JSON(map[string]string{"message": ErrEpisodeNotFound.Error()})
+		}
+		// jikan doesn't always find the anime
+		bestMatch := jikan.getBestMatchAnimeInfo(animeIdOrTitle)
+		var jikanEpisode *JikanAnimeEpisode
+		if bestMatch != nil {
+			jikanEpisode = jikan.getSingleEpisode(bestMatch.MalID, episodeNum)
+		}
 
 		fetcherEpisode := fetcherEpisodes[episodeNum-1]
 		medias := fetcherEpisode.GetPlayersWithQuality()
+		if len(medias) == 0 {
+			// the sources can be down for a while
+			c.Set(fiber.HeaderCacheControl, "no-store")
+		}
 
 		type EpisodeType struct {
 			ArMedias []types.AniVideo `json:"arMediaUrl"`
@@ -85,7 +98,7 @@ func InitiateRoutes(app *fiber.App) {
 				ArMedias: medias,
 			},
 		})
-	})
+	}))
 
 }
 
@@ -94,15 +107,17 @@ type EnhancedAnimeResult struct {
 	Data    *types.AniResult `json:"data"`
 }
 
+var ErrAnimeNotFound = errors.New("anime not found")
+
 func GetAnimeEnhancedResults(animeIdOrTitle string, fetcher fetcher.Fetcher) (*EnhancedAnimeResult, error) {
 	jikan := GetJikanApi()
 	anime := fetcher.GetAnimeResult(animeIdOrTitle)
 	if anime == nil {
-		return nil, errors.New("anime not found")
+		return nil, ErrAnimeNotFound
 	}
 	details := jikan.getBestMatchAnimeInfo(animeIdOrTitle)
 	enhancedResult := &EnhancedAnimeResult{Data: anime}
-	if details.Episodes == anime.Episodes {
+	if details != nil && details.Episodes == anime.Episodes {
 		enhancedResult.Details = details
 	}
 	return enhancedResult, nil
@@ -2,6 +2,8 @@ package api
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log"
 	"net"
@@ -9,11 +11,17 @@ import (
 	"os"
 	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/ani/ani-ar/apikey"
+	"github.com/ani/ani-ar/config"
+	"github.com/ani/ani-ar/download"
+	"github.com/ani/ani-ar/party"
 	"github.com/fatih/color"
+	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/adaptor"
-	"github.com/gofiber/fiber/v2/middleware/cors"
+	"golang.org/x/crypto/acme/autocert"
 )
 
 type ServerConfig struct {
@@ -21,11 +29,11 @@ type ServerConfig struct {
 	ShowStartBanner bool
 
 	// HttpAddr is the TCP address to listen for the HTTP server (eg. `127.0.0.1:80`).
+	// With HttpsAddr it only redirects to the HTTPS server and answers the ACME challenges.
 	HttpAddr string
 
 	// HttpsAddr is the TCP address to listen for the HTTPS server (eg. `127.0.0.1:443`).
-	// TODO: implement https
-	// HttpsAddr string
+	HttpsAddr string
 
 	// Optional domains list to use when issuing the TLS certificate.
 	//
@@ -33,112 +41,185 @@ type ServerConfig struct {
 	//
 	// For convenience, for each "non-www" domain a "www" entry and
 	// redirect will be automatically added.
-	// CertificateDomains []string
+	CertificateDomains []string
+
+	// CertFile and KeyFile are an optional certificate and its key, the
+	// certificates are issued with ACME when they aren't set.
+	CertFile string
+	KeyFile  string
+
+	// CertificateCacheDir is where the ACME certificates are kept (default to the config folder).
+	CertificateCacheDir string
+
+	// AcmeDirectoryUrl is the ACME server (default to Let's Encrypt), eg. a local pebble for testing.
+	AcmeDirectoryUrl string
+
+	// AcmeEmail is the optional contact email of the ACME account.
+	AcmeEmail string
 
 	// AllowedOrigins is an optional list of CORS origins (default to "*").
 	AllowedOrigins []string
 
+	// TrustedProxies is an optional list of reverse proxies ips or cidr ranges,
+	// the client ip, host and protocol are read from their X-Forwarded-* headers.
+	TrustedProxies []string
+
+	// BasePath is an optional path prefix every route is served under (eg. `/ani`),
+	// for a reverse proxy forwarding a path of its site to the server.
+	BasePath string
+
+	// ReadTimeout is the maximum duration for reading a request (default to 10 minutes).
+	ReadTimeout time.Duration
+
+	// WriteTimeout is the maximum duration for writing a response, it's
+	// disabled by default because it cuts the long video streams.
+	WriteTimeout time.Duration
+
 	TimeToWaitBeforeGracefulShutdown time.Duration
+
+	// ShutdownTimeout is how long the shutdown waits for the open requests
+	// before closing them (default to 10 seconds).
+	ShutdownTimeout time.Duration
+
+	// Party is an optional watch party hosted on this server
+	Party *party.Hub
+
+	// PartyOnly serves only the watch party and the stream proxy, without the
+	// api, the web ui and the download jobs (eg. for `party host` on the local network).
+	PartyOnly bool
+
+	// Routes are optional route groups of the packages importing api, eg. the jellyfin library
+	Routes []RouteGroup
+
+	// RateLimits are the limits of the route groups by client ip (default to DefaultRateLimits).
+	RateLimits RateLimits
+
+	// KeyRateLimits are the limits of the route groups by api key (default to DefaultKeyRateLimits).
+	KeyRateLimits RateLimits
 }
 
+// RouteGroup registers its routes on the fiber app
+type RouteGroup func(app *fiber.App)
+
 func Serve(cfg *ServerConfig) (*http.Server, error) {
 	if len(cfg.AllowedOrigins) == 0 {
 		cfg.AllowedOrigins = []string{"*"}
 	}
+	if cfg.RateLimits == nil {
+		cfg.RateLimits = DefaultRateLimits
+	}
+	if cfg.KeyRateLimits == nil {
+		cfg.KeyRateLimits = DefaultKeyRateLimits
+	}
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return nil, errors.New("the certificate and its key should be set together")
+	}
+	if cfg.PartyOnly && cfg.Party == nil {
+		return nil, errors.New("a party only server needs the party")
+	}
 
-	app := InitApp()
-	app.Use(cors.New())
-	InitiateRoutes(app)
-
-	// app.Use(cors.New(cors.Config{
-	// 	AllowOrigins: "*",
-	// 	AllowHeaders: "Origin, Content-Type, Accept",
-	// 	AllowMethods: strings.Join([]string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodPatch, http.MethodPost, http.MethodDelete}, ","),
-	// }))
+	mux := http.NewServeMux()
+	// the streams are resolved with the fetcher of the request profile
+	NewStreamProxy(nil).RegisterRoutes(mux)
+	if cfg.Party != nil {
+		// websockets can't go through the fiber adaptor
+		mux.Handle("GET "+party.Path, cfg.Party)
+	}
+	// the event streams end with the shutdown instead of holding it up
+	shutdown := make(chan struct{})
+	if !cfg.PartyOnly {
+		app, err := newApp(cfg)
+		if err != nil {
+			return nil, err
+		}
+		mux.Handle("GET "+eventsUrl, eventsHandler(shutdown))
+		mux.Handle("/", adaptor.FiberApp(app))
+	}
 
 	// start http server
 	// ---
 	mainAddr := cfg.HttpAddr
-	// if cfg.HttpsAddr != "" {
-	// 	mainAddr = cfg.HttpsAddr
-	// }
-
-	// var wwwRedirects []string
-
-	// extract the host names for the certificate host policy
-	// hostNames := cfg.CertificateDomains
-	// if len(hostNames) == 0 {
-	// 	host, _, _ := net.SplitHostPort(mainAddr)
-	// 	hostNames = append(hostNames, host)
-	// }
-	// for _, host := range hostNames {
-	// 	if strings.HasPrefix(host, "www.") {
-	// 		continue // explicitly set www host
-	// 	}
-
-	// 	wwwHost := "www." + host
-	// 	if !list.ExistInSlice(wwwHost, hostNames) {
-	// 		hostNames = append(hostNames, wwwHost)
-	// 		wwwRedirects = append(wwwRedirects, wwwHost)
-	// 	}
-	// }
-
-	// implicit www->non-www redirect(s)
-	// if len(wwwRedirects) > 0 {
-	// 	router.Pre(func(next echo.HandlerFunc) echo.HandlerFunc {
-	// 		return func(c echo.Context) error {
-	// 			host := c.Request().Host
-
-	// 			if strings.HasPrefix(host, "www.") && list.ExistInSlice(host, wwwRedirects) {
-	// 				return c.Redirect(
-	// 					http.StatusTemporaryRedirect,
-	// 					(c.Scheme() + "://" + host[4:] + c.Request().RequestURI),
-	// 				)
-	// 			}
-
-	// 			return next(c)
-	// 		}
-	// 	})
-	// }
-
-	// certManager := &autocert.Manager{
-	// 	Prompt:     autocert.AcceptTOS,
-	// 	Cache:      autocert.DirCache(filepath.Join(app.DataDir(), ".autocert_cache")),
-	// 	HostPolicy: autocert.HostWhitelist(hostNames...),
-	// }
+	if cfg.HttpsAddr != "" {
+		mainAddr = cfg.HttpsAddr
+	}
+
+	basePath, err := cleanBasePath(cfg.BasePath)
+	if err != nil {
+		return nil, err
+	}
+	keys := apikey.GetStore()
+	// the cors policy and the rate limits cover the routes of the mux too, eg. the streams and the events
+	limited := withRateLimit(mux, cfg.RateLimits, cfg.KeyRateLimits, cfg.TrustedProxies)
+	var handler http.Handler = withBasePath(withCors(withProfile(withAuth(limited, keys)), cfg.AllowedOrigins), basePath)
+	var tlsCfg *tls.Config
+	var certManager *autocert.Manager
+	if cfg.HttpsAddr != "" {
+		// extract the host names for the certificate host policy
+		hostNames, wwwRedirects := certificateHosts(cfg)
+		if cfg.CertFile == "" {
+			if certManager, err = newCertManager(cfg, hostNames); err != nil {
+				return nil, err
+			}
+		}
+		if tlsCfg, err = tlsConfig(cfg, certManager); err != nil {
+			return nil, err
+		}
+		// implicit www->non-www redirect(s)
+		handler = withWwwRedirect(handler, wwwRedirects)
+	}
 
 	// base request context used for cancelling long running requests
 	// like the SSE connections
 	baseCtx, cancelBaseCtx := context.WithCancel(context.Background())
 	defer cancelBaseCtx()
 
+	readTimeout := cfg.ReadTimeout
+	if readTimeout == 0 {
+		readTimeout = 10 * time.Minute
+	}
 	server := &http.Server{
-		// TLSConfig: &tls.Config{
-		// 	MinVersion:     tls.VersionTLS12,
-		// 	GetCertificate: certManager.GetCertificate,
-		// 	NextProtos:     []string{acme.ALPNProto},
-		// },
-		Handler:           adaptor.FiberApp(app),
-		ReadTimeout:       10 * time.Minute,
+		TLSConfig:         tlsCfg,
+		Handler:           handler,
+		ReadTimeout:       readTimeout,
 		ReadHeaderTimeout: 30 * time.Second,
-		// WriteTimeout: 60 * time.Second, // breaks sse!
-		Addr: mainAddr,
+		// breaks sse and the streams when it's set
+		WriteTimeout: cfg.WriteTimeout,
+		Addr:         mainAddr,
 		BaseContext: func(l net.Listener) context.Context {
 			return baseCtx
 		},
 	}
+	server.RegisterOnShutdown(func() { close(shutdown) })
+
+	// if httpAddr is set, start an HTTP server to redirect the traffic to the HTTPS version
+	var redirectServer *http.Server
+	if cfg.HttpsAddr != "" && cfg.HttpAddr != "" {
+		redirect := httpsRedirect(cfg.HttpsAddr)
+		if certManager != nil {
+			// answers the http-01 challenges and redirects the rest
+			redirect = certManager.HTTPHandler(redirect)
+		}
+		redirectServer = &http.Server{
+			Addr:              cfg.HttpAddr,
+			Handler:           redirect,
+			ReadHeaderTimeout: 30 * time.Second,
+		}
+	}
 
 	if cfg.ShowStartBanner {
 		schema := "http"
 		addr := server.Addr
 
-		// if cfg.HttpsAddr != "" {
-		// 	schema = "https"
+		if cfg.HttpsAddr != "" {
+			schema = "https"
 
-		// 	if len(cfg.CertificateDomains) > 0 {
-		// 		addr = cfg.CertificateDomains[0]
-		// 	}
-		// }
+			if len(cfg.CertificateDomains) > 0 {
+				addr = cfg.CertificateDomains[0]
+				if _, port, _ := net.SplitHostPort(cfg.HttpsAddr); port != "" && port != "443" {
+					addr = net.JoinHostPort(addr, port)
+				}
+			}
+		}
 
 		date := new(strings.Builder)
 		log.New(date, "", log.LstdFlags).Print()
@@ -147,19 +228,39 @@ func Serve(cfg *ServerConfig) (*http.Server, error) {
 		bold.Printf(
 			"%s Server started at %s\n",
 			strings.TrimSpace(date.String()),
-			color.CyanString("%s://%s", schema, addr),
+			color.CyanString("%s://%s", schema, addr+basePath),
 		)
 
 		regular := color.New()
-		regular.Printf("├─ REST API: %s\n", color.CyanString("%s://%s/api/", schema, addr))
-		regular.Printf("├─ Anime Results API: %s\n", color.CyanString("%s://%s/api/ani-results", schema, addr))
-		regular.Printf("├─ Anime Episodes API: %s\n", color.CyanString("%s://%s/api/ani-episodes", schema, addr))
+		if !cfg.PartyOnly {
+			regular.Printf("├─ Web UI: %s\n", color.CyanString("%s://%s/", schema, addr+basePath))
+			regular.Printf("├─ REST API: %s\n", color.CyanString("%s://%s/api/", schema, addr+basePath))
+			regular.Printf("├─ REST API v1: %s\n", color.CyanString("%s://%s/api/v1/", schema, addr+basePath))
+			regular.Printf("├─ API docs: %s\n", color.CyanString("%s://%s/api/docs", schema, addr+basePath))
+			regular.Printf("├─ Anime Results API: %s\n", color.CyanString("%s://%s/api/ani-results", schema, addr+basePath))
+			regular.Printf("├─ Anime Episodes API: %s\n", color.CyanString("%s://%s/api/ani-episodes", schema, addr+basePath))
+			regular.Printf("├─ Events: %s\n", color.CyanString("%s://%s/api/events", schema, addr+basePath))
+		}
+		regular.Printf("├─ Stream proxy: %s\n", color.CyanString("%s://%s/stream/:animeId/:episode", schema, addr+basePath))
+		if cfg.Party != nil {
+			regular.Printf("├─ Watch party: %s\n", color.CyanString("ani-ar party join %s", addr))
+		}
+		if keys.Enabled() {
+			regular.Printf("├─ Auth: %s\n", color.CyanString("api keys (%d)", len(keys.List())))
+		} else {
+			regular.Printf("├─ Auth: %s\n", color.YellowString("disabled, create an api key with `ani-ar apikey create` to enable it"))
+		}
+		if redirectServer != nil {
+			regular.Printf("├─ HTTPS redirect: %s\n", color.CyanString("http://%s", redirectServer.Addr))
+		}
 	}
 
 	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 
+	shutdownDone := make(chan struct{})
 	go func() {
+		defer close(shutdownDone)
 		_ = <-c
 		// wait for execve and other handlers up to 5 seconds before exit
 		ttw := cfg.TimeToWaitBeforeGracefulShutdown // time to wait
@@ -167,25 +268,68 @@ func Serve(cfg *ServerConfig) (*http.Server, error) {
 			ttw = time.Second * 5
 		}
 		fmt.Printf("Gracefully shutting down..., waiting %v seconds\n", ttw.Seconds())
-		time.AfterFunc(ttw, func() {
-			server.Shutdown(baseCtx)
-		})
+		time.Sleep(ttw)
+
+		shutdownTimeout := cfg.ShutdownTimeout
+		if shutdownTimeout == 0 {
+			shutdownTimeout = 10 * time.Second
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if redirectServer != nil {
+			redirectServer.Shutdown(ctx)
+		}
+		if err := server.Shutdown(ctx); err != nil {
+			// the streams and the long running requests are still open
+			cancelBaseCtx()
+			server.Close()
+		}
 	}()
 
 	// @todo consider removing the server return value because it is
 	// not really useful when combined with the blocking serve calls
 	// ---
 
-	// start HTTPS server
-	// if cfg.HttpsAddr != "" {
-	// 	// if httpAddr is set, start an HTTP server to redirect the traffic to the HTTPS version
-	// 	if cfg.HttpAddr != "" {
-	// 		go http.ListenAndServe(cfg.HttpAddr, certManager.HTTPHandler(nil))
-	// 	}
+	if cfg.HttpsAddr != "" {
+		// start HTTPS server
+		if redirectServer != nil {
+			go func() {
+				if err := redirectServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					log.Println("the https redirect server stopped, reason: " + err.Error())
+				}
+			}()
+		}
 
-	// 	return server, server.ListenAndServeTLS("", "")
-	// }
+		err = server.ListenAndServeTLS("", "")
+	} else {
+		// OR start HTTP server
+		err = server.ListenAndServe()
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		// the open requests are being finished
+		<-shutdownDone
+		return server, nil
+	}
+	return server, err
+}
 
-	// OR start HTTP server
-	return server, server.ListenAndServe()
+// newApp returns the fiber app of the api and the web ui
+func newApp(cfg *ServerConfig) (*fiber.App, error) {
+	app := InitApp(cfg.TrustedProxies)
+	InitiateV1Routes(app)
+	InitiateOpenapiRoutes(app)
+	InitiateRoutes(app)
+	InitiateWatchlistRoutes(app)
+	InitiateProfileRoutes(app)
+	// the jobs are kept across restarts and the interrupted ones resume
+	downloads, err := download.LoadQueue(config.Path("downloads.json"), download.GetDownloader(-1), download.DefaultDir(), nil)
+	if err != nil {
+		return nil, err
+	}
+	InitiateDownloadRoutes(app, downloads)
+	for _, routes := range cfg.Routes {
+		routes(app)
+	}
+	InitiateWebRoutes(app)
+	return app, nil
 }
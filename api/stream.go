@@ -0,0 +1,271 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ani/ani-ar/apikey"
+	"github.com/ani/ani-ar/cache"
+	"github.com/ani/ani-ar/fetcher"
+	"github.com/ani/ani-ar/profile"
+	"github.com/ani/ani-ar/types"
+)
+
+// playlists are small, anything bigger than this is not a playlist
+const maxPlaylistSize = 5 << 20
+
+// headers forwarded from the player to the video host
+var forwardedRequestHeaders = []string{"Range", "If-Range", "If-Modified-Since", "If-None-Match"}
+
+// headers forwarded from the video host back to the player
+var forwardedResponseHeaders = []string{
+	"Content-Type",
+	"Content-Length",
+	"Content-Range",
+	"Accept-Ranges",
+	"Last-Modified",
+	"ETag",
+}
+
+var playlistUriAttrRe = regexp.MustCompile(`URI="([^"]+)"`)
+
+// StreamProxy resolves episodes through the fetcher at request time and
+// proxies the video bytes, so players get a stable local url
+type StreamProxy struct {
+	// the fetcher of the request profile is used when it's nil
+	fetcher fetcher.Fetcher
+	// key used to sign the rewritten hls urls, so the proxy can't be
+	// used to fetch arbitrary urls
+	key []byte
+}
+
+func NewStreamProxy(f fetcher.Fetcher) *StreamProxy {
+	key := make([]byte, 32)
+	rand.Read(key)
+	return &StreamProxy{
+		fetcher: f,
+		key:     key,
+	}
+}
+
+// RegisterRoutes adds the stream routes to a net/http mux, fiber buffers the
+// whole response body so the stream can't go through the fiber app
+func (s *StreamProxy) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET "+streamUrl, s.handleStream)
+	mux.HandleFunc("GET "+streamHlsUrl, s.handleHls)
+}
+
+// StreamUrl returns the proxy url of an episode on the given server base url (eg. `http://127.0.0.1:8000`)
+func StreamUrl(serverUrl, animeId string, episode int) string {
+	return fmt.Sprintf("%s%s/%s/%d", strings.TrimSuffix(serverUrl, "/"), streamBaseUrl, url.PathEscape(animeId), episode)
+}
+
+// StartStreamProxy serves the stream proxy of the fetcher alone on addr in the
+// background, it's used to play through the proxy without running the whole
+// api server
+func StartStreamProxy(addr string, f fetcher.Fetcher) (net.Listener, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	NewStreamProxy(f).RegisterRoutes(mux)
+	go http.Serve(l, mux)
+	return l, nil
+}
+
+// the fetcher and the preferred quality of the request profile
+func (s *StreamProxy) profileOf(r *http.Request) (fetcher.Fetcher, string) {
+	name := requestProfileOf(r)
+	if s.fetcher != nil {
+		return s.fetcher, profile.GetSettings(name).Quality
+	}
+	return fetcher.GetProfileFetcher(name), profile.GetSettings(name).Quality
+}
+
+// resolveVideo returns the video of the episode, its sources are kept in the
+// cache for the Stream ttl since the players request it again for every range.
+// They are cached without the resolution so any quality is picked from them
+func (s *StreamProxy) resolveVideo(f fetcher.Fetcher, animeId string, episodeNum int, res string) (*types.AniVideo, error) {
+	cacheKey := fmt.Sprintf("%s:%s:%d", fetcher.GetFetcherKey(f), animeId, episodeNum)
+	medias, err := cache.Fetch(cache.Stream, cacheKey, func() ([]types.AniVideo, error) {
+		anime := f.GetAnimeResult(animeId)
+		if anime == nil {
+			return nil, errors.New("anime not found")
+		}
+		episodes := f.GetEpisodes(*anime)
+		if episodeNum < 1 || episodeNum > len(episodes) {
+			return nil, errors.New("episode out of range")
+		}
+		medias := episodes[episodeNum-1].GetPlayersWithQuality()
+		if len(medias) == 0 {
+			return nil, errors.New("no video sources found for the episode")
+		}
+		return medias, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return types.SelectVideo(medias, res), nil
+}
+
+func (s *StreamProxy) sign(u string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(u))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *StreamProxy) parseEpisodeParams(w http.ResponseWriter, r *http.Request) (*types.AniVideo, bool) {
+	animeId := r.PathValue("animeId")
+	episodeNum, err := strconv.Atoi(r.PathValue("episode"))
+	if err != nil {
+		http.Error(w, "invalid episode number", http.StatusBadRequest)
+		return nil, false
+	}
+	f, quality := s.profileOf(r)
+	if res := r.URL.Query().Get("res"); res != "" {
+		quality = res
+	}
+	video, err := s.resolveVideo(f, animeId, episodeNum, quality)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return nil, false
+	}
+	return video, true
+}
+
+func (s *StreamProxy) handleStream(w http.ResponseWriter, r *http.Request) {
+	video, ok := s.parseEpisodeParams(w, r)
+	if !ok {
+		return
+	}
+	s.proxy(w, r, video, video.Src)
+}
+
+func (s *StreamProxy) handleHls(w http.ResponseWriter, r *http.Request) {
+	target := r.URL.Query().Get("u")
+	if target == "" || !hmac.Equal([]byte(s.sign(target)), []byte(r.URL.Query().Get("sig"))) {
+		http.Error(w, "invalid stream url signature", http.StatusForbidden)
+		return
+	}
+	video, ok := s.parseEpisodeParams(w, r)
+	if !ok {
+		return
+	}
+	s.proxy(w, r, video, target)
+}
+
+func (s *StreamProxy) proxy(w http.ResponseWriter, r *http.Request, video *types.AniVideo, target string) {
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, target, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	for _, h := range forwardedRequestHeaders {
+		if v := r.Header.Get(h); v != "" {
+			req.Header.Set(h, v)
+		}
+	}
+	for k, v := range video.Headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer res.Body.Close()
+
+	if isPlaylist(res) {
+		s.writePlaylist(w, r, res)
+		return
+	}
+
+	for _, h := range forwardedResponseHeaders {
+		if v := res.Header.Get(h); v != "" {
+			w.Header().Set(h, v)
+		}
+	}
+	w.WriteHeader(res.StatusCode)
+	_, err = io.Copy(w, res.Body)
+	if err != nil && r.Context().Err() == nil {
+		log.Printf("[stream] error while proxying %s: %v\n", r.URL.Path, err)
+	}
+}
+
+func isPlaylist(res *http.Response) bool {
+	contentType := strings.ToLower(res.Header.Get("Content-Type"))
+	if strings.Contains(contentType, "mpegurl") {
+		return true
+	}
+	return strings.HasSuffix(strings.ToLower(res.Request.URL.Path), ".m3u8")
+}
+
+func (s *StreamProxy) writePlaylist(w http.ResponseWriter, r *http.Request, res *http.Response) {
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxPlaylistSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	hlsPath := fmt.Sprintf("%s%s/%s/%s/hls", basePathOf(r), streamBaseUrl, url.PathEscape(r.PathValue("animeId")), r.PathValue("episode"))
+	quality := r.URL.Query().Get("res")
+
+	proxied := func(ref string) string {
+		abs, err := res.Request.URL.Parse(ref)
+		if err != nil {
+			return ref
+		}
+		q := url.Values{}
+		q.Set("u", abs.String())
+		q.Set("sig", s.sign(abs.String()))
+		if quality != "" {
+			q.Set("res", quality)
+		}
+		// the players don't send the profile header again for the segments
+		if name := r.Header.Get(profile.Header); name != "" {
+			q.Set("profile", name)
+		}
+		// the key isn't written in the playlist, the segments get a token of the episode
+		if key, found := apikey.GetStore().Get(r.Header.Get(apiKeyIdHeader)); found {
+			q.Set(StreamTokenQuery, newStreamToken(key.Id, key.Hash, streamHlsPath(r.PathValue("animeId"), r.PathValue("episode")), streamTokenTTL))
+		}
+		return hlsPath + "?" + q.Encode()
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+	w.WriteHeader(res.StatusCode)
+	io.WriteString(w, rewritePlaylist(string(body), proxied))
+}
+
+// rewrites every uri in the playlist (segments, keys, variant playlists...)
+func rewritePlaylist(playlist string, proxied func(string) string) string {
+	lines := strings.Split(playlist, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			lines[i] = playlistUriAttrRe.ReplaceAllStringFunc(line, func(attr string) string {
+				uri := playlistUriAttrRe.FindStringSubmatch(attr)[1]
+				return `URI="` + proxied(uri) + `"`
+			})
+			continue
+		}
+		lines[i] = proxied(trimmed)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/ani/ani-ar/cache"
+)
+
+func TestResolvedVideosAreCached(t *testing.T) {
+	cache.SetDefault(cache.New(cache.NewMemory()))
+	t.Cleanup(func() { cache.SetDefault(cache.New(cache.NewMemory())) })
+	f := &fakeFetcher{src: "https://example.com/naruto-1.mp4"}
+	s := NewStreamProxy(f)
+
+	// the other resolutions are picked from the cached sources
+	for _, res := range []string{"1080", "1080", "720", "480"} {
+		video, err := s.resolveVideo(f, "naruto", 1, res)
+		if err != nil || video.Src != f.src {
+			t.Fatalf("got %v, %v", video, err)
+		}
+	}
+	if n := f.calls.Load(); n != 1 {
+		t.Fatalf("the episode was resolved %d times, want 1", n)
+	}
+
+	if _, err := s.resolveVideo(f, "naruto", 3, "1080"); err == nil {
+		t.Fatal("the missing episode should fail")
+	}
+	// the failures aren't cached
+	s.resolveVideo(f, "naruto", 3, "1080")
+	if n := f.calls.Load(); n != 3 {
+		t.Fatalf("the missing episode was resolved %d times, want 2", n-1)
+	}
+}
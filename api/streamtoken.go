@@ -0,0 +1,81 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ani/ani-ar/apikey"
+)
+
+// the rewritten hls playlists link their segments with a stream token instead
+// of the api key, the token only opens the hls route of the episode and
+// expires once the episode should be over. the other tokens open the episode
+// itself
+const (
+	StreamTokenQuery = "st"
+	streamTokenTTL   = 6 * time.Hour
+)
+
+var errInvalidStreamToken = errors.New("the stream token is invalid or expired")
+
+// streamPath returns the path of the episode in the stream proxy
+func streamPath(animeId, episode string) string {
+	return fmt.Sprintf("%s/%s/%s", streamBaseUrl, animeId, episode)
+}
+
+// streamHlsPath returns the path the segment tokens of the episode are valid for
+func streamHlsPath(animeId, episode string) string {
+	return streamPath(animeId, episode) + "/hls"
+}
+
+// the tokens are signed with the hash of their key, so any process knowing
+// the key can give them and they end once the key is revoked
+func signStreamToken(secretHash, keyId, path string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(secretHash))
+	fmt.Fprintf(mac, "%s\n%s\n%d", keyId, path, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newStreamToken returns a token of the key for the path
+func newStreamToken(keyId, secretHash, path string, ttl time.Duration) string {
+	expires := time.Now().Add(ttl).Unix()
+	return fmt.Sprintf("%s.%d.%s", keyId, expires, signStreamToken(secretHash, keyId, path, expires))
+}
+
+// NewStreamToken returns a token of the api key that only opens the episode in
+// the stream proxy, it's sent in the StreamTokenQuery parameter by the links
+// that are written down (eg. the jellyfin .strm files) instead of the key
+func NewStreamToken(apiKey, animeId string, episode int, ttl time.Duration) (string, error) {
+	keyId, secretHash, err := apikey.ParseToken(apiKey)
+	if err != nil {
+		return "", err
+	}
+	return newStreamToken(keyId, secretHash, streamPath(animeId, strconv.Itoa(episode)), ttl), nil
+}
+
+// authenticateStreamToken returns the key of the token when it's valid for the
+// path, the key should still exist with the stream scope
+func authenticateStreamToken(keys *apikey.Store, token, path string) (apikey.Key, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return apikey.Key{}, errInvalidStreamToken
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return apikey.Key{}, errInvalidStreamToken
+	}
+	key, found := keys.Get(parts[0])
+	if !found || !key.HasScope(apikey.ScopeStream) {
+		return apikey.Key{}, errInvalidStreamToken
+	}
+	if !hmac.Equal([]byte(signStreamToken(key.Hash, key.Id, path, expires)), []byte(parts[2])) {
+		return apikey.Key{}, errInvalidStreamToken
+	}
+	return key, nil
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ani/ani-ar/apikey"
+	"github.com/ani/ani-ar/types"
+)
+
+// fakeFetcher has an anime whose episodes play the video of src
+type fakeFetcher struct {
+	src string
+	// the number of GetAnimeResult calls
+	calls atomic.Int32
+}
+
+func (f *fakeFetcher) Search(q string) []types.AniResult { return nil }
+
+func (f *fakeFetcher) GetAnimeResult(id string) *types.AniResult {
+	f.calls.Add(1)
+	return &types.AniResult{Id: id, DisplayName: id, Episodes: 2}
+}
+
+func (f *fakeFetcher) GetEpisodes(anime types.AniResult) []types.AniEpisode {
+	var episodes []types.AniEpisode
+	for number := 1; number <= anime.Episodes; number++ {
+		episodes = append(episodes, types.AniEpisode{
+			Anime:  anime,
+			Number: number,
+			GetPlayersWithQuality: func() []types.AniVideo {
+				return []types.AniVideo{{Src: f.src, Res: "1080"}}
+			},
+		})
+	}
+	return episodes
+}
+
+func get(t *testing.T, handler http.Handler, target string) (int, string) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+	b, _ := io.ReadAll(w.Body)
+	return w.Code, string(b)
+}
+
+func TestHlsSegmentsGetAStreamToken(t *testing.T) {
+	t.Setenv("ANI_AR_CONFIG_DIR", t.TempDir())
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/master.m3u8" {
+			w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+			io.WriteString(w, "#EXTM3U\n#EXTINF:10,\nsegment1.ts\n")
+			return
+		}
+		io.WriteString(w, "segment")
+	}))
+	defer upstream.Close()
+
+	keys := apikey.GetStore()
+	token, key, err := keys.Create("tv", []string{apikey.ScopeStream})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	NewStreamProxy(&fakeFetcher{src: upstream.URL + "/master.m3u8"}).RegisterRoutes(mux)
+	handler := withAuth(mux, keys)
+
+	status, playlist := get(t, handler, "/stream/naruto/1?api_key="+token)
+	if status != 200 {
+		t.Fatalf("the playlist should be served, got %d %s", status, playlist)
+	}
+	if strings.Contains(playlist, token) || strings.Contains(playlist, apiKeyQuery+"=") {
+		t.Fatalf("the playlist shouldn't have the api key:\n%s", playlist)
+	}
+	segment := strings.Split(strings.TrimSpace(playlist), "\n")[2]
+	if !strings.Contains(segment, StreamTokenQuery+"=") {
+		t.Fatalf("the segment should have a stream token: %s", segment)
+	}
+	if status, body := get(t, handler, segment); status != 200 || body != "segment" {
+		t.Fatalf("the segment should be served with the token, got %d %s", status, body)
+	}
+
+	// the token only opens the episode it was given for
+	other := strings.Replace(segment, "/stream/naruto/1/hls", "/stream/naruto/2/hls", 1)
+	if status, _ := get(t, handler, other); status != http.StatusUnauthorized {
+		t.Fatalf("the token of another episode should be refused, got %d", status)
+	}
+	// nor another route
+	tokenValue := segment[strings.Index(segment, StreamTokenQuery+"=")+len(StreamTokenQuery)+1:]
+	if status, _ := get(t, handler, "/stream/naruto/1?"+StreamTokenQuery+"="+tokenValue); status != http.StatusUnauthorized {
+		t.Fatalf("the token should only open the hls route, got %d", status)
+	}
+
+	// the token ends with its key
+	if err := keys.Revoke(key.Id); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := keys.Create("other", []string{apikey.ScopeRead}); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := get(t, handler, segment); status != http.StatusUnauthorized {
+		t.Fatalf("the token of a revoked key should be refused, got %d", status)
+	}
+}
+
+func TestStreamTokenExpires(t *testing.T) {
+	path := streamHlsPath("naruto", "1")
+	expired := "abcd.1." + signStreamToken("hash", "abcd", path, 1)
+	if _, err := authenticateStreamToken(nil, expired, path); err != errInvalidStreamToken {
+		t.Fatalf("got %v, want errInvalidStreamToken", err)
+	}
+}
+
+func TestEpisodeStreamToken(t *testing.T) {
+	t.Setenv("ANI_AR_CONFIG_DIR", t.TempDir())
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "video")
+	}))
+	defer upstream.Close()
+
+	keys := apikey.GetStore()
+	apiKey, _, err := keys.Create("jellyfin", []string{apikey.ScopeStream})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	NewStreamProxy(&fakeFetcher{src: upstream.URL + "/video.mp4"}).RegisterRoutes(mux)
+	handler := withAuth(mux, keys)
+
+	token, err := NewStreamToken(apiKey, "naruto", 1, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(token, apiKey) {
+		t.Fatalf("the token shouldn't have the api key: %s", token)
+	}
+	if status, body := get(t, handler, "/stream/naruto/1?"+StreamTokenQuery+"="+token); status != 200 || body != "video" {
+		t.Fatalf("the episode should be served with the token, got %d %s", status, body)
+	}
+	if status, _ := get(t, handler, "/stream/naruto/2?"+StreamTokenQuery+"="+token); status != http.StatusUnauthorized {
+		t.Fatalf("the token of another episode should be refused, got %d", status)
+	}
+	if _, err := NewStreamToken("not-a-key", "naruto", 1, time.Hour); err == nil {
+		t.Fatal("a token shouldn't be given for an invalid api key")
+	}
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/ani/ani-ar/config"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+var ErrNoCertificateDomains = errors.New("the automatic certificates need a domain, set it with --domain or in the https address")
+
+// certificateHosts returns the host names of the certificate with a www entry
+// for each non-www domain, and the www entries that should redirect to it
+func certificateHosts(cfg *ServerConfig) (hostNames, wwwRedirects []string) {
+	hostNames = slices.Clone(cfg.CertificateDomains)
+	if len(hostNames) == 0 {
+		// acme doesn't issue certificates for ips
+		if host, _, err := net.SplitHostPort(cfg.HttpsAddr); err == nil && host != "" && net.ParseIP(host) == nil {
+			hostNames = append(hostNames, host)
+		}
+	}
+	for _, host := range hostNames {
+		// explicitly set www host, or a host that can't have a www entry
+		if strings.HasPrefix(host, "www.") || net.ParseIP(host) != nil || !strings.Contains(host, ".") {
+			continue
+		}
+		wwwHost := "www." + host
+		if !slices.Contains(hostNames, wwwHost) {
+			hostNames = append(hostNames, wwwHost)
+			wwwRedirects = append(wwwRedirects, wwwHost)
+		}
+	}
+	return hostNames, wwwRedirects
+}
+
+// newCertManager returns the acme manager issuing the certificates of the
+// hosts, they are cached in the config folder unless another cache is set
+func newCertManager(cfg *ServerConfig, hostNames []string) (*autocert.Manager, error) {
+	if len(hostNames) == 0 {
+		return nil, ErrNoCertificateDomains
+	}
+	cacheDir := cfg.CertificateCacheDir
+	if cacheDir == "" {
+		cacheDir = config.Path(".autocert_cache")
+	}
+	m := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      autocert.DirCache(cacheDir),
+		HostPolicy: autocert.HostWhitelist(hostNames...),
+		Email:      cfg.AcmeEmail,
+	}
+	if cfg.AcmeDirectoryUrl != "" {
+		m.Client = &acme.Client{DirectoryURL: cfg.AcmeDirectoryUrl}
+	}
+	return m, nil
+}
+
+// tlsConfig returns the tls config of the https server, it uses the
+// certificate files when they are set and the acme manager otherwise
+func tlsConfig(cfg *ServerConfig, certManager *autocert.Manager) (*tls.Config, error) {
+	c := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"h2", "http/1.1"},
+	}
+	if certManager != nil {
+		c.GetCertificate = certManager.GetCertificate
+		// answers the tls-alpn-01 challenges on the https port
+		c.NextProtos = append(c.NextProtos, acme.ALPNProto)
+		return c, nil
+	}
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, errors.New("couldn't load the certificate, reason: " + err.Error())
+	}
+	c.Certificates = []tls.Certificate{cert}
+	return c, nil
+}
+
+// withWwwRedirect redirects the www hosts to their non-www host
+func withWwwRedirect(next http.Handler, wwwRedirects []string) http.Handler {
+	if len(wwwRedirects) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, port, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host, port = r.Host, ""
+		}
+		if slices.Contains(wwwRedirects, host) {
+			target := host[4:]
+			if port != "" {
+				target = net.JoinHostPort(target, port)
+			}
+			http.Redirect(w, r, "https://"+target+r.RequestURI, http.StatusTemporaryRedirect)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// httpsRedirect redirects the plain http requests to the https server
+func httpsRedirect(httpsAddr string) http.Handler {
+	_, httpsPort, _ := net.SplitHostPort(httpsAddr)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if httpsPort != "" && httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+		http.Redirect(w, r, "https://"+host+r.RequestURI, http.StatusPermanentRedirect)
+	})
+}
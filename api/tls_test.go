@@ -0,0 +1,179 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// writeSelfSignedCertificate writes a certificate of localhost and its key in
+// the folder and returns their files and the certificate
+func writeSelfSignedCertificate(t *testing.T, dir string) (certFile, keyFile string, cert *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert, err = x509.ParseCertificate(der); err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile, cert
+}
+
+func TestTlsConfigServesTheCertificateFiles(t *testing.T) {
+	certFile, keyFile, cert := writeSelfSignedCertificate(t, t.TempDir())
+	c, err := tlsConfig(&ServerConfig{CertFile: certFile, KeyFile: keyFile}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.GetCertificate != nil || slices.Contains(c.NextProtos, "acme-tls/1") {
+		t.Fatal("the certificate files shouldn't answer the acme challenges")
+	}
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.Proto)
+	}))
+	srv.TLS = c
+	// the handshake of the untrusting client below fails
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: roots},
+		ForceAttemptHTTP2: true,
+	}}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(resp.Body)
+	if string(b) != "HTTP/2.0" {
+		t.Fatalf("the server should speak http/2, got %s", b)
+	}
+	if peer := resp.TLS.PeerCertificates[0]; !peer.Equal(cert) {
+		t.Fatalf("the server should present the certificate file, got %v", peer.Subject)
+	}
+	if resp.TLS.Version < tls.VersionTLS12 {
+		t.Fatalf("got tls version %x", resp.TLS.Version)
+	}
+
+	// a client that doesn't trust the certificate
+	if _, err := http.Get(srv.URL); err == nil {
+		t.Fatal("the self-signed certificate shouldn't be trusted by default")
+	}
+}
+
+func TestTlsConfigWithoutTheCertificateFiles(t *testing.T) {
+	dir := t.TempDir()
+	_, err := tlsConfig(&ServerConfig{CertFile: filepath.Join(dir, "cert.pem"), KeyFile: filepath.Join(dir, "key.pem")}, nil)
+	if err == nil {
+		t.Fatal("the missing certificate should be an error")
+	}
+}
+
+func TestCertManagerOnlyIssuesTheHosts(t *testing.T) {
+	cfg := &ServerConfig{HttpsAddr: "ani.example.com:443", CertificateCacheDir: t.TempDir()}
+	hostNames, wwwRedirects := certificateHosts(cfg)
+	if !slices.Equal(hostNames, []string{"ani.example.com", "www.ani.example.com"}) || !slices.Equal(wwwRedirects, []string{"www.ani.example.com"}) {
+		t.Fatalf("got the hosts %v and the redirects %v", hostNames, wwwRedirects)
+	}
+	m, err := newCertManager(cfg, hostNames)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, host := range hostNames {
+		if err := m.HostPolicy(context.Background(), host); err != nil {
+			t.Errorf("%s should be allowed, got %v", host, err)
+		}
+	}
+	for _, host := range []string{"evil.com", "example.com", "api.ani.example.com", "127.0.0.1"} {
+		if err := m.HostPolicy(context.Background(), host); err == nil {
+			t.Errorf("%s shouldn't be allowed", host)
+		}
+	}
+	// the certificate isn't requested for the other hosts
+	if _, err := m.GetCertificate(&tls.ClientHelloInfo{ServerName: "evil.com"}); err == nil {
+		t.Fatal("the certificate of another host shouldn't be issued")
+	}
+
+	// acme doesn't issue certificates for ips
+	hostNames, _ = certificateHosts(&ServerConfig{HttpsAddr: "127.0.0.1:443"})
+	if _, err := newCertManager(cfg, hostNames); err != ErrNoCertificateDomains {
+		t.Fatalf("got %v, want no domains", err)
+	}
+}
+
+// request returns the request of the url as the server gets it
+func request(url string) *http.Request {
+	r := httptest.NewRequest("GET", url, nil)
+	r.RequestURI = r.URL.RequestURI()
+	return r
+}
+
+func TestWwwRedirect(t *testing.T) {
+	handler := withWwwRedirect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}), []string{"www.ani.example.com"})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, request("https://www.ani.example.com:8443/api/docs?a=1"))
+	if w.Code != http.StatusTemporaryRedirect || w.Header().Get("Location") != "https://ani.example.com:8443/api/docs?a=1" {
+		t.Fatalf("got %d %s", w.Code, w.Header().Get("Location"))
+	}
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, request("https://ani.example.com/api/docs"))
+	if w.Code != 200 {
+		t.Fatalf("the other hosts should be served, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	httpsRedirect(":8443").ServeHTTP(w, request("http://ani.example.com/stream/1/2"))
+	if w.Code != http.StatusPermanentRedirect || w.Header().Get("Location") != "https://ani.example.com:8443/stream/1/2" {
+		t.Fatalf("got %d %s", w.Code, w.Header().Get("Location"))
+	}
+}
@@ -13,3 +13,61 @@ const (
 
 	AddJellyfinAnime = baseUrl + "/add/:animeId"
 )
+
+// the jellyfin routes are registered by the jellyfin package, see ServerConfig.Routes
+const (
+	JellyfinBaseUrl    = baseUrl + "/jellyfin"
+	JellyfinItemUrl    = JellyfinBaseUrl + "/items/:animeId"
+	JellyfinItemsUrl   = JellyfinBaseUrl + "/items"
+	JellyfinRefreshUrl = JellyfinBaseUrl + "/refresh"
+	JellyfinStatusUrl  = JellyfinBaseUrl + "/status"
+)
+
+// stream routes are served by net/http (see StreamProxy) so they use its pattern syntax
+const (
+	streamBaseUrl = "/stream"
+	streamUrl     = streamBaseUrl + "/{animeId}/{episode}"
+	streamHlsUrl  = streamUrl + "/hls"
+
+	// the server-sent events, see eventsHandler
+	eventsUrl = baseUrl + "/events"
+)
+
+const (
+	profilesBaseUrl = baseUrl + "/profiles"
+	settingsUrl     = baseUrl + "/settings"
+	// every route is also served under it for the profile, see withProfile
+	profilesPathPrefix = "/profiles/"
+)
+
+// the v1 routes answer with the {"data": ...} and {"error": {...}} envelopes, the
+// watchlist, profiles, settings, jellyfin and downloads routes are served under it too, see v1Envelope
+const (
+	v1BaseUrl      = baseUrl + "/v1"
+	v1AnimeUrl     = v1BaseUrl + "/anime"
+	v1AnimeByIdUrl = v1AnimeUrl + "/:animeId"
+	v1EpisodesUrl  = v1AnimeByIdUrl + "/episodes"
+	v1EpisodeUrl   = v1EpisodesUrl + "/:episodeNum"
+	openapiUrl     = baseUrl + "/openapi.json"
+	docsUrl        = baseUrl + "/docs"
+	// the swagger ui files of the docs
+	swaggerUiUrl = baseUrl + "/swagger-ui"
+)
+
+// the web ui, see InitiateWebRoutes
+const (
+	webUrl       = "/"
+	webAssetsUrl = "/web"
+)
+
+const (
+	watchlistBaseUrl  = baseUrl + "/watchlist"
+	watchlistEntryUrl = watchlistBaseUrl + "/:source/:animeId"
+)
+
+const (
+	downloadsBaseUrl  = baseUrl + "/downloads"
+	downloadUrl       = downloadsBaseUrl + "/:jobId"
+	downloadPauseUrl  = downloadUrl + "/pause"
+	downloadResumeUrl = downloadUrl + "/resume"
+)
@@ -0,0 +1,248 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/ani/ani-ar/cache"
+	"github.com/ani/ani-ar/fetcher"
+	"github.com/ani/ani-ar/types"
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	ErrMissingQuery     = errors.New("the search query q is required")
+	ErrInvalidEpisode   = errors.New("invalid episode number")
+	ErrEpisodeNotFound  = errors.New("episode not found")
+	errV1RouteNotFound  = errors.New("route not found")
+	errV1InternalServer = errors.New("internal server error")
+)
+
+// Anime is an anime of the fetcher, the MyAnimeList details are only set when
+// the anime is requested by id and they match it
+type Anime struct {
+	Id       string          `json:"id"`
+	Title    string          `json:"title"`
+	Episodes int             `json:"episodes"`
+	Cover    string          `json:"cover"`
+	Source   string          `json:"source"`
+	Details  *JikanAnimeInfo `json:"details,omitempty"`
+}
+
+// Episode is an episode with its MyAnimeList title, airing date and filler/recap flags when they match
+type Episode struct {
+	Number int    `json:"number"`
+	Title  string `json:"title,omitempty"`
+	Aired  string `json:"aired,omitempty"`
+	Filler bool   `json:"filler"`
+	Recap  bool   `json:"recap"`
+}
+
+// EpisodeDetails is an episode with its videos, they expire after a while
+type EpisodeDetails struct {
+	Episode
+	Anime  Anime            `json:"anime"`
+	Videos []types.AniVideo `json:"videos"`
+}
+
+// Error is the error object of the v1 routes
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// the missing scope of the forbidden requests
+	Scope string `json:"scope,omitempty"`
+}
+
+type v1Response struct {
+	Data json.RawMessage `json:"data"`
+}
+
+type v1ErrorResponse struct {
+	Error Error `json:"error"`
+}
+
+// errorCode returns the code of the error objects for the status
+func errorCode(status int) string {
+	switch status {
+	case fiber.StatusBadRequest:
+		return "invalid_request"
+	case fiber.StatusUnauthorized:
+		return "unauthorized"
+	case fiber.StatusForbidden:
+		return "forbidden"
+	case fiber.StatusNotFound:
+		return "not_found"
+	case fiber.StatusMethodNotAllowed:
+		return "method_not_allowed"
+	case fiber.StatusConflict:
+		return "conflict"
+	case fiber.StatusTooManyRequests:
+		return "rate_limited"
+	}
+	if status >= 500 {
+		return "internal_error"
+	}
+	return "error"
+}
+
+func newAnime(f fetcher.Fetcher, anime types.AniResult) Anime {
+	return Anime{
+		Id:       anime.Id,
+		Title:    anime.DisplayName,
+		Episodes: anime.Episodes,
+		Cover:    anime.DisplayCover,
+		Source:   fetcher.GetFetcherName(f),
+	}
+}
+
+func newEpisode(e types.AniEpisode) Episode {
+	return Episode{Number: e.Number, Title: e.Title, Aired: e.Aired, Filler: e.Filler, Recap: e.Recap}
+}
+
+// the routes of these prefixes are shared with the unversioned api
+var v1Aliases = []string{"/watchlist", "/profiles", "/settings", "/jellyfin", "/downloads"}
+
+func isV1Alias(path string) bool {
+	for _, prefix := range v1Aliases {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// v1Envelope serves the shared routes under /api/v1 and wraps the responses of
+// the v1 routes, the json bodies go in {"data": ...} and the errors (the
+// {"message": ...} bodies, the returned errors and the missing routes) in
+// {"error": {"code": ..., "message": ...}}
+func v1Envelope(c *fiber.Ctx) error {
+	// the path is backed by the request buffer that the rewrite changes
+	originalPath := strings.Clone(c.Path())
+	if rest, found := strings.CutPrefix(originalPath, v1BaseUrl); found && isV1Alias(rest) {
+		c.Path(baseUrl + rest)
+	}
+	err := c.Next()
+	// the logger reads the path after the handlers
+	c.Path(originalPath)
+	if err != nil {
+		var fiberErr *fiber.Error
+		switch {
+		case errors.As(err, &fiberErr) && fiberErr.Code == fiber.StatusNotFound:
+			return v1Error(c, fiberErr.Code, errV1RouteNotFound.Error())
+		case errors.As(err, &fiberErr):
+			return v1Error(c, fiberErr.Code, fiberErr.Message)
+		}
+		return v1Error(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	status := c.Response().StatusCode()
+	body := append([]byte(nil), c.Response().Body()...)
+	if status >= 400 {
+		var e struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &e) != nil || e.Message == "" {
+			e.Message = strings.TrimSpace(string(body))
+		}
+		if e.Message == "" {
+			e.Message = errV1InternalServer.Error()
+		}
+		return v1Error(c, status, e.Message)
+	}
+	if status == fiber.StatusNoContent || !json.Valid(body) {
+		return nil
+	}
+	return c.JSON(v1Response{Data: body})
+}
+
+func v1Error(c *fiber.Ctx, status int, message string) error {
+	return c.Status(status).JSON(v1ErrorResponse{Error: Error{Code: errorCode(status), Message: message}})
+}
+
+// InitiateV1Routes registers the envelope of the v1 routes and the anime
+// routes, it should be registered before the shared routes
+func InitiateV1Routes(app *fiber.App) {
+	jikan := GetJikanApi()
+
+	app.Use(v1BaseUrl, v1Envelope)
+
+	app.Get(v1AnimeUrl, withCache(cache.Search, func(c *fiber.Ctx) error {
+		search := strings.TrimSpace(c.Query("q"))
+		if search == "" {
+			return c.Status(400).JSON(map[string]string{"message": ErrMissingQuery.Error()})
+		}
+		f := requestFetcher(c)
+		results := []Anime{}
+		for _, anime := range f.Search(search) {
+			results = append(results, newAnime(f, anime))
+		}
+		return c.JSON(results)
+	}))
+
+	app.Get(v1AnimeByIdUrl, withCache(cache.Anime, func(c *fiber.Ctx) error {
+		f := requestFetcher(c)
+		enhanced, err := GetAnimeEnhancedResults(c.Params("animeId"), f)
+		if err != nil {
+			return animeError(c, err)
+		}
+		anime := newAnime(f, *enhanced.Data)
+		anime.Details = enhanced.Details
+		return c.JSON(anime)
+	}))
+
+	app.Get(v1EpisodesUrl, withCache(cache.Episodes, func(c *fiber.Ctx) error {
+		f := requestFetcher(c)
+		anime := f.GetAnimeResult(c.Params("animeId"))
+		if anime == nil {
+			return animeError(c, ErrAnimeNotFound)
+		}
+		fetcherEpisodes := f.GetEpisodes(*anime)
+		jikan.AddEpisodesDetails(*anime, fetcherEpisodes)
+		episodes := []Episode{}
+		for _, e := range fetcherEpisodes {
+			episodes = append(episodes, newEpisode(e))
+		}
+		return c.JSON(episodes)
+	}))
+
+	app.Get(v1EpisodeUrl, withCache(cache.Videos, func(c *fiber.Ctx) error {
+		episodeNum, err := strconv.Atoi(c.Params("episodeNum"))
+		if err != nil || episodeNum < 1 {
+			return animeError(c, ErrInvalidEpisode)
+		}
+		f := requestFetcher(c)
+		anime := f.GetAnimeResult(c.Params("animeId"))
+		if anime == nil {
+			return animeError(c, ErrAnimeNotFound)
+		}
+		episodes := f.GetEpisodes(*anime)
+		if episodeNum > len(episodes) {
+			return animeError(c, ErrEpisodeNotFound)
+		}
+		jikan.AddEpisodesDetails(*anime, episodes)
+		episode := episodes[episodeNum-1]
+		videos := episode.GetPlayersWithQuality()
+		if len(videos) == 0 {
+			// the sources can be down for a while
+			c.Set(fiber.HeaderCacheControl, "no-store")
+			videos = []types.AniVideo{}
+		}
+		return c.JSON(EpisodeDetails{
+			Episode: newEpisode(episode),
+			Anime:   newAnime(f, *anime),
+			Videos:  videos,
+		})
+	}))
+}
+
+func animeError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, ErrAnimeNotFound), errors.Is(err, ErrEpisodeNotFound):
+		return c.Status(404).JSON(map[string]string{"message": err.Error()})
+	case errors.Is(err, ErrInvalidEpisode), errors.Is(err, ErrMissingQuery):
+		return c.Status(400).JSON(map[string]string{"message": err.Error()})
+	}
+	return err
+}
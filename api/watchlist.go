@@ -0,0 +1,135 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/ani/ani-ar/fetcher"
+	"github.com/ani/ani-ar/types"
+	"github.com/ani/ani-ar/watchlist"
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewWatchlistEntry returns the watchlist entry of an anime of the fetcher,
+// the MyAnimeList id is looked up and left empty when there is no match
+func NewWatchlistEntry(f fetcher.Fetcher, anime types.AniResult) watchlist.Entry {
+	return watchlist.Entry{
+		Source: fetcher.GetFetcherName(f),
+		Id:     anime.Id,
+		Title:  anime.DisplayName,
+		MalId:  GetJikanApi().GetMalId(anime.Id),
+	}
+}
+
+// the watchlist of the request profile
+func requestWatchlist(c *fiber.Ctx) (*watchlist.Store, error) {
+	return watchlist.GetProfileStore(requestProfile(c))
+}
+
+func InitiateWatchlistRoutes(app *fiber.App) {
+	app.Get(watchlistBaseUrl, func(c *fiber.Ctx) error {
+		status := c.Query("status")
+		if status != "" && !watchlist.IsValidStatus(status) {
+			return c.Status(400).JSON(map[string]string{"message": watchlist.ErrInvalidStatus.Error()})
+		}
+		store, err := requestWatchlist(c)
+		if err != nil {
+			return err
+		}
+		return c.JSON(store.List(status, c.QueryBool("favorites")))
+	})
+
+	app.Post(watchlistBaseUrl, func(c *fiber.Ctx) error {
+		var body struct {
+			Source   string `json:"source"`
+			Id       string `json:"id"`
+			Status   string `json:"status"`
+			Favorite bool   `json:"favorite"`
+		}
+		if err := c.BodyParser(&body); err != nil || body.Id == "" {
+			return c.Status(400).JSON(map[string]string{"message": "the anime id is required"})
+		}
+		f := requestFetcher(c)
+		if body.Source != "" {
+			var err error
+			if f, err = fetcher.GetFetcherByName(body.Source); err != nil {
+				return c.Status(400).JSON(map[string]string{"message": err.Error()})
+			}
+		}
+		anime := f.GetAnimeResult(body.Id)
+		if anime == nil {
+			return c.Status(404).JSON(map[string]string{"message": "anime not found"})
+		}
+
+		store, err := requestWatchlist(c)
+		if err != nil {
+			return err
+		}
+		entry := NewWatchlistEntry(f, *anime)
+		entry.Status = body.Status
+		entry.Favorite = body.Favorite
+		added, err := store.Add(entry)
+		if err != nil {
+			return watchlistError(c, err)
+		}
+		return c.Status(201).JSON(added)
+	})
+
+	app.Get(watchlistEntryUrl, func(c *fiber.Ctx) error {
+		store, err := requestWatchlist(c)
+		if err != nil {
+			return err
+		}
+		entry, found := store.Get(c.Params("source"), c.Params("animeId"))
+		if !found {
+			return watchlistError(c, watchlist.ErrNotFound)
+		}
+		return c.JSON(entry)
+	})
+
+	app.Patch(watchlistEntryUrl, func(c *fiber.Ctx) error {
+		var body struct {
+			Status   *string `json:"status"`
+			Favorite *bool   `json:"favorite"`
+		}
+		if err := c.BodyParser(&body); err != nil {
+			return c.Status(400).JSON(map[string]string{"message": "invalid body, reason: " + err.Error()})
+		}
+		store, err := requestWatchlist(c)
+		if err != nil {
+			return err
+		}
+		entry, err := store.Update(c.Params("source"), c.Params("animeId"), func(e *watchlist.Entry) {
+			if body.Status != nil {
+				e.Status = *body.Status
+			}
+			if body.Favorite != nil {
+				e.Favorite = *body.Favorite
+			}
+		})
+		if err != nil {
+			return watchlistError(c, err)
+		}
+		return c.JSON(entry)
+	})
+
+	app.Delete(watchlistEntryUrl, func(c *fiber.Ctx) error {
+		store, err := requestWatchlist(c)
+		if err != nil {
+			return err
+		}
+		if err := store.Remove(c.Params("source"), c.Params("animeId")); err != nil {
+			return watchlistError(c, err)
+		}
+		return c.SendStatus(204)
+	})
+}
+
+func watchlistError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, watchlist.ErrNotFound):
+		return c.Status(404).JSON(map[string]string{"message": err.Error()})
+	case errors.Is(err, watchlist.ErrInvalidStatus):
+		return c.Status(400).JSON(map[string]string{"message": err.Error()})
+	}
+	return err
+}
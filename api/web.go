@@ -0,0 +1,32 @@
+package api
+
+import (
+	"embed"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/filesystem"
+)
+
+// the web ui, a page on top of the v1 api that loads a pinned hls.js from unpkg
+//
+//go:embed web
+var webFiles embed.FS
+
+// InitiateWebRoutes serves the web ui, the page and its assets are public and
+// it asks for an api key when the api needs one
+func InitiateWebRoutes(app *fiber.App) {
+	app.Get(webUrl, func(c *fiber.Ctx) error {
+		page, err := webFiles.ReadFile("web/index.html")
+		if err != nil {
+			return err
+		}
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return c.Send(page)
+	})
+
+	app.Use(webAssetsUrl, filesystem.New(filesystem.Config{
+		Root:       http.FS(webFiles),
+		PathPrefix: "web",
+	}))
+}
@@ -0,0 +1,474 @@
+// the web ui of `ani-ar serve`, a hash routed page on top of the v1 api
+"use strict";
+
+const view = document.getElementById("view");
+const profileSelect = document.getElementById("profile");
+
+const state = {
+  apiKey: localStorage.getItem("ani-ar.apiKey") || "",
+  profile: localStorage.getItem("ani-ar.profile") || "",
+  jellyfin: false,
+};
+
+const statuses = ["plan-to-watch", "watching", "completed", "dropped"];
+
+class ApiError extends Error {
+  constructor(status, error) {
+    super(error.message);
+    this.status = status;
+    this.code = error.code;
+  }
+}
+
+// api sends a v1 request and returns the data of its envelope
+async function api(method, path, body) {
+  const headers = { Accept: "application/json" };
+  if (body !== undefined) headers["Content-Type"] = "application/json";
+  if (state.apiKey) headers.Authorization = "Bearer " + state.apiKey;
+  if (state.profile) headers["X-Ani-Profile"] = state.profile;
+  const res = await fetch("api/v1" + path, {
+    method,
+    headers,
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+  if (res.status === 204) return null;
+  const payload = await res.json().catch(() => ({}));
+  if (!res.ok) {
+    throw new ApiError(res.status, payload.error || { code: "error", message: res.statusText });
+  }
+  return payload.data;
+}
+
+// withAuth adds the api key and the profile to the query of the links the
+// browser loads by itself (the videos and the event streams)
+function withAuth(path, query = {}) {
+  const params = new URLSearchParams(query);
+  if (state.profile) params.set("profile", state.profile);
+  if (state.apiKey) params.set("api_key", state.apiKey);
+  const q = params.toString();
+  return q ? path + "?" + q : path;
+}
+
+function seg(s) {
+  return encodeURIComponent(s);
+}
+
+function esc(s) {
+  return String(s ?? "").replace(/[&<>"']/g, (c) => ({
+    "&": "&amp;", "<": "&lt;", ">": "&gt;", '"': "&quot;", "'": "&#39;",
+  })[c]);
+}
+
+function html(strings, ...values) {
+  return strings.reduce((out, s, i) => {
+    const v = values[i - 1];
+    return out + (v && v.safe !== undefined ? v.safe : esc(v)) + s;
+  });
+}
+
+// raw marks already escaped html so html doesn't escape it again
+function raw(s) {
+  return { safe: s };
+}
+
+let toastTimer;
+function toast(message) {
+  const el = document.getElementById("toast");
+  el.textContent = message;
+  el.hidden = false;
+  clearTimeout(toastTimer);
+  toastTimer = setTimeout(() => (el.hidden = true), 4000);
+}
+
+function fail(error) {
+  toast(error.message || String(error));
+}
+
+function askApiKey() {
+  const key = prompt("api key (ani-ar apikey create):", state.apiKey);
+  if (key === null) return;
+  state.apiKey = key.trim();
+  localStorage.setItem("ani-ar.apiKey", state.apiKey);
+  init();
+}
+
+document.getElementById("key-button").addEventListener("click", askApiKey);
+
+profileSelect.addEventListener("change", () => {
+  state.profile = profileSelect.value;
+  localStorage.setItem("ani-ar.profile", state.profile);
+  route();
+});
+
+async function loadProfiles() {
+  const profiles = await api("GET", "/profiles").catch(() => []);
+  profileSelect.innerHTML = profiles
+    .map((p) => html`<option value="${p.name}">${p.name}</option>`)
+    .join("");
+  if (!profiles.some((p) => p.name === state.profile)) {
+    state.profile = "";
+  }
+  if (state.profile) profileSelect.value = state.profile;
+  profileSelect.hidden = profiles.length < 2;
+}
+
+// the jellyfin routes are only served with a jellyfin library
+async function detectJellyfin() {
+  state.jellyfin = await api("GET", "/jellyfin/status").then(() => true, () => false);
+  document.getElementById("jellyfin-link").hidden = !state.jellyfin;
+}
+
+// closed when leaving the view
+let cleanup = null;
+
+const routes = [
+  [/^#\/?$/, searchView],
+  [/^#\/search\/(.*)$/, searchView],
+  [/^#\/anime\/([^/]+)$/, animeView],
+  [/^#\/watch\/([^/]+)\/(\d+)$/, watchView],
+  [/^#\/watchlist$/, watchlistView],
+  [/^#\/downloads$/, downloadsView],
+  [/^#\/jellyfin$/, jellyfinView],
+];
+
+function route() {
+  if (cleanup) {
+    cleanup();
+    cleanup = null;
+  }
+  const hash = location.hash || "#/";
+  for (const a of document.querySelectorAll("header nav a")) {
+    const target = a.getAttribute("href");
+    a.classList.toggle("active", target === "#/" ? hash === "#/" || hash.startsWith("#/search") : hash.startsWith(target));
+  }
+  for (const [pattern, render] of routes) {
+    const match = hash.match(pattern);
+    if (match) {
+      view.innerHTML = '<p class="empty">Loading…</p>';
+      render(...match.slice(1).map((m) => decodeURIComponent(m || ""))).catch((error) => {
+        view.innerHTML = html`<p class="error">${error.message}</p>`;
+        if (error.status === 401 || error.status === 403) {
+          view.insertAdjacentHTML("beforeend", '<button class="primary" id="key-retry">Enter an api key</button>');
+          document.getElementById("key-retry").addEventListener("click", askApiKey);
+        }
+      });
+      return;
+    }
+  }
+  location.hash = "#/";
+}
+
+async function searchView(query = "") {
+  view.innerHTML = html`
+    <form class="row" id="search">
+      <input type="search" name="q" placeholder="Search anime" value="${query}" autofocus>
+      <button class="primary">Search</button>
+    </form>
+    <div id="results"></div>`;
+  document.getElementById("search").addEventListener("submit", (e) => {
+    e.preventDefault();
+    const q = e.target.q.value.trim();
+    if (q) location.hash = "#/search/" + seg(q);
+  });
+  if (!query) return;
+  const results = document.getElementById("results");
+  results.innerHTML = '<p class="empty">Searching…</p>';
+  const anime = await api("GET", "/anime?q=" + seg(query));
+  results.innerHTML = anime.length
+    ? '<div class="grid">' + anime.map((a) => html`
+        <a class="card" href="#/anime/${seg(a.id)}">
+          <img src="${a.cover}" alt="" loading="lazy">
+          <span>${a.title}</span>
+        </a>`).join("") + "</div>"
+    : '<p class="empty">No results</p>';
+}
+
+async function animeView(animeId) {
+  const [anime, episodes] = await Promise.all([
+    api("GET", "/anime/" + seg(animeId)),
+    api("GET", "/anime/" + seg(animeId) + "/episodes"),
+  ]);
+  const d = anime.details;
+  const entryPath = "/watchlist/" + seg(anime.source) + "/" + seg(anime.id);
+  const meta = d
+    ? [d.type, d.year || "", d.status, d.score ? "★ " + d.score : "", (d.genres || []).map((g) => g.name).join(", ")]
+        .filter(Boolean).join(" · ")
+    : anime.source;
+  view.innerHTML = html`
+    <section class="details">
+      <img src="${d ? d.images.jpg.large_image_url : anime.cover}" alt="">
+      <div>
+        <h1>${anime.title}</h1>
+        ${raw(d && d.title_english && d.title_english !== anime.title ? html`<p class="meta">${d.title_english}</p>` : "")}
+        <p class="meta">${meta}</p>
+        <p class="synopsis">${d ? d.synopsis : ""}</p>
+        <div class="row">
+          <select id="watch-status">
+            <option value="">Add to the watchlist</option>
+            ${raw(statuses.map((s) => html`<option value="${s}">${s}</option>`).join(""))}
+          </select>
+          <button id="favorite" type="button" hidden>☆ Favorite</button>
+          <button id="jellyfin-add" type="button" ${raw(state.jellyfin ? "" : "hidden")}>Add to Jellyfin</button>
+        </div>
+        <form class="row" id="download">
+          Episodes
+          <input type="number" name="from" min="1" max="${episodes.length}" value="1">
+          to
+          <input type="number" name="to" min="1" max="${episodes.length}" value="${episodes.length}">
+          <button>Download</button>
+        </form>
+      </div>
+    </section>
+    <h2>Episodes</h2>
+    <ul class="episodes">
+      ${raw(episodes.map((e) => html`
+        <li><a href="#/watch/${seg(anime.id)}/${e.number}">
+          <span class="number">${e.number}</span>
+          <span>${e.title || "Episode " + e.number}</span>
+          ${raw(e.filler ? '<span class="tag">filler</span>' : "")}
+          ${raw(e.recap ? '<span class="tag">recap</span>' : "")}
+          <span class="meta hide-small">${e.aired ? e.aired.slice(0, 10) : ""}</span>
+        </a></li>`).join(""))}
+    </ul>`;
+
+  const statusSelect = document.getElementById("watch-status");
+  const favorite = document.getElementById("favorite");
+  const showEntry = (e) => {
+    statusSelect.value = e ? e.status : "";
+    statusSelect.options[0].textContent = e ? "Remove from the watchlist" : "Add to the watchlist";
+    favorite.hidden = !e;
+    favorite.textContent = e && e.favorite ? "★ Favorite" : "☆ Favorite";
+    favorite.dataset.on = e && e.favorite ? "1" : "";
+  };
+  showEntry(await api("GET", entryPath).catch(() => null));
+  statusSelect.addEventListener("change", async () => {
+    try {
+      const status = statusSelect.value;
+      if (!status) {
+        await api("DELETE", entryPath);
+        showEntry(null);
+      } else if (!favorite.hidden) {
+        showEntry(await api("PATCH", entryPath, { status }));
+      } else {
+        showEntry(await api("POST", "/watchlist", { id: anime.id, source: anime.source, status }));
+      }
+    } catch (error) {
+      fail(error);
+    }
+  });
+  favorite.addEventListener("click", () => {
+    api("PATCH", entryPath, { favorite: !favorite.dataset.on }).then(showEntry, fail);
+  });
+  document.getElementById("jellyfin-add").addEventListener("click", () => {
+    api("POST", "/jellyfin/items", { id: anime.id, type: d && d.type === "Movie" ? "Movie" : "TV" })
+      .then(() => toast("Added to the Jellyfin library"), fail);
+  });
+  document.getElementById("download").addEventListener("submit", (e) => {
+    e.preventDefault();
+    const body = { id: anime.id, source: anime.source, from: +e.target.from.value, to: +e.target.to.value };
+    api("POST", "/downloads", body).then((jobs) => toast(jobs.length + " episodes queued"), fail);
+  });
+}
+
+async function watchView(animeId, episodeNum) {
+  const number = +episodeNum;
+  const episode = await api("GET", "/anime/" + seg(animeId) + "/episodes/" + number);
+  const videos = episode.videos.filter((v) => v.res).sort((a, b) => parseInt(b.res) - parseInt(a.res));
+  const animeUrl = "#/anime/" + seg(animeId);
+  view.innerHTML = html`
+    <p><a href="${animeUrl}">${episode.anime.title}</a></p>
+    <h1>${number}. ${episode.title || "Episode " + number}</h1>
+    <video id="player" controls autoplay playsinline></video>
+    <div class="row">
+      <button id="prev" ${raw(number > 1 ? "" : "disabled")}>Previous</button>
+      <select id="quality" ${raw(videos.length ? "" : "hidden")}>
+        ${raw(videos.map((v) => html`<option value="${v.res}">${v.res}p</option>`).join(""))}
+      </select>
+      <button id="next" ${raw(number < episode.anime.episodes ? "" : "disabled")}>Next</button>
+    </div>`;
+
+  const player = document.getElementById("player");
+  const quality = document.getElementById("quality");
+  let hls = null;
+  const play = (time = 0) => {
+    if (hls) {
+      hls.destroy();
+      hls = null;
+    }
+    const res = quality.value;
+    const video = videos.find((v) => v.res === res) || episode.videos[0];
+    // the stream proxy resolves the video again on every request so the link doesn't expire
+    const src = withAuth("stream/" + seg(animeId) + "/" + number, res ? { res } : {});
+    const isHls = video && /\.m3u8(\?|$)/i.test(video.src);
+    if (isHls && !player.canPlayType("application/vnd.apple.mpegurl") && window.Hls && Hls.isSupported()) {
+      hls = new Hls();
+      hls.loadSource(src);
+      hls.attachMedia(player);
+    } else {
+      player.src = src;
+    }
+    player.currentTime = time;
+  };
+  quality.addEventListener("change", () => play(player.currentTime));
+  document.getElementById("prev").addEventListener("click", () => (location.hash = "#/watch/" + seg(animeId) + "/" + (number - 1)));
+  document.getElementById("next").addEventListener("click", () => (location.hash = "#/watch/" + seg(animeId) + "/" + (number + 1)));
+  player.addEventListener("ended", () => {
+    if (number < episode.anime.episodes) location.hash = "#/watch/" + seg(animeId) + "/" + (number + 1);
+  });
+  play();
+  cleanup = () => {
+    if (hls) hls.destroy();
+    player.removeAttribute("src");
+    player.load();
+  };
+}
+
+async function watchlistView() {
+  const entries = await api("GET", "/watchlist");
+  if (!entries.length) {
+    view.innerHTML = '<h1>Watchlist</h1><p class="empty">The watchlist is empty</p>';
+    return;
+  }
+  view.innerHTML = html`
+    <h1>Watchlist</h1>
+    <table>
+      <thead><tr><th>Anime</th><th>Status</th><th class="hide-small">Source</th><th></th></tr></thead>
+      <tbody>
+        ${raw(entries.map((e) => html`
+          <tr data-source="${e.source}" data-id="${e.id}">
+            <td><a href="#/anime/${seg(e.id)}">${e.favorite ? "★ " : ""}${e.title}</a></td>
+            <td><select class="status">
+              ${raw(statuses.map((s) => html`<option value="${s}" ${raw(s === e.status ? "selected" : "")}>${s}</option>`).join(""))}
+            </select></td>
+            <td class="hide-small meta">${e.source}</td>
+            <td class="actions"><button class="remove">Remove</button></td>
+          </tr>`).join(""))}
+      </tbody>
+    </table>`;
+  const entryPath = (row) => "/watchlist/" + seg(row.dataset.source) + "/" + seg(row.dataset.id);
+  view.addEventListener("change", onChange);
+  view.addEventListener("click", onClick);
+  function onChange(e) {
+    if (!e.target.matches("select.status")) return;
+    api("PATCH", entryPath(e.target.closest("tr")), { status: e.target.value }).catch(fail);
+  }
+  function onClick(e) {
+    if (!e.target.matches("button.remove")) return;
+    const row = e.target.closest("tr");
+    api("DELETE", entryPath(row)).then(() => row.remove(), fail);
+  }
+  cleanup = () => {
+    view.removeEventListener("change", onChange);
+    view.removeEventListener("click", onClick);
+  };
+}
+
+function jobRow(job) {
+  const actions = [];
+  if (job.status === "queued" || job.status === "downloading") actions.push("pause");
+  if (job.status === "paused" || job.status === "failed") actions.push("resume");
+  actions.push(job.status === "done" ? "remove" : "cancel");
+  return html`
+    <tr data-id="${job.id}">
+      <td>${job.episode.anime.displayName} <span class="meta">#${job.episode.number}</span>
+        ${raw(job.error ? html`<div class="error">${job.error}</div>` : "")}</td>
+      <td>${job.status}</td>
+      <td class="hide-small"><progress max="1" value="${job.progress}"></progress></td>
+      <td class="actions">${raw(actions.map((a) => html`<button data-action="${a}">${a}</button>`).join(""))}</td>
+    </tr>`;
+}
+
+async function downloadsView() {
+  const jobs = new Map((await api("GET", "/downloads")).map((job) => [job.id, job]));
+  const render = () => {
+    view.innerHTML = jobs.size
+      ? html`<h1>Downloads</h1>
+        <table>
+          <thead><tr><th>Episode</th><th>Status</th><th class="hide-small">Progress</th><th></th></tr></thead>
+          <tbody>${raw([...jobs.values()].map(jobRow).join(""))}</tbody>
+        </table>`
+      : '<h1>Downloads</h1><p class="empty">No downloads, queue episodes from the page of an anime</p>';
+  };
+  render();
+
+  const onClick = (e) => {
+    const action = e.target.dataset.action;
+    if (!action) return;
+    const id = +e.target.closest("tr").dataset.id;
+    const request = action === "cancel" || action === "remove"
+      ? api("DELETE", "/downloads/" + id).then(() => jobs.delete(id))
+      : api("POST", "/downloads/" + id + "/" + action).then((job) => jobs.set(id, job));
+    request.then(render, fail);
+  };
+  view.addEventListener("click", onClick);
+
+  // the progress is followed with the server-sent events, the data of the
+  // download events is a summary of the job so its row is reloaded
+  const events = new EventSource(withAuth("api/events", { types: "download" }));
+  let pending = null;
+  const refresh = (id) => {
+    api("GET", "/downloads/" + id).then((job) => jobs.set(id, job), () => jobs.delete(id)).then(() => {
+      if (!pending) pending = requestAnimationFrame(() => { pending = null; render(); });
+    });
+  };
+  for (const type of ["queued", "started", "paused", "cancelled", "done", "failed"]) {
+    events.addEventListener("download." + type, (e) => refresh(JSON.parse(e.data).data.id));
+  }
+  events.addEventListener("download.progress", (e) => {
+    const data = JSON.parse(e.data).data;
+    const job = jobs.get(data.id);
+    if (!job) return refresh(data.id);
+    job.status = data.status;
+    job.progress = data.progress;
+    const bar = view.querySelector(`tr[data-id="${data.id}"] progress`);
+    if (bar) bar.value = data.progress;
+  });
+  cleanup = () => {
+    events.close();
+    view.removeEventListener("click", onClick);
+  };
+}
+
+async function jellyfinView() {
+  const [items, status] = await Promise.all([api("GET", "/jellyfin/items"), api("GET", "/jellyfin/status")]);
+  view.innerHTML = html`
+    <h1>Jellyfin</h1>
+    <div class="row">
+      <span class="meta">${items.length} anime${status.running ? ", " + status.running + " running" : ""}</span>
+      <button id="refresh">Refresh the links</button>
+    </div>
+    ${raw(status.lastRevisionError ? html`<p class="error">${status.lastRevisionError}</p>` : "")}
+    ${raw(status.lastRefreshError ? html`<p class="error">${status.lastRefreshError}</p>` : "")}
+    <table>
+      <thead><tr><th>Anime</th><th>Type</th><th class="hide-small">Quality</th><th class="hide-small">Season</th><th></th></tr></thead>
+      <tbody>
+        ${raw(items.map((item) => html`
+          <tr data-id="${item.id}">
+            <td><a href="#/anime/${seg(item.id)}">${item.id}</a></td>
+            <td>${item.type}</td>
+            <td class="hide-small">${item.res}</td>
+            <td class="hide-small">${item.season}</td>
+            <td class="actions"><button class="remove">Remove</button></td>
+          </tr>`).join(""))}
+      </tbody>
+    </table>`;
+  document.getElementById("refresh").addEventListener("click", () => {
+    api("POST", "/jellyfin/refresh").then(() => toast("The links are being refreshed"), fail);
+  });
+  const onClick = (e) => {
+    if (!e.target.matches("button.remove")) return;
+    const row = e.target.closest("tr");
+    api("DELETE", "/jellyfin/items/" + seg(row.dataset.id)).then(() => row.remove(), fail);
+  };
+  view.addEventListener("click", onClick);
+  cleanup = () => view.removeEventListener("click", onClick);
+}
+
+async function init() {
+  await loadProfiles();
+  await detectJellyfin();
+  route();
+}
+
+window.addEventListener("hashchange", route);
+init();
@@ -0,0 +1,271 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ani/ani-ar/config"
+	"github.com/goccy/go-json"
+)
+
+// the scopes of a key, admin has all of them
+const (
+	ScopeRead         = "read"
+	ScopeStream       = "stream"
+	ScopeLibraryWrite = "library:write"
+	ScopeAdmin        = "admin"
+)
+
+var Scopes = []string{ScopeRead, ScopeStream, ScopeLibraryWrite, ScopeAdmin}
+
+// the keys look like ani_<id>_<secret>, the id finds the key without
+// comparing the secret against every hash
+const tokenPrefix = "ani_"
+
+var (
+	ErrInvalidKey   = errors.New("invalid api key")
+	ErrNotFound     = errors.New("api key not found")
+	ErrInvalidScope = errors.New("invalid scope, it should be one of read, stream, library:write or admin")
+	ErrNoScopes     = errors.New("the api key needs at least one scope")
+	ErrInvalidName  = errors.New("the api key needs a name")
+	ErrNameExists   = errors.New("an api key with the name already exists")
+)
+
+// Key is an api key, only the sha256 hash of its secret is kept
+type Key struct {
+	Id        string    `json:"id"`
+	Name      string    `json:"name"`
+	Hash      string    `json:"hash"`
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// HasScope reports whether the key grants the scope, admin grants every scope
+func (k Key) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, ScopeAdmin) || slices.Contains(k.Scopes, scope)
+}
+
+type Store struct {
+	path string
+
+	mu sync.RWMutex
+	// the modification time of the file when it was read, the keys are
+	// created and revoked by the cli while the server runs
+	modTime time.Time
+	Keys    []*Key `json:"keys"`
+}
+
+var (
+	store     *Store
+	storeOnce sync.Once
+)
+
+// NewStore returns the api keys of the file
+func NewStore(path string) *Store {
+	s := &Store{path: path}
+	s.reload()
+	return s
+}
+
+// GetStore returns the api keys of apikeys.json in the config folder
+func GetStore() *Store {
+	storeOnce.Do(func() {
+		store = NewStore(config.Path("apikeys.json"))
+	})
+	return store
+}
+
+// reload reads the file again when it changed since it was read, an
+// unreadable file keeps the keys that were read before
+func (s *Store) reload() {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			s.mu.Lock()
+			s.Keys, s.modTime = nil, time.Time{}
+			s.mu.Unlock()
+		}
+		return
+	}
+	s.mu.RLock()
+	unchanged := info.ModTime().Equal(s.modTime)
+	s.mu.RUnlock()
+	if unchanged {
+		return
+	}
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		return
+	}
+	var read Store
+	if err := json.Unmarshal(b, &read); err != nil {
+		return
+	}
+	s.mu.Lock()
+	s.Keys, s.modTime = read.Keys, info.ModTime()
+	s.mu.Unlock()
+}
+
+// must be called with the lock held
+func (s *Store) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), os.ModePerm); err != nil {
+		return err
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	// the hashes can't be reversed but they are nobody else's business
+	if err := os.WriteFile(s.path, b, 0600); err != nil {
+		return err
+	}
+	if info, err := os.Stat(s.path); err == nil {
+		s.modTime = info.ModTime()
+	}
+	return nil
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func ValidateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return ErrNoScopes
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(Scopes, scope) {
+			return ErrInvalidScope
+		}
+	}
+	return nil
+}
+
+// Create adds a key with the scopes and returns its token, the token can't be
+// shown again since only its hash is kept
+func (s *Store) Create(name string, scopes []string) (string, Key, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", Key{}, ErrInvalidName
+	}
+	if err := ValidateScopes(scopes); err != nil {
+		return "", Key{}, err
+	}
+	s.reload()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, k := range s.Keys {
+		if k.Name == name {
+			return "", Key{}, ErrNameExists
+		}
+	}
+
+	secret := randomHex(24)
+	key := &Key{
+		Id:        randomHex(4),
+		Name:      name,
+		Hash:      hash(secret),
+		Scopes:    slices.Compact(slices.Sorted(slices.Values(scopes))),
+		CreatedAt: time.Now(),
+	}
+	s.Keys = append(s.Keys, key)
+	if err := s.save(); err != nil {
+		s.Keys = s.Keys[:len(s.Keys)-1]
+		return "", Key{}, err
+	}
+	return tokenPrefix + key.Id + "_" + secret, *key, nil
+}
+
+// List returns the keys, the oldest first
+func (s *Store) List() []Key {
+	s.reload()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]Key, len(s.Keys))
+	for i, k := range s.Keys {
+		keys[i] = *k
+	}
+	return keys
+}
+
+// Get returns the key with the id
+func (s *Store) Get(id string) (Key, bool) {
+	s.reload()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, k := range s.Keys {
+		if k.Id == id {
+			return *k, true
+		}
+	}
+	return Key{}, false
+}
+
+// Revoke removes the key with the id or the name
+func (s *Store) Revoke(idOrName string) error {
+	s.reload()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, k := range s.Keys {
+		if k.Id == idOrName || k.Name == idOrName {
+			s.Keys = append(s.Keys[:i], s.Keys[i+1:]...)
+			return s.save()
+		}
+	}
+	return ErrNotFound
+}
+
+// Enabled reports whether the api requires a key, it does once a key is created
+func (s *Store) Enabled() bool {
+	s.reload()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.Keys) > 0
+}
+
+// ParseToken returns the id of the token and the hash of its secret, the hash
+// is what the store keeps of the key
+func ParseToken(token string) (id, secretHash string, err error) {
+	rest, found := strings.CutPrefix(token, tokenPrefix)
+	if !found {
+		return "", "", ErrInvalidKey
+	}
+	id, secret, found := strings.Cut(rest, "_")
+	if !found {
+		return "", "", ErrInvalidKey
+	}
+	return id, hash(secret), nil
+}
+
+// Authenticate returns the key of the token
+func (s *Store) Authenticate(token string) (Key, error) {
+	id, secretHash, err := ParseToken(token)
+	if err != nil {
+		return Key{}, err
+	}
+	s.reload()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, k := range s.Keys {
+		if k.Id == id && subtle.ConstantTimeCompare([]byte(k.Hash), []byte(secretHash)) == 1 {
+			return *k, nil
+		}
+	}
+	return Key{}, ErrInvalidKey
+}
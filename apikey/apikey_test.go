@@ -0,0 +1,115 @@
+package apikey
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestStore(t *testing.T) *Store {
+	return NewStore(filepath.Join(t.TempDir(), "apikeys.json"))
+}
+
+func TestCreateAuthenticateRevoke(t *testing.T) {
+	s := newTestStore(t)
+	token, key, err := s.Create("tv", []string{ScopeStream, ScopeRead, ScopeStream})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(token, tokenPrefix+key.Id+"_") {
+		t.Fatalf("the token should start with the id of the key, got %s", token)
+	}
+	if len(key.Scopes) != 2 || key.Scopes[0] != ScopeRead || key.Scopes[1] != ScopeStream {
+		t.Fatalf("the scopes should be sorted without duplicates, got %v", key.Scopes)
+	}
+
+	authenticated, err := s.Authenticate(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authenticated.Id != key.Id || !authenticated.HasScope(ScopeStream) || authenticated.HasScope(ScopeAdmin) {
+		t.Fatalf("got %+v", authenticated)
+	}
+	for _, invalid := range []string{token + "0", tokenPrefix + key.Id, "ani_ffffffff_" + strings.Split(token, "_")[2], key.Hash} {
+		if _, err := s.Authenticate(invalid); err != ErrInvalidKey {
+			t.Fatalf("%s should be invalid, got %v", invalid, err)
+		}
+	}
+
+	if _, _, err := s.Create("tv", []string{ScopeRead}); err != ErrNameExists {
+		t.Fatalf("got %v, want the existing name", err)
+	}
+	if err := s.Revoke("tv"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Authenticate(token); err != ErrInvalidKey {
+		t.Fatalf("the revoked key should be invalid, got %v", err)
+	}
+	if err := s.Revoke(key.Id); err != ErrNotFound {
+		t.Fatalf("got %v, want not found", err)
+	}
+}
+
+func TestCreateValidatesTheKey(t *testing.T) {
+	s := newTestStore(t)
+	if _, _, err := s.Create(" ", []string{ScopeRead}); err != ErrInvalidName {
+		t.Fatalf("got %v, want the invalid name", err)
+	}
+	if _, _, err := s.Create("tv", nil); err != ErrNoScopes {
+		t.Fatalf("got %v, want no scopes", err)
+	}
+	if _, _, err := s.Create("tv", []string{"write"}); err != ErrInvalidScope {
+		t.Fatalf("got %v, want the invalid scope", err)
+	}
+	if s.Enabled() {
+		t.Fatal("the invalid keys shouldn't be created")
+	}
+}
+
+func TestOnlyTheHashIsSaved(t *testing.T) {
+	s := newTestStore(t)
+	token, key, err := s.Create("tv", []string{ScopeAdmin})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(s.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("the keys file should only be readable by its owner, got %v", info.Mode().Perm())
+	}
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := strings.Split(token, "_")[2]
+	if strings.Contains(string(b), secret) || !strings.Contains(string(b), key.Hash) {
+		t.Fatalf("only the hash of the secret should be saved:\n%s", b)
+	}
+	if _, secretHash, _ := ParseToken(token); secretHash != key.Hash {
+		t.Fatalf("the hash of the token should be the saved one, got %s", secretHash)
+	}
+}
+
+func TestStoreIsEnabledByTheFirstKey(t *testing.T) {
+	s := newTestStore(t)
+	if s.Enabled() {
+		t.Fatal("the store shouldn't be enabled without keys")
+	}
+	// the cli creates the key while the server runs
+	cli := NewStore(s.path)
+	if _, _, err := cli.Create("tv", []string{ScopeRead}); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Enabled() {
+		t.Fatal("the store should be enabled once a key is created")
+	}
+	if err := cli.Revoke("tv"); err != nil {
+		t.Fatal(err)
+	}
+	if s.Enabled() {
+		t.Fatal("the store shouldn't be enabled once the keys are revoked")
+	}
+}
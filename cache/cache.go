@@ -0,0 +1,202 @@
+// Package cache caches the upstream calls of the api and the fetchers, the
+// values are kept as json in a backend (in memory by default or on the disk)
+// for the ttl of their resource and the concurrent calls of a missing value
+// share a single upstream call
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/goccy/go-json"
+	"golang.org/x/sync/singleflight"
+)
+
+// Resource is a type of cached value, each type has its ttl
+type Resource string
+
+const (
+	// the search results of the fetchers
+	Search Resource = "search"
+	// the anime of the fetchers and their api responses
+	Anime Resource = "anime"
+	// the episodes lists of the api
+	Episodes Resource = "episodes"
+	// the video sources of the episodes, the hosts links expire after a while
+	Videos Resource = "videos"
+	// the videos the stream proxy resolved, they are kept for a short time
+	// since it plays them right away
+	Stream Resource = "stream"
+	// the MyAnimeList anime of jikan
+	JikanAnime Resource = "jikan.anime"
+	// the MyAnimeList episodes of jikan
+	JikanEpisodes Resource = "jikan.episodes"
+	// the seasons and top lists of jikan
+	JikanLists Resource = "jikan.lists"
+)
+
+var (
+	ErrUnknownResource = errors.New("unknown cache resource, it should be one of search, anime, episodes, videos, stream, jikan.anime, jikan.episodes or jikan.lists")
+	// ErrNotCached is returned by the fetch functions whose value is given to
+	// the waiting calls without being stored, eg. an empty search result
+	ErrNotCached = errors.New("the value isn't cached")
+)
+
+var ttls = map[Resource]time.Duration{
+	Search:        time.Hour,
+	Anime:         6 * time.Hour,
+	Episodes:      time.Hour,
+	Videos:        time.Hour,
+	Stream:        10 * time.Minute,
+	JikanAnime:    6 * 24 * time.Hour,
+	JikanEpisodes: 24 * time.Hour,
+	JikanLists:    time.Hour,
+}
+
+// TTL returns how long the values of the resource are kept, 0 when they aren't
+func TTL(r Resource) time.Duration {
+	return ttls[r]
+}
+
+// SetTTL changes the ttl of the resource, 0 stops caching it. It should be
+// called before the cache is used
+func SetTTL(r Resource, ttl time.Duration) error {
+	if _, found := ttls[r]; !found {
+		return ErrUnknownResource
+	}
+	if ttl < 0 {
+		return fmt.Errorf("invalid ttl %v of %s, it should be positive", ttl, r)
+	}
+	ttls[r] = ttl
+	return nil
+}
+
+// SetTTLs changes the ttls of a list like search=30m,videos=10m
+func SetTTLs(list string) error {
+	for _, pair := range strings.Split(list, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, found := strings.Cut(pair, "=")
+		if !found {
+			return fmt.Errorf("invalid cache ttl %q, it should look like videos=10m", pair)
+		}
+		ttl, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("invalid cache ttl %q, reason: %v", pair, err)
+		}
+		if err := SetTTL(Resource(strings.TrimSpace(name)), ttl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Entry is a stored value
+type Entry struct {
+	Value    []byte    `json:"value"`
+	StoredAt time.Time `json:"storedAt"`
+	// the zero time for the values that weren't stored
+	Expires time.Time `json:"expires"`
+}
+
+func (e Entry) expired() bool {
+	return !e.Expires.IsZero() && time.Now().After(e.Expires)
+}
+
+// Backend keeps the entries, it should drop them once they expire
+type Backend interface {
+	Get(key string) (Entry, bool)
+	Set(key string, e Entry)
+	Delete(key string)
+}
+
+type Cache struct {
+	backend Backend
+	group   singleflight.Group
+}
+
+func New(backend Backend) *Cache {
+	return &Cache{backend: backend}
+}
+
+var defaultCache atomic.Pointer[Cache]
+
+func init() {
+	defaultCache.Store(New(NewMemory()))
+}
+
+// Default returns the cache of the api and the fetchers, in memory unless
+// SetDefault changed it
+func Default() *Cache {
+	return defaultCache.Load()
+}
+
+func SetDefault(c *Cache) {
+	defaultCache.Store(c)
+}
+
+// the keys of the resources don't collide
+func resourceKey(r Resource, key string) string {
+	return string(r) + ":" + key
+}
+
+type fetchResult struct {
+	entry Entry
+	err   error
+}
+
+// Do decodes the value of the key in v, fn is called when it's missing and
+// the concurrent calls for the same key wait for the first one. The value of
+// fn is stored for the ttl of the resource unless it fails, its value is
+// still decoded in v for the ErrNotCached errors
+func (c *Cache) Do(r Resource, key string, v interface{}, fn func() (interface{}, error)) (Entry, error) {
+	key = resourceKey(r, key)
+	if e, found := c.backend.Get(key); found && !e.expired() {
+		if err := json.Unmarshal(e.Value, v); err == nil {
+			return e, nil
+		}
+		// eg. a value stored by an older version
+		c.backend.Delete(key)
+	}
+
+	res, _, _ := c.group.Do(key, func() (interface{}, error) {
+		value, err := fn()
+		b, marshalErr := json.Marshal(value)
+		if marshalErr != nil {
+			return fetchResult{err: marshalErr}, nil
+		}
+		e := Entry{Value: b, StoredAt: time.Now()}
+		if ttl := TTL(r); err == nil && ttl > 0 {
+			e.Expires = e.StoredAt.Add(ttl)
+			c.backend.Set(key, e)
+		}
+		return fetchResult{entry: e, err: err}, nil
+	})
+	result := res.(fetchResult)
+	// every call decodes its own copy so they don't share the value
+	if result.entry.Value != nil {
+		if err := json.Unmarshal(result.entry.Value, v); err != nil && result.err == nil {
+			return result.entry, err
+		}
+	}
+	return result.entry, result.err
+}
+
+// Delete removes the value of the key
+func (c *Cache) Delete(r Resource, key string) {
+	c.backend.Delete(resourceKey(r, key))
+}
+
+// Fetch returns the value of the key from the default cache, see Cache.Do
+func Fetch[T any](r Resource, key string, fn func() (T, error)) (T, error) {
+	var v T
+	_, err := Default().Do(r, key, &v, func() (interface{}, error) {
+		return fn()
+	})
+	return v, err
+}
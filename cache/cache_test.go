@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSetTTLs(t *testing.T) {
+	t.Cleanup(func() {
+		ttls[Search] = time.Hour
+		ttls[Stream] = 10 * time.Minute
+	})
+	if err := SetTTLs("search=30m, stream=0"); err != nil {
+		t.Fatal(err)
+	}
+	if TTL(Search) != 30*time.Minute || TTL(Stream) != 0 {
+		t.Fatalf("got search %v and stream %v", TTL(Search), TTL(Stream))
+	}
+	for _, list := range []string{"search", "search=soon", "search=-1m", "other=1m"} {
+		if err := SetTTLs(list); err == nil {
+			t.Errorf("%q should be refused", list)
+		}
+	}
+	if err := SetTTL("other", time.Minute); err != ErrUnknownResource {
+		t.Fatalf("got %v, want ErrUnknownResource", err)
+	}
+}
+
+func TestDo(t *testing.T) {
+	c := New(NewMemory())
+	calls := 0
+	fetch := func() (interface{}, error) {
+		calls++
+		return []string{"naruto"}, nil
+	}
+
+	var v []string
+	first, err := c.Do(Search, "naruto", &v, fetch)
+	if err != nil || len(v) != 1 || v[0] != "naruto" {
+		t.Fatalf("got %v, %v", v, err)
+	}
+	var cached []string
+	if e, _ := c.Do(Search, "naruto", &cached, fetch); calls != 1 || !e.StoredAt.Equal(first.StoredAt) || len(cached) != 1 {
+		t.Fatalf("the value should be cached, %d calls", calls)
+	}
+	// the resources have their own keys
+	c.Do(Anime, "naruto", &v, fetch)
+	if calls != 2 {
+		t.Fatalf("the anime shouldn't get the search value, %d calls", calls)
+	}
+
+	c.Delete(Search, "naruto")
+	c.Do(Search, "naruto", &v, fetch)
+	if calls != 3 {
+		t.Fatalf("the deleted value should be fetched again, %d calls", calls)
+	}
+}
+
+func TestErrorsAreNotCached(t *testing.T) {
+	c := New(NewMemory())
+	calls := 0
+	for _, want := range []error{ErrNotCached, errors.New("the source is down")} {
+		var v []string
+		_, err := c.Do(Search, "naruto", &v, func() (interface{}, error) {
+			calls++
+			return []string{}, want
+		})
+		if err != want || v == nil {
+			t.Fatalf("got %v, %v, want the value and %v", v, err, want)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("the failed calls shouldn't be cached, %d calls", calls)
+	}
+}
+
+func TestConcurrentCallsShareTheFetch(t *testing.T) {
+	c := New(NewMemory())
+	var calls atomic.Int32
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	values := make([][]string, 10)
+	for i := range values {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Do(Search, "naruto", &values[i], func() (interface{}, error) {
+				calls.Add(1)
+				<-release
+				return []string{"naruto"}, nil
+			})
+		}()
+	}
+	// the calls wait for the first one
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Fatalf("%d upstream calls, want 1", calls.Load())
+	}
+	values[0][0] = "changed"
+	for i, v := range values[1:] {
+		if len(v) != 1 || v[0] != "naruto" {
+			t.Fatalf("call %d got %v, the calls shouldn't share the value", i+1, v)
+		}
+	}
+}
+
+func TestFetchExpires(t *testing.T) {
+	SetDefault(New(NewMemory()))
+	t.Cleanup(func() {
+		SetDefault(New(NewMemory()))
+		ttls[Stream] = 10 * time.Minute
+	})
+	ttls[Stream] = 20 * time.Millisecond
+	calls := 0
+	fetch := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	if v, _ := Fetch(Stream, "naruto", fetch); v != 1 {
+		t.Fatalf("got %d", v)
+	}
+	if v, _ := Fetch(Stream, "naruto", fetch); v != 1 {
+		t.Fatalf("got %d, the value should be cached", v)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if v, _ := Fetch(Stream, "naruto", fetch); v != 2 {
+		t.Fatalf("got %d, the value should be fetched again once it expires", v)
+	}
+
+	// a 0 ttl stops caching
+	ttls[Stream] = 0
+	Fetch(Stream, "bleach", fetch)
+	if v, _ := Fetch(Stream, "bleach", fetch); v != 4 {
+		t.Fatalf("got %d, the value shouldn't be cached", v)
+	}
+}
@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// Disk keeps the entries as files of a folder so they survive restarts, the
+// expired files are removed when they are read and when the folder is opened
+type Disk struct {
+	dir string
+}
+
+func NewDisk(dir string) (*Disk, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	d := &Disk{dir: dir}
+	go d.removeExpired()
+	return d, nil
+}
+
+// the keys are hashed since they can hold any character
+func (d *Disk) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (d *Disk) read(path string) (Entry, bool) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Entry{}, false
+	}
+	var e Entry
+	if json.Unmarshal(b, &e) != nil || e.expired() {
+		os.Remove(path)
+		return Entry{}, false
+	}
+	return e, true
+}
+
+func (d *Disk) Get(key string) (Entry, bool) {
+	return d.read(d.path(key))
+}
+
+// Set writes the entry to a temporary file first so a concurrent Get doesn't read half of it
+func (d *Disk) Set(key string, e Entry) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(d.dir, "*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(b)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if os.Rename(tmp.Name(), d.path(key)) != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+func (d *Disk) Delete(key string) {
+	os.Remove(d.path(key))
+}
+
+func (d *Disk) removeExpired() {
+	files, err := os.ReadDir(d.dir)
+	if err != nil {
+		return
+	}
+	for _, f := range files {
+		path := filepath.Join(d.dir, f.Name())
+		switch {
+		case strings.HasSuffix(f.Name(), ".json"):
+			d.read(path)
+		case strings.HasSuffix(f.Name(), ".tmp"):
+			// left by an interrupted Set, the recent ones can still be written
+			if info, err := f.Info(); err == nil && time.Since(info.ModTime()) > time.Minute {
+				os.Remove(path)
+			}
+		}
+	}
+}
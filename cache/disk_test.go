@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDisk(t *testing.T) {
+	dir := t.TempDir()
+	d, err := NewDisk(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := Entry{Value: []byte(`"naruto"`), StoredAt: time.Now(), Expires: time.Now().Add(time.Hour)}
+	d.Set("search:naruto/1?q=a b", e)
+	got, found := d.Get("search:naruto/1?q=a b")
+	if !found || string(got.Value) != `"naruto"` || !got.Expires.Equal(e.Expires) {
+		t.Fatalf("got %+v, %v", got, found)
+	}
+
+	// the entries survive a restart
+	d, err = NewDisk(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, found := d.Get("search:naruto/1?q=a b"); !found {
+		t.Fatal("the entry should be read again")
+	}
+
+	d.Delete("search:naruto/1?q=a b")
+	if _, found := d.Get("search:naruto/1?q=a b"); found {
+		t.Fatal("the entry should be deleted")
+	}
+}
+
+func TestDiskRemovesTheExpiredEntries(t *testing.T) {
+	dir := t.TempDir()
+	d, err := NewDisk(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Set("expired", Entry{Value: []byte("1"), Expires: time.Now().Add(-time.Second)})
+	d.Set("kept", Entry{Value: []byte("2"), Expires: time.Now().Add(time.Hour)})
+	os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0600)
+
+	if _, found := d.Get("expired"); found {
+		t.Fatal("the expired entry shouldn't be returned")
+	}
+	d.Set("expired", Entry{Value: []byte("1"), Expires: time.Now().Add(-time.Second)})
+	d.removeExpired()
+	files, _ := os.ReadDir(dir)
+	if len(files) != 1 || files[0].Name() != filepath.Base(d.path("kept")) {
+		t.Fatalf("only the kept entry should be left, %v", files)
+	}
+}
+
+func TestDiskBackedCache(t *testing.T) {
+	d, err := NewDisk(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := New(d)
+	calls := 0
+	for i := 0; i < 2; i++ {
+		var v map[string]int
+		c.Do(Videos, "naruto:1", &v, func() (interface{}, error) {
+			calls++
+			return map[string]int{"episode": 1}, nil
+		})
+		if v["episode"] != 1 {
+			t.Fatalf("got %v", v)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("%d upstream calls, want 1", calls)
+	}
+}
@@ -0,0 +1,32 @@
+package cache
+
+import (
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+)
+
+// Memory keeps the entries in memory, they are lost on restart
+type Memory struct {
+	c *gocache.Cache
+}
+
+func NewMemory() *Memory {
+	return &Memory{c: gocache.New(gocache.NoExpiration, 10*time.Minute)}
+}
+
+func (m *Memory) Get(key string) (Entry, bool) {
+	v, found := m.c.Get(key)
+	if !found {
+		return Entry{}, false
+	}
+	return v.(Entry), true
+}
+
+func (m *Memory) Set(key string, e Entry) {
+	m.c.Set(key, e, time.Until(e.Expires))
+}
+
+func (m *Memory) Delete(key string) {
+	m.c.Delete(key)
+}
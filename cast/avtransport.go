@@ -0,0 +1,101 @@
+package cast
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type soapArg struct {
+	name  string
+	value string
+}
+
+func xmlEscape(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// sends an AVTransport action to the renderer control url
+func (d *Device) callAction(action string, args ...soapArg) error {
+	body := new(strings.Builder)
+	body.WriteString(`<?xml version="1.0" encoding="utf-8"?>`)
+	body.WriteString(`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>`)
+	fmt.Fprintf(body, `<u:%s xmlns:u="%s">`, action, avTransportType)
+	for _, arg := range args {
+		fmt.Fprintf(body, "<%s>%s</%s>", arg.name, xmlEscape(arg.value), arg.name)
+	}
+	fmt.Fprintf(body, "</u:%s>", action)
+	body.WriteString(`</s:Body></s:Envelope>`)
+
+	req, err := http.NewRequest(http.MethodPost, d.ControlURL, strings.NewReader(body.String()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	req.Header.Set("SOAPAction", fmt.Sprintf(`"%s#%s"`, avTransportType, action))
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("%s failed with status %d: %s", action, res.StatusCode, string(b))
+	}
+	return nil
+}
+
+// minimal DIDL-Lite metadata, some renderers refuse to play without it
+func didlMetadata(uri, title, contentType string) string {
+	return `<DIDL-Lite xmlns="urn:schemas-upnp-org:metadata-1-0/DIDL-Lite/" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:upnp="urn:schemas-upnp-org:metadata-1-0/upnp/">` +
+		`<item id="0" parentID="-1" restricted="1">` +
+		`<dc:title>` + xmlEscape(title) + `</dc:title>` +
+		`<upnp:class>object.item.videoItem</upnp:class>` +
+		`<res protocolInfo="http-get:*:` + xmlEscape(contentType) + `:*">` + xmlEscape(uri) + `</res>` +
+		`</item></DIDL-Lite>`
+}
+
+// SetAVTransportURI loads the url on a DLNA renderer without playing it
+func (d *Device) SetAVTransportURI(uri, title, contentType string) error {
+	return d.callAction("SetAVTransportURI",
+		soapArg{"InstanceID", "0"},
+		soapArg{"CurrentURI", uri},
+		soapArg{"CurrentURIMetaData", didlMetadata(uri, title, contentType)},
+	)
+}
+
+func (d *Device) avPlay() error {
+	return d.callAction("Play", soapArg{"InstanceID", "0"}, soapArg{"Speed", "1"})
+}
+
+func (d *Device) avPause() error {
+	return d.callAction("Pause", soapArg{"InstanceID", "0"})
+}
+
+func (d *Device) avStop() error {
+	return d.callAction("Stop", soapArg{"InstanceID", "0"})
+}
+
+func (d *Device) avSeek(position time.Duration) error {
+	return d.callAction("Seek",
+		soapArg{"InstanceID", "0"},
+		soapArg{"Unit", "REL_TIME"},
+		soapArg{"Target", formatDuration(position)},
+	)
+}
+
+// formats the duration as H:MM:SS which is what AVTransport expects
+func formatDuration(t time.Duration) string {
+	if t < 0 {
+		t = 0
+	}
+	total := int(t.Seconds())
+	return fmt.Sprintf("%d:%02d:%02d", total/3600, (total/60)%60, total%60)
+}
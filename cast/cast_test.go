@@ -0,0 +1,293 @@
+package cast
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestCastMessageRoundTrip(t *testing.T) {
+	msg := castMessage{
+		SourceId:      senderId,
+		DestinationId: receiverId,
+		Namespace:     namespaceReceiver,
+		PayloadUtf8:   `{"type":"GET_STATUS","requestId":1,"title":"` + strings.Repeat("x", 300) + `"}`,
+	}
+	var decoded castMessage
+	if err := decoded.unmarshal(msg.marshal()); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != msg {
+		t.Fatalf("decoded %+v, want %+v", decoded, msg)
+	}
+	if err := decoded.unmarshal([]byte{fieldNamespace<<3 | 2, 10, 'a'}); err == nil {
+		t.Fatal("a truncated message should fail")
+	}
+}
+
+func TestContentType(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/stream/hls":
+			w.Header().Set("Content-Type", "application/x-mpegURL")
+		case "/stream/webm":
+			w.Header().Set("Content-Type", "video/webm")
+		case "/stream/missing":
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	tests := map[string]string{
+		"http://host/video/playlist.m3u8?token=1": hlsContentType,
+		"http://host/video/episode.MKV":           "video/x-matroska",
+		srv.URL + "/stream/hls":                   hlsContentType,
+		srv.URL + "/stream/webm":                  "video/webm",
+		srv.URL + "/stream/missing":               defaultContentType,
+	}
+	for uri, want := range tests {
+		if got := ContentType(uri); got != want {
+			t.Errorf("ContentType(%q) = %q, want %q", uri, got, want)
+		}
+	}
+}
+
+// listens on a local udp port that answers the discovery queries with answer
+func fakeMulticast(t *testing.T, answer func(query []byte) []byte) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	go func() {
+		buf := make([]byte, 2048)
+		for {
+			n, from, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if res := answer(buf[:n]); res != nil {
+				conn.WriteTo(res, from)
+			}
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func withDiscoveryAddrs(t *testing.T, ssdp, mdns string) {
+	oldSsdp, oldMdns := SSDPAddr, MDNSAddr
+	SSDPAddr, MDNSAddr = ssdp, mdns
+	t.Cleanup(func() { SSDPAddr, MDNSAddr = oldSsdp, oldMdns })
+}
+
+func TestDLNA(t *testing.T) {
+	var mu sync.Mutex
+	var actions []string
+	var metadata string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/description.xml":
+			io.WriteString(w, `<root><device><deviceType>urn:schemas-upnp-org:device:MediaRenderer:1</deviceType>`+
+				`<friendlyName>Living Room TV</friendlyName><serviceList><service>`+
+				`<serviceType>urn:schemas-upnp-org:service:AVTransport:1</serviceType>`+
+				`<controlURL>/control</controlURL></service></serviceList></device></root>`)
+		case "/control":
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			defer mu.Unlock()
+			actions = append(actions, strings.Trim(strings.Split(r.Header.Get("SOAPAction"), "#")[1], `"`))
+			if strings.Contains(string(body), "SetAVTransportURI") {
+				metadata = string(body)
+			}
+		}
+	}))
+	defer srv.Close()
+
+	ssdp := fakeMulticast(t, func(query []byte) []byte {
+		if !strings.HasPrefix(string(query), "M-SEARCH") {
+			return nil
+		}
+		return []byte("HTTP/1.1 200 OK\r\nLOCATION: " + srv.URL + "/description.xml\r\nST: " + mediaRendererType + "\r\n\r\n")
+	})
+	withDiscoveryAddrs(t, ssdp, fakeMulticast(t, func([]byte) []byte { return nil }))
+
+	device, err := FindDevice("living room", 500*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if device.Kind != KindDLNA || device.ControlURL != srv.URL+"/control" {
+		t.Fatalf("unexpected device %+v", device)
+	}
+	if err := device.Cast(srv.URL+"/video.m3u8", "episode 1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := device.Pause(); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := strings.Join(actions, ","); got != "SetAVTransportURI,Play,Pause" {
+		t.Fatalf("actions %s", got)
+	}
+	if !strings.Contains(metadata, "http-get:*:"+hlsContentType+":*") {
+		t.Fatalf("the metadata should have the hls protocol info: %s", metadata)
+	}
+}
+
+// fakeChromecast is a cast v2 receiver that plays the media it's loaded with
+type fakeChromecast struct {
+	listener net.Listener
+	mu       sync.Mutex
+	loaded   map[string]interface{}
+	commands []string
+}
+
+func newFakeChromecast(t *testing.T) *fakeChromecast {
+	t.Helper()
+	// borrow the self signed certificate of httptest
+	certSrv := httptest.NewUnstartedServer(nil)
+	certSrv.StartTLS()
+	cert := certSrv.TLS.Certificates[0]
+	certSrv.Close()
+
+	l, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	f := &fakeChromecast{listener: l}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeChromecast) serve(conn net.Conn) {
+	defer conn.Close()
+	c := &castConn{conn: conn}
+	write := func(to, namespace string, payload interface{}) {
+		data, _ := json.Marshal(payload)
+		b := (&castMessage{SourceId: to, DestinationId: senderId, Namespace: namespace, PayloadUtf8: string(data)}).marshal()
+		conn.Write(append(binary.BigEndian.AppendUint32(nil, uint32(len(b))), b...))
+	}
+	// the senders should answer the heartbeats while they wait
+	write(receiverId, namespaceHeartbeat, map[string]string{"type": "PING"})
+
+	for {
+		msg, err := c.read()
+		if err != nil {
+			return
+		}
+		var req map[string]interface{}
+		json.Unmarshal([]byte(msg.PayloadUtf8), &req)
+		requestId := req["requestId"]
+		receiverStatus := map[string]interface{}{
+			"type":      "RECEIVER_STATUS",
+			"requestId": requestId,
+			"status": map[string]interface{}{
+				"applications": []map[string]string{{"appId": defaultMediaReceiver, "transportId": "web-1"}},
+			},
+		}
+		mediaStatus := map[string]interface{}{
+			"type":      "MEDIA_STATUS",
+			"requestId": requestId,
+			"status":    []map[string]int{{"mediaSessionId": 7}},
+		}
+
+		f.mu.Lock()
+		switch {
+		case msg.Namespace == namespaceReceiver && req["type"] == "LAUNCH":
+			write(receiverId, namespaceReceiver, receiverStatus)
+		case msg.Namespace == namespaceReceiver && req["type"] == "GET_STATUS":
+			write(receiverId, namespaceReceiver, receiverStatus)
+		case msg.Namespace == namespaceMedia && msg.DestinationId == "web-1":
+			switch req["type"] {
+			case "LOAD":
+				f.loaded = req["media"].(map[string]interface{})
+			case "GET_STATUS":
+			default:
+				f.commands = append(f.commands, fmt.Sprintf("%v %v %v", req["type"], req["mediaSessionId"], req["currentTime"]))
+			}
+			write("web-1", namespaceMedia, mediaStatus)
+		}
+		f.mu.Unlock()
+	}
+}
+
+func TestChromecast(t *testing.T) {
+	receiver := newFakeChromecast(t)
+	port := uint16(receiver.listener.Addr().(*net.TCPAddr).Port)
+
+	mdns := fakeMulticast(t, func(query []byte) []byte {
+		var msg dnsmessage.Message
+		if msg.Unpack(query) != nil || len(msg.Questions) != 1 || msg.Questions[0].Name.String() != chromecastService {
+			return nil
+		}
+		service := dnsmessage.MustNewName(chromecastService)
+		instance := dnsmessage.MustNewName("Chromecast-abc." + chromecastService)
+		host := dnsmessage.MustNewName("abc.local.")
+		header := func(name dnsmessage.Name, typ dnsmessage.Type) dnsmessage.ResourceHeader {
+			return dnsmessage.ResourceHeader{Name: name, Type: typ, Class: dnsmessage.ClassINET, TTL: 120}
+		}
+		res := dnsmessage.Message{
+			Header: dnsmessage.Header{Response: true, Authoritative: true},
+			Answers: []dnsmessage.Resource{
+				{Header: header(service, dnsmessage.TypePTR), Body: &dnsmessage.PTRResource{PTR: instance}},
+			},
+			Additionals: []dnsmessage.Resource{
+				{Header: header(instance, dnsmessage.TypeSRV), Body: &dnsmessage.SRVResource{Target: host, Port: port}},
+				{Header: header(instance, dnsmessage.TypeTXT), Body: &dnsmessage.TXTResource{TXT: []string{"id=abc", "fn=Bedroom TV"}}},
+				{Header: header(host, dnsmessage.TypeA), Body: &dnsmessage.AResource{A: [4]byte{127, 0, 0, 1}}},
+			},
+		}
+		b, _ := res.Pack()
+		return b
+	})
+	withDiscoveryAddrs(t, fakeMulticast(t, func([]byte) []byte { return nil }), mdns)
+
+	device, err := FindDevice("bedroom", 500*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if device.Kind != KindChromecast || device.Addr != receiver.listener.Addr().String() {
+		t.Fatalf("unexpected device %+v", device)
+	}
+
+	if err := device.Cast("http://127.0.0.1:1/video.m3u8", "episode 1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := device.Seek(90 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := device.Pause(); err != nil {
+		t.Fatal(err)
+	}
+
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+	if receiver.loaded["contentId"] != "http://127.0.0.1:1/video.m3u8" || receiver.loaded["contentType"] != hlsContentType {
+		t.Fatalf("unexpected loaded media %v", receiver.loaded)
+	}
+	if got := strings.Join(receiver.commands, ","); got != "SEEK 7 90,PAUSE 7 <nil>" {
+		t.Fatalf("commands %s", got)
+	}
+}
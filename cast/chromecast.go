@@ -0,0 +1,363 @@
+package cast
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// the app id of the Default Media Receiver, it plays the urls it's given
+const defaultMediaReceiver = "CC1AD845"
+
+// the namespaces of the cast v2 channel
+const (
+	namespaceConnection = "urn:x-cast:com.google.cast.tp.connection"
+	namespaceHeartbeat  = "urn:x-cast:com.google.cast.tp.heartbeat"
+	namespaceReceiver   = "urn:x-cast:com.google.cast.receiver"
+	namespaceMedia      = "urn:x-cast:com.google.cast.media"
+)
+
+const (
+	senderId   = "sender-0"
+	receiverId = "receiver-0"
+)
+
+// how long to wait for the device to answer a request, launching the
+// receiver app can take a few seconds
+const chromecastTimeout = 20 * time.Second
+
+// the messages can't be bigger than 64KiB
+const maxCastMessageSize = 64 << 10
+
+// castMessage is the CastMessage protobuf of the cast v2 channel, only the
+// string payloads are used
+type castMessage struct {
+	SourceId      string
+	DestinationId string
+	Namespace     string
+	PayloadUtf8   string
+}
+
+// the protobuf field numbers of CastMessage
+const (
+	fieldProtocolVersion = 1
+	fieldSourceId        = 2
+	fieldDestinationId   = 3
+	fieldNamespace       = 4
+	fieldPayloadType     = 5
+	fieldPayloadUtf8     = 6
+)
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+func appendStringField(b []byte, field int, s string) []byte {
+	b = appendVarint(b, uint64(field)<<3|2)
+	b = appendVarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func appendVarintField(b []byte, field int, v uint64) []byte {
+	b = appendVarint(b, uint64(field)<<3)
+	return appendVarint(b, v)
+}
+
+// marshal encodes the message, the protocol version and the payload type
+// are required so they are always written even though they are 0
+// (CASTV2_1_0 and STRING)
+func (m *castMessage) marshal() []byte {
+	var b []byte
+	b = appendVarintField(b, fieldProtocolVersion, 0)
+	b = appendStringField(b, fieldSourceId, m.SourceId)
+	b = appendStringField(b, fieldDestinationId, m.DestinationId)
+	b = appendStringField(b, fieldNamespace, m.Namespace)
+	b = appendVarintField(b, fieldPayloadType, 0)
+	b = appendStringField(b, fieldPayloadUtf8, m.PayloadUtf8)
+	return b
+}
+
+func readVarint(b []byte) (uint64, int, error) {
+	var v uint64
+	for i := 0; i < len(b) && i < 10; i++ {
+		v |= uint64(b[i]&0x7f) << (7 * i)
+		if b[i] < 0x80 {
+			return v, i + 1, nil
+		}
+	}
+	return 0, 0, errors.New("invalid varint in the cast message")
+}
+
+// unmarshal decodes the message, skipping the fields it doesn't use
+func (m *castMessage) unmarshal(b []byte) error {
+	for len(b) > 0 {
+		tag, n, err := readVarint(b)
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+		field, wireType := int(tag>>3), tag&7
+		switch wireType {
+		case 0:
+			if _, n, err = readVarint(b); err != nil {
+				return err
+			}
+			b = b[n:]
+		case 2:
+			size, n, err := readVarint(b)
+			if err != nil {
+				return err
+			}
+			b = b[n:]
+			if uint64(len(b)) < size {
+				return errors.New("truncated cast message")
+			}
+			value := string(b[:size])
+			b = b[size:]
+			switch field {
+			case fieldSourceId:
+				m.SourceId = value
+			case fieldDestinationId:
+				m.DestinationId = value
+			case fieldNamespace:
+				m.Namespace = value
+			case fieldPayloadUtf8:
+				m.PayloadUtf8 = value
+			}
+		default:
+			return fmt.Errorf("unsupported wire type %d in the cast message", wireType)
+		}
+	}
+	return nil
+}
+
+// castConn is a cast v2 channel, the messages are length prefixed protobufs
+// over tls
+type castConn struct {
+	conn      net.Conn
+	requestId int
+}
+
+func dialChromecast(addr string) (*castConn, error) {
+	dialer := &net.Dialer{Timeout: httpClient.Timeout}
+	// the devices use self signed certificates
+	conn, err := tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		return nil, err
+	}
+	c := &castConn{conn: conn}
+	if err := c.send(receiverId, namespaceConnection, map[string]string{"type": "CONNECT"}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *castConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *castConn) send(destination, namespace string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	msg := castMessage{
+		SourceId:      senderId,
+		DestinationId: destination,
+		Namespace:     namespace,
+		PayloadUtf8:   string(data),
+	}
+	b := msg.marshal()
+	frame := binary.BigEndian.AppendUint32(make([]byte, 0, 4+len(b)), uint32(len(b)))
+	c.conn.SetWriteDeadline(time.Now().Add(chromecastTimeout))
+	_, err = c.conn.Write(append(frame, b...))
+	return err
+}
+
+func (c *castConn) read() (*castMessage, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(c.conn, size[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > maxCastMessageSize {
+		return nil, errors.New("the cast message is too big")
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(c.conn, b); err != nil {
+		return nil, err
+	}
+	var msg castMessage
+	return &msg, msg.unmarshal(b)
+}
+
+// castResponse holds the fields of the receiver and media responses
+type castResponse struct {
+	Type      string `json:"type"`
+	RequestId int    `json:"requestId"`
+	Reason    string `json:"reason"`
+	Status    struct {
+		Applications []struct {
+			AppId       string `json:"appId"`
+			TransportId string `json:"transportId"`
+		} `json:"applications"`
+	} `json:"-"`
+	MediaStatus []struct {
+		MediaSessionId int `json:"mediaSessionId"`
+	} `json:"-"`
+}
+
+func (r *castResponse) UnmarshalJSON(data []byte) error {
+	type plain castResponse
+	var raw struct {
+		plain
+		Status json.RawMessage `json:"status"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*r = castResponse(raw.plain)
+	if len(raw.Status) == 0 {
+		return nil
+	}
+	// the receiver status is an object and the media status is a list
+	if raw.Status[0] == '[' {
+		return json.Unmarshal(raw.Status, &r.MediaStatus)
+	}
+	return json.Unmarshal(raw.Status, &r.Status)
+}
+
+// request sends the payload with a new request id and waits for its
+// response, answering the heartbeats in the meantime
+func (c *castConn) request(destination, namespace string, payload map[string]interface{}) (*castResponse, error) {
+	c.requestId++
+	payload["requestId"] = c.requestId
+	if err := c.send(destination, namespace, payload); err != nil {
+		return nil, err
+	}
+
+	c.conn.SetReadDeadline(time.Now().Add(chromecastTimeout))
+	for {
+		msg, err := c.read()
+		if err != nil {
+			return nil, err
+		}
+		if msg.Namespace == namespaceHeartbeat {
+			if err := c.send(msg.SourceId, namespaceHeartbeat, map[string]string{"type": "PONG"}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if msg.Namespace != namespace {
+			continue
+		}
+		var res castResponse
+		if err := json.Unmarshal([]byte(msg.PayloadUtf8), &res); err != nil {
+			return nil, err
+		}
+		if res.RequestId != c.requestId {
+			continue
+		}
+		switch res.Type {
+		case "LAUNCH_ERROR", "LOAD_FAILED", "LOAD_CANCELLED", "INVALID_REQUEST", "INVALID_PLAYER_STATE":
+			if res.Reason != "" {
+				return nil, fmt.Errorf("the chromecast refused the request: %s (%s)", res.Type, res.Reason)
+			}
+			return nil, fmt.Errorf("the chromecast refused the request: %s", res.Type)
+		}
+		return &res, nil
+	}
+}
+
+// mediaReceiver returns the transport id of the running media receiver,
+// launching it when launch is set
+func (c *castConn) mediaReceiver(launch bool) (string, error) {
+	payload := map[string]interface{}{"type": "GET_STATUS"}
+	if launch {
+		payload = map[string]interface{}{"type": "LAUNCH", "appId": defaultMediaReceiver}
+	}
+	res, err := c.request(receiverId, namespaceReceiver, payload)
+	if err != nil {
+		return "", err
+	}
+	for _, app := range res.Status.Applications {
+		if app.AppId == defaultMediaReceiver {
+			return app.TransportId, c.send(app.TransportId, namespaceConnection, map[string]string{"type": "CONNECT"})
+		}
+	}
+	return "", errors.New("nothing is playing on the chromecast")
+}
+
+// chromecastLoad launches the media receiver on the device and loads the url
+func (d *Device) chromecastLoad(uri, title, contentType string) error {
+	c, err := dialChromecast(d.Addr)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	transportId, err := c.mediaReceiver(true)
+	if err != nil {
+		return err
+	}
+	_, err = c.request(transportId, namespaceMedia, map[string]interface{}{
+		"type":     "LOAD",
+		"autoplay": true,
+		"media": map[string]interface{}{
+			"contentId":   uri,
+			"contentType": contentType,
+			"streamType":  "BUFFERED",
+			"metadata": map[string]interface{}{
+				"metadataType": 0,
+				"title":        title,
+			},
+		},
+	})
+	return err
+}
+
+type mediaCommand struct {
+	Type string
+	// the position of SEEK in seconds
+	CurrentTime *float64
+}
+
+// chromecastMedia sends the command to the media session playing on the device
+func (d *Device) chromecastMedia(cmd mediaCommand) error {
+	c, err := dialChromecast(d.Addr)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	transportId, err := c.mediaReceiver(false)
+	if err != nil {
+		return err
+	}
+	status, err := c.request(transportId, namespaceMedia, map[string]interface{}{"type": "GET_STATUS"})
+	if err != nil {
+		return err
+	}
+	if len(status.MediaStatus) == 0 {
+		return errors.New("nothing is playing on the chromecast")
+	}
+	payload := map[string]interface{}{
+		"type":           cmd.Type,
+		"mediaSessionId": status.MediaStatus[0].MediaSessionId,
+	}
+	if cmd.CurrentTime != nil {
+		payload["currentTime"] = *cmd.CurrentTime
+	}
+	_, err = c.request(transportId, namespaceMedia, payload)
+	return err
+}
@@ -0,0 +1,100 @@
+package cast
+
+import (
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	hlsContentType     = "application/vnd.apple.mpegurl"
+	defaultContentType = "video/mp4"
+)
+
+// content types of the video extensions the renderers know about
+var videoExtensions = map[string]string{
+	".m3u8": hlsContentType,
+	".mp4":  "video/mp4",
+	".m4v":  "video/mp4",
+	".mkv":  "video/x-matroska",
+	".webm": "video/webm",
+	".ts":   "video/mp2t",
+	".mpd":  "application/dash+xml",
+}
+
+// ContentType returns the media type of the stream, from the extension of
+// the url or, for the urls without one (eg. the stream proxy), from the
+// Content-Type the server answers with. It falls back to video/mp4
+func ContentType(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return defaultContentType
+	}
+	if t, found := videoExtensions[strings.ToLower(path.Ext(u.Path))]; found {
+		return t
+	}
+
+	res, err := httpClient.Head(uri)
+	if err != nil {
+		return defaultContentType
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return defaultContentType
+	}
+	t, _, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	switch {
+	case err != nil:
+		return defaultContentType
+	case strings.Contains(t, "mpegurl"):
+		return hlsContentType
+	case strings.HasPrefix(t, "video/"), t == "application/dash+xml":
+		return t
+	}
+	return defaultContentType
+}
+
+// Cast loads the url on the device and starts playing it
+func (d *Device) Cast(uri, title string) error {
+	contentType := ContentType(uri)
+	if d.Kind == KindChromecast {
+		return d.chromecastLoad(uri, title, contentType)
+	}
+	if err := d.SetAVTransportURI(uri, title, contentType); err != nil {
+		return err
+	}
+	return d.avPlay()
+}
+
+func (d *Device) Play() error {
+	if d.Kind == KindChromecast {
+		return d.chromecastMedia(mediaCommand{Type: "PLAY"})
+	}
+	return d.avPlay()
+}
+
+func (d *Device) Pause() error {
+	if d.Kind == KindChromecast {
+		return d.chromecastMedia(mediaCommand{Type: "PAUSE"})
+	}
+	return d.avPause()
+}
+
+func (d *Device) Stop() error {
+	if d.Kind == KindChromecast {
+		return d.chromecastMedia(mediaCommand{Type: "STOP"})
+	}
+	return d.avStop()
+}
+
+// Seek jumps to the given position from the start of the current media
+func (d *Device) Seek(position time.Duration) error {
+	if d.Kind == KindChromecast {
+		seconds := position.Seconds()
+		return d.chromecastMedia(mediaCommand{Type: "SEEK", CurrentTime: &seconds})
+	}
+	return d.avSeek(position)
+}
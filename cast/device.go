@@ -0,0 +1,171 @@
+package cast
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const avTransportType = "urn:schemas-upnp-org:service:AVTransport:1"
+
+// the kinds of the cast devices
+const (
+	KindDLNA       = "dlna"
+	KindChromecast = "chromecast"
+)
+
+// the devices are on the local network, the ones that don't answer quickly
+// shouldn't stall the discovery
+var httpClient = &http.Client{Timeout: 5 * time.Second}
+
+type Device struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	// url of the DLNA device description
+	Location string `json:"location,omitempty"`
+	// absolute url of the AVTransport control endpoint
+	ControlURL string `json:"controlUrl,omitempty"`
+	// host:port of the Chromecast cast channel
+	Addr string `json:"addr,omitempty"`
+}
+
+type deviceDescription struct {
+	URLBase string      `xml:"URLBase"`
+	Device  deviceEntry `xml:"device"`
+}
+
+type deviceEntry struct {
+	DeviceType   string         `xml:"deviceType"`
+	FriendlyName string         `xml:"friendlyName"`
+	Services     []serviceEntry `xml:"serviceList>service"`
+	Devices      []deviceEntry  `xml:"deviceList>device"`
+}
+
+type serviceEntry struct {
+	ServiceType string `xml:"serviceType"`
+	ControlURL  string `xml:"controlURL"`
+}
+
+// finds the AVTransport service in the device or in one of its embedded devices
+func (d *deviceEntry) findAVTransport() (*deviceEntry, *serviceEntry) {
+	for i := range d.Services {
+		if strings.HasPrefix(d.Services[i].ServiceType, "urn:schemas-upnp-org:service:AVTransport:") {
+			return d, &d.Services[i]
+		}
+	}
+	for i := range d.Devices {
+		if dev, s := d.Devices[i].findAVTransport(); s != nil {
+			return dev, s
+		}
+	}
+	return nil, nil
+}
+
+// GetDevice reads the device description at location and resolves its AVTransport control url
+func GetDevice(location string) (*Device, error) {
+	res, err := httpClient.Get(location)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get the device description, status %d", res.StatusCode)
+	}
+
+	var desc deviceDescription
+	if err := xml.NewDecoder(res.Body).Decode(&desc); err != nil {
+		return nil, errors.New("couldn't parse the device description, reason: " + err.Error())
+	}
+
+	dev, service := desc.Device.findAVTransport()
+	if service == nil {
+		return nil, errors.New("device doesn't support AVTransport")
+	}
+
+	base := location
+	if desc.URLBase != "" {
+		base = desc.URLBase
+	}
+	baseUrl, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+	controlUrl, err := baseUrl.Parse(service.ControlURL)
+	if err != nil {
+		return nil, err
+	}
+
+	name := desc.Device.FriendlyName
+	if name == "" {
+		name = dev.FriendlyName
+	}
+	if name == "" {
+		name = baseUrl.Host
+	}
+
+	return &Device{
+		Name:       name,
+		Kind:       KindDLNA,
+		Location:   location,
+		ControlURL: controlUrl.String(),
+	}, nil
+}
+
+// Matches reports whether the device friendly name or host matches the given name
+func (d *Device) Matches(name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return false
+	}
+	if strings.Contains(strings.ToLower(d.Name), name) {
+		return true
+	}
+	host := d.host()
+	if host == "" {
+		return false
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil && hostname == name {
+		return true
+	}
+	return host == name
+}
+
+// host returns the host:port the device is reached at
+func (d *Device) host() string {
+	if d.Addr != "" {
+		return d.Addr
+	}
+	u, err := url.Parse(d.Location)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// LocalIP returns the ip of the interface used to reach the device, which is
+// the address the device can use to reach a server running on this machine
+func (d *Device) LocalIP() (string, error) {
+	host := d.host()
+	if host == "" {
+		return "", errors.New("the device has no address")
+	}
+	conn, err := net.Dial("udp", host)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP.String(), nil
+}
+
+func (d *Device) String() string {
+	host := d.host()
+	if host == "" {
+		return d.Name
+	}
+	return fmt.Sprintf("%s (%s, %s)", d.Name, d.Kind, host)
+}
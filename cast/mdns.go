@@ -0,0 +1,152 @@
+package cast
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// MDNSAddr is the address the mDNS query is sent to, it can be pointed to
+// a local fake Chromecast for testing.
+var MDNSAddr = "224.0.0.251:5353"
+
+const chromecastService = "_googlecast._tcp.local."
+
+// the mDNS records of a Chromecast instance
+type mdnsInstance struct {
+	name   string
+	target string
+	port   uint16
+	fields map[string]string
+}
+
+// mdnsQuery asks for the Chromecast instances, the unicast response bit is
+// set because the query isn't sent from the mDNS port
+func mdnsQuery() ([]byte, error) {
+	name, err := dnsmessage.NewName(chromecastService)
+	if err != nil {
+		return nil, err
+	}
+	msg := dnsmessage.Message{
+		Questions: []dnsmessage.Question{{
+			Name:  name,
+			Type:  dnsmessage.TypePTR,
+			Class: dnsmessage.ClassINET | 1<<15,
+		}},
+	}
+	return msg.Pack()
+}
+
+// discoverChromecasts sends an mDNS query for the Chromecasts and collects
+// the devices that answered before the timeout
+func discoverChromecasts(timeout time.Duration) ([]*Device, error) {
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	addr, err := net.ResolveUDPAddr("udp4", MDNSAddr)
+	if err != nil {
+		return nil, err
+	}
+	query, err := mdnsQuery()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.WriteTo(query, addr); err != nil {
+		return nil, err
+	}
+
+	conn.SetReadDeadline(time.Now().Add(timeout))
+
+	instances := make(map[string]*mdnsInstance)
+	ips := make(map[string]net.IP)
+	buf := make([]byte, 9000)
+	for {
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			return nil, err
+		}
+		var msg dnsmessage.Message
+		if err := msg.Unpack(buf[:n]); err != nil {
+			continue
+		}
+		answered := readMdnsRecords(&msg, instances, ips)
+		// the devices that don't send their address are reached at the source of the answer
+		if udpAddr, ok := from.(*net.UDPAddr); ok {
+			for _, instance := range answered {
+				if _, found := ips[instance.target]; !found && instance.target != "" {
+					ips[instance.target] = udpAddr.IP
+				}
+			}
+		}
+	}
+
+	var devices []*Device
+	for _, instance := range instances {
+		ip, found := ips[instance.target]
+		if !found || instance.port == 0 {
+			continue
+		}
+		name := instance.fields["fn"]
+		if name == "" {
+			name = strings.TrimSuffix(instance.name, "."+chromecastService)
+		}
+		devices = append(devices, &Device{
+			Name: name,
+			Kind: KindChromecast,
+			Addr: net.JoinHostPort(ip.String(), strconv.Itoa(int(instance.port))),
+		})
+	}
+	return devices, nil
+}
+
+// readMdnsRecords collects the PTR, SRV, TXT and A records of the answer,
+// the devices send them either as answers or as additional records. It
+// returns the instances the answer is about
+func readMdnsRecords(msg *dnsmessage.Message, instances map[string]*mdnsInstance, ips map[string]net.IP) []*mdnsInstance {
+	var answered []*mdnsInstance
+	instance := func(name string) *mdnsInstance {
+		if instances[name] == nil {
+			instances[name] = &mdnsInstance{name: name, fields: make(map[string]string)}
+		}
+		answered = append(answered, instances[name])
+		return instances[name]
+	}
+	records := append(append([]dnsmessage.Resource{}, msg.Answers...), msg.Additionals...)
+	for _, r := range records {
+		name := r.Header.Name.String()
+		switch body := r.Body.(type) {
+		case *dnsmessage.PTRResource:
+			if strings.EqualFold(name, chromecastService) {
+				instance(body.PTR.String())
+			}
+		case *dnsmessage.SRVResource:
+			if strings.HasSuffix(name, "."+chromecastService) {
+				i := instance(name)
+				i.target, i.port = body.Target.String(), body.Port
+			}
+		case *dnsmessage.TXTResource:
+			if strings.HasSuffix(name, "."+chromecastService) {
+				i := instance(name)
+				for _, field := range body.TXT {
+					if k, v, found := strings.Cut(field, "="); found {
+						i.fields[k] = v
+					}
+				}
+			}
+		case *dnsmessage.AResource:
+			ips[name] = net.IP(body.A[:])
+		}
+	}
+	return answered
+}
@@ -0,0 +1,121 @@
+package cast
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SSDPAddr is the address the M-SEARCH request is sent to, it can be
+// pointed to a local fake renderer for testing.
+var SSDPAddr = "239.255.255.250:1900"
+
+const mediaRendererType = "urn:schemas-upnp-org:device:MediaRenderer:1"
+
+// Discover looks for the DLNA renderers and the Chromecasts at the same
+// time and returns the devices that answered before the timeout
+func Discover(timeout time.Duration) ([]*Device, error) {
+	type result struct {
+		devices []*Device
+		err     error
+	}
+	chromecasts := make(chan result, 1)
+	go func() {
+		devices, err := discoverChromecasts(timeout)
+		chromecasts <- result{devices, err}
+	}()
+	devices, err := discoverRenderers(timeout)
+	found := <-chromecasts
+	devices = append(devices, found.devices...)
+	// multicast may only work for one of them, eg. when a firewall drops the mDNS answers
+	if len(devices) == 0 {
+		return nil, errors.Join(err, found.err)
+	}
+	return devices, nil
+}
+
+// discoverRenderers sends an SSDP M-SEARCH for media renderers and collects
+// the devices that answered before the timeout
+func discoverRenderers(timeout time.Duration) ([]*Device, error) {
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	addr, err := net.ResolveUDPAddr("udp4", SSDPAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	mx := int(timeout.Seconds())
+	if mx < 1 {
+		mx = 1
+	}
+	search := strings.Join([]string{
+		"M-SEARCH * HTTP/1.1",
+		"HOST: " + SSDPAddr,
+		`MAN: "ssdp:discover"`,
+		fmt.Sprintf("MX: %d", mx),
+		"ST: " + mediaRendererType,
+		"", "",
+	}, "\r\n")
+	if _, err := conn.WriteTo([]byte(search), addr); err != nil {
+		return nil, err
+	}
+
+	conn.SetReadDeadline(time.Now().Add(timeout))
+
+	var devices []*Device
+	seen := make(map[string]bool)
+	buf := make([]byte, 2048)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			return devices, err
+		}
+
+		res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(buf[:n])), nil)
+		if err != nil {
+			continue
+		}
+		res.Body.Close()
+		location := res.Header.Get("Location")
+		if location == "" || seen[location] {
+			continue
+		}
+		seen[location] = true
+
+		device, err := GetDevice(location)
+		if err != nil {
+			// not every responder is a renderer we can drive, ignore it
+			continue
+		}
+		devices = append(devices, device)
+	}
+	return devices, nil
+}
+
+// FindDevice discovers the renderers and returns the first one whose
+// friendly name or host matches the given name (case insensitive)
+func FindDevice(name string, timeout time.Duration) (*Device, error) {
+	devices, err := Discover(timeout)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range devices {
+		if d.Matches(name) {
+			return d, nil
+		}
+	}
+	return nil, fmt.Errorf("can't find a cast device named %q", name)
+}
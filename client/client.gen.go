@@ -0,0 +1,5777 @@
+// Package client provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.4.1 DO NOT EDIT.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/oapi-codegen/runtime"
+)
+
+const (
+	ApiKeyHeaderScopes = "apiKeyHeader.Scopes"
+	ApiKeyQueryScopes  = "apiKeyQuery.Scopes"
+	BearerScopes       = "bearer.Scopes"
+)
+
+// Defines values for AuthErrorError.
+const (
+	AuthErrorErrorForbidden    AuthErrorError = "forbidden"
+	AuthErrorErrorUnauthorized AuthErrorError = "unauthorized"
+)
+
+// Defines values for DownloadJobStatus.
+const (
+	Done        DownloadJobStatus = "done"
+	Downloading DownloadJobStatus = "downloading"
+	Failed      DownloadJobStatus = "failed"
+	Paused      DownloadJobStatus = "paused"
+	Queued      DownloadJobStatus = "queued"
+)
+
+// Defines values for ErrorCode.
+const (
+	ErrorCodeConflict         ErrorCode = "conflict"
+	ErrorCodeError            ErrorCode = "error"
+	ErrorCodeForbidden        ErrorCode = "forbidden"
+	ErrorCodeInternalError    ErrorCode = "internal_error"
+	ErrorCodeInvalidRequest   ErrorCode = "invalid_request"
+	ErrorCodeMethodNotAllowed ErrorCode = "method_not_allowed"
+	ErrorCodeNotFound         ErrorCode = "not_found"
+	ErrorCodeRateLimited      ErrorCode = "rate_limited"
+	ErrorCodeUnauthorized     ErrorCode = "unauthorized"
+)
+
+// Defines values for JellyfinItemType.
+const (
+	JellyfinItemTypeMovie JellyfinItemType = "Movie"
+	JellyfinItemTypeTV    JellyfinItemType = "TV"
+)
+
+// Defines values for JellyfinItemUpdateType.
+const (
+	JellyfinItemUpdateTypeMovie JellyfinItemUpdateType = "Movie"
+	JellyfinItemUpdateTypeTV    JellyfinItemUpdateType = "TV"
+)
+
+// Defines values for JellyfinStatusRunning.
+const (
+	Refresh  JellyfinStatusRunning = "refresh"
+	Revision JellyfinStatusRunning = "revision"
+)
+
+// Defines values for WatchlistStatus.
+const (
+	Completed   WatchlistStatus = "completed"
+	Dropped     WatchlistStatus = "dropped"
+	PlanToWatch WatchlistStatus = "plan-to-watch"
+	Watching    WatchlistStatus = "watching"
+)
+
+// Anime defines model for Anime.
+type Anime struct {
+	// Cover the cover image url
+	Cover string `json:"cover"`
+
+	// Details the MyAnimeList details from jikan, only the main fields are listed
+	Details  *AnimeDetails `json:"details,omitempty"`
+	Episodes int           `json:"episodes"`
+
+	// Id the id of the anime in its source
+	Id string `json:"id"`
+
+	// Source the fetcher the anime comes from, eg. anime3rb
+	Source string `json:"source"`
+	Title  string `json:"title"`
+}
+
+// AnimeDetails the MyAnimeList details from jikan, only the main fields are listed
+type AnimeDetails struct {
+	Airing               *bool                  `json:"airing,omitempty"`
+	Duration             *string                `json:"duration,omitempty"`
+	Episodes             *int                   `json:"episodes,omitempty"`
+	MalId                *int                   `json:"mal_id,omitempty"`
+	Rating               *string                `json:"rating,omitempty"`
+	Score                *float32               `json:"score,omitempty"`
+	Season               *string                `json:"season,omitempty"`
+	Status               *string                `json:"status,omitempty"`
+	Synopsis             *string                `json:"synopsis,omitempty"`
+	Title                *string                `json:"title,omitempty"`
+	TitleEnglish         *string                `json:"title_english,omitempty"`
+	TitleJapanese        *string                `json:"title_japanese,omitempty"`
+	Type                 *string                `json:"type,omitempty"`
+	Url                  *string                `json:"url,omitempty"`
+	Year                 *int                   `json:"year,omitempty"`
+	AdditionalProperties map[string]interface{} `json:"-"`
+}
+
+// AuthError the auth errors of the routes outside /api/v1
+type AuthError struct {
+	Error   AuthErrorError `json:"error"`
+	Message string         `json:"message"`
+
+	// Scope the missing scope of the forbidden requests
+	Scope *string `json:"scope,omitempty"`
+}
+
+// AuthErrorError defines model for AuthError.Error.
+type AuthErrorError string
+
+// DownloadJob defines model for DownloadJob.
+type DownloadJob struct {
+	AddedAt time.Time `json:"addedAt"`
+	Episode struct {
+		Anime *struct {
+			DisplayCover *string `json:"displayCover,omitempty"`
+			DisplayName  *string `json:"displayName,omitempty"`
+			Episodes     *int    `json:"episodes,omitempty"`
+			Id           *string `json:"id,omitempty"`
+		} `json:"anime,omitempty"`
+		Number *int    `json:"number,omitempty"`
+		Title  *string `json:"title,omitempty"`
+	} `json:"episode"`
+	Error    *string           `json:"error,omitempty"`
+	Id       int               `json:"id"`
+	Path     string            `json:"path"`
+	Profile  *string           `json:"profile,omitempty"`
+	Progress float32           `json:"progress"`
+	Quality  *string           `json:"quality,omitempty"`
+	Source   *string           `json:"source,omitempty"`
+	Status   DownloadJobStatus `json:"status"`
+}
+
+// DownloadJobStatus defines model for DownloadJob.Status.
+type DownloadJobStatus string
+
+// DownloadRequest defines model for DownloadRequest.
+type DownloadRequest struct {
+	// Dir the absolute folder the anime folder is created in, ANI_AR_DOWNLOADS_DIR or ~/Downloads/ani-ar by default
+	Dir *string `json:"dir,omitempty"`
+
+	// From the first episode, 1 by default
+	From *int   `json:"from,omitempty"`
+	Id   string `json:"id"`
+
+	// Quality the resolution, the quality of the profile (or the best one) by default
+	Quality *string `json:"quality,omitempty"`
+
+	// Source the source of the profile by default
+	Source *string `json:"source,omitempty"`
+
+	// To the last episode, the last episode of the anime by default
+	To *int `json:"to,omitempty"`
+}
+
+// Episode defines model for Episode.
+type Episode struct {
+	Aired  *string `json:"aired,omitempty"`
+	Filler bool    `json:"filler"`
+	Number int     `json:"number"`
+	Recap  bool    `json:"recap"`
+	Title  *string `json:"title,omitempty"`
+}
+
+// EpisodeDetails defines model for EpisodeDetails.
+type EpisodeDetails struct {
+	Aired  *string `json:"aired,omitempty"`
+	Anime  Anime   `json:"anime"`
+	Filler bool    `json:"filler"`
+	Number int     `json:"number"`
+	Recap  bool    `json:"recap"`
+	Title  *string `json:"title,omitempty"`
+	Videos []Video `json:"videos"`
+}
+
+// Error defines model for Error.
+type Error struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+
+	// Scope the missing scope of the forbidden requests
+	Scope *string `json:"scope,omitempty"`
+}
+
+// ErrorCode defines model for Error.Code.
+type ErrorCode string
+
+// ErrorResponse defines model for ErrorResponse.
+type ErrorResponse struct {
+	Error Error `json:"error"`
+}
+
+// JellyfinItem defines model for JellyfinItem.
+type JellyfinItem struct {
+	// CanBeEnhanced whether the anime is looked up on MyAnimeList
+	CanBeEnhanced *bool  `json:"canBeEnhanced,omitempty"`
+	Id            string `json:"id"`
+
+	// Local whether the anime was added with the api, the remote revision checks keep these unless the remote revision lists them too
+	Local  *bool             `json:"local,omitempty"`
+	Res    *string           `json:"res,omitempty"`
+	Season *int              `json:"season,omitempty"`
+	Type   *JellyfinItemType `json:"type,omitempty"`
+}
+
+// JellyfinItemType defines model for JellyfinItem.Type.
+type JellyfinItemType string
+
+// JellyfinItemUpdate defines model for JellyfinItemUpdate.
+type JellyfinItemUpdate struct {
+	CanBeEnhanced *bool                   `json:"canBeEnhanced,omitempty"`
+	Res           *string                 `json:"res,omitempty"`
+	Season        *int                    `json:"season,omitempty"`
+	Type          *JellyfinItemUpdateType `json:"type,omitempty"`
+}
+
+// JellyfinItemUpdateType defines model for JellyfinItemUpdate.Type.
+type JellyfinItemUpdateType string
+
+// JellyfinStatus defines model for JellyfinStatus.
+type JellyfinStatus struct {
+	Items             int                    `json:"items"`
+	LastRefreshAt     *time.Time             `json:"lastRefreshAt,omitempty"`
+	LastRefreshError  *string                `json:"lastRefreshError,omitempty"`
+	LastRevisionAt    *time.Time             `json:"lastRevisionAt,omitempty"`
+	LastRevisionError *string                `json:"lastRevisionError,omitempty"`
+	RemoteRevisionUrl string                 `json:"remoteRevisionUrl"`
+	RevisionId        string                 `json:"revisionId"`
+	Running           *JellyfinStatusRunning `json:"running,omitempty"`
+}
+
+// JellyfinStatusRunning defines model for JellyfinStatus.Running.
+type JellyfinStatusRunning string
+
+// Profile defines model for Profile.
+type Profile struct {
+	Name     string   `json:"name"`
+	Settings Settings `json:"settings"`
+}
+
+// ProfileName defines model for ProfileName.
+type ProfileName struct {
+	Name string `json:"name"`
+}
+
+// Settings defines model for Settings.
+type Settings struct {
+	// Quality the preferred resolution, eg. 1080
+	Quality *string `json:"quality,omitempty"`
+
+	// Source the fetcher the anime are searched in, eg. anime3rb
+	Source *string `json:"source,omitempty"`
+
+	// Translation sub or dub, only allanime has dubbed episodes
+	Translation *string `json:"translation,omitempty"`
+}
+
+// Video defines model for Video.
+type Video struct {
+	// Headers the request headers the video host expects
+	Headers *map[string]string `json:"headers,omitempty"`
+
+	// Res the resolution, eg. 1080
+	Res string `json:"res"`
+	Src string `json:"src"`
+}
+
+// WatchlistEntry defines model for WatchlistEntry.
+type WatchlistEntry struct {
+	AddedAt   time.Time       `json:"addedAt"`
+	Favorite  bool            `json:"favorite"`
+	Id        string          `json:"id"`
+	MalId     *int            `json:"malId,omitempty"`
+	Source    string          `json:"source"`
+	Status    WatchlistStatus `json:"status"`
+	Title     string          `json:"title"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// WatchlistEntryCreate defines model for WatchlistEntryCreate.
+type WatchlistEntryCreate struct {
+	Favorite *bool  `json:"favorite,omitempty"`
+	Id       string `json:"id"`
+
+	// Source the source of the profile by default
+	Source *string          `json:"source,omitempty"`
+	Status *WatchlistStatus `json:"status,omitempty"`
+}
+
+// WatchlistEntryUpdate defines model for WatchlistEntryUpdate.
+type WatchlistEntryUpdate struct {
+	Favorite *bool            `json:"favorite,omitempty"`
+	Status   *WatchlistStatus `json:"status,omitempty"`
+}
+
+// WatchlistStatus defines model for WatchlistStatus.
+type WatchlistStatus string
+
+// AnimeId defines model for AnimeId.
+type AnimeId = string
+
+// EpisodeNum defines model for EpisodeNum.
+type EpisodeNum = int
+
+// JobId defines model for JobId.
+type JobId = int
+
+// ProfileHeader defines model for ProfileHeader.
+type ProfileHeader = string
+
+// Source defines model for Source.
+type Source = string
+
+// BadRequest defines model for BadRequest.
+type BadRequest = ErrorResponse
+
+// Conflict defines model for Conflict.
+type Conflict = ErrorResponse
+
+// Forbidden defines model for Forbidden.
+type Forbidden = ErrorResponse
+
+// NotFound defines model for NotFound.
+type NotFound = ErrorResponse
+
+// TooManyRequests defines model for TooManyRequests.
+type TooManyRequests = ErrorResponse
+
+// Unauthorized defines model for Unauthorized.
+type Unauthorized = ErrorResponse
+
+// SearchAnimeParams defines parameters for SearchAnime.
+type SearchAnimeParams struct {
+	// Q the search query
+	Q string `form:"q" json:"q"`
+
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// GetAnimeParams defines parameters for GetAnime.
+type GetAnimeParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// ListEpisodesParams defines parameters for ListEpisodes.
+type ListEpisodesParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// GetEpisodeParams defines parameters for GetEpisode.
+type GetEpisodeParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// ListDownloadsParams defines parameters for ListDownloads.
+type ListDownloadsParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// AddDownloadsParams defines parameters for AddDownloads.
+type AddDownloadsParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// CancelDownloadParams defines parameters for CancelDownload.
+type CancelDownloadParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// GetDownloadParams defines parameters for GetDownload.
+type GetDownloadParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// PauseDownloadParams defines parameters for PauseDownload.
+type PauseDownloadParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// ResumeDownloadParams defines parameters for ResumeDownload.
+type ResumeDownloadParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// StreamEventsParams defines parameters for StreamEvents.
+type StreamEventsParams struct {
+	// Types comma separated event types or groups (eg. `download,jellyfin.item`), every event is sent without it
+	Types *string `form:"types,omitempty" json:"types,omitempty"`
+
+	// LastEventId the Last-Event-ID of the clients that can't send headers
+	LastEventId *string `form:"lastEventId,omitempty" json:"lastEventId,omitempty"`
+
+	// LastEventID the id of the last received event, the kept events after it are sent first
+	LastEventID *string `json:"Last-Event-ID,omitempty"`
+}
+
+// ListJellyfinItemsParams defines parameters for ListJellyfinItems.
+type ListJellyfinItemsParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// AddJellyfinItemParams defines parameters for AddJellyfinItem.
+type AddJellyfinItemParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// RemoveJellyfinItemParams defines parameters for RemoveJellyfinItem.
+type RemoveJellyfinItemParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// GetJellyfinItemParams defines parameters for GetJellyfinItem.
+type GetJellyfinItemParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// UpdateJellyfinItemParams defines parameters for UpdateJellyfinItem.
+type UpdateJellyfinItemParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// RefreshJellyfinParams defines parameters for RefreshJellyfin.
+type RefreshJellyfinParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// GetJellyfinStatusParams defines parameters for GetJellyfinStatus.
+type GetJellyfinStatusParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// ListProfilesParams defines parameters for ListProfiles.
+type ListProfilesParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// CreateProfileParams defines parameters for CreateProfile.
+type CreateProfileParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// GetSettingsParams defines parameters for GetSettings.
+type GetSettingsParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// UpdateSettingsParams defines parameters for UpdateSettings.
+type UpdateSettingsParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// StreamEpisodeParams defines parameters for StreamEpisode.
+type StreamEpisodeParams struct {
+	// Profile the profile of the players that can't send headers
+	Profile *string `form:"profile,omitempty" json:"profile,omitempty"`
+
+	// Res the preferred resolution (eg. `720`), the quality of the profile by default
+	Res *string `form:"res,omitempty" json:"res,omitempty"`
+
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+	Range       *string        `json:"Range,omitempty"`
+}
+
+// ListWatchlistParams defines parameters for ListWatchlist.
+type ListWatchlistParams struct {
+	Status *WatchlistStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// Favorites only the favorites
+	Favorites *bool `form:"favorites,omitempty" json:"favorites,omitempty"`
+
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// AddToWatchlistParams defines parameters for AddToWatchlist.
+type AddToWatchlistParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// RemoveFromWatchlistParams defines parameters for RemoveFromWatchlist.
+type RemoveFromWatchlistParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// GetWatchlistEntryParams defines parameters for GetWatchlistEntry.
+type GetWatchlistEntryParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// UpdateWatchlistEntryParams defines parameters for UpdateWatchlistEntry.
+type UpdateWatchlistEntryParams struct {
+	// XAniProfile the profile of the request, the active profile by default
+	XAniProfile *ProfileHeader `json:"X-Ani-Profile,omitempty"`
+}
+
+// AddDownloadsJSONRequestBody defines body for AddDownloads for application/json ContentType.
+type AddDownloadsJSONRequestBody = DownloadRequest
+
+// AddJellyfinItemJSONRequestBody defines body for AddJellyfinItem for application/json ContentType.
+type AddJellyfinItemJSONRequestBody = JellyfinItem
+
+// UpdateJellyfinItemJSONRequestBody defines body for UpdateJellyfinItem for application/json ContentType.
+type UpdateJellyfinItemJSONRequestBody = JellyfinItemUpdate
+
+// CreateProfileJSONRequestBody defines body for CreateProfile for application/json ContentType.
+type CreateProfileJSONRequestBody = ProfileName
+
+// UpdateSettingsJSONRequestBody defines body for UpdateSettings for application/json ContentType.
+type UpdateSettingsJSONRequestBody = Settings
+
+// AddToWatchlistJSONRequestBody defines body for AddToWatchlist for application/json ContentType.
+type AddToWatchlistJSONRequestBody = WatchlistEntryCreate
+
+// UpdateWatchlistEntryJSONRequestBody defines body for UpdateWatchlistEntry for application/json ContentType.
+type UpdateWatchlistEntryJSONRequestBody = WatchlistEntryUpdate
+
+// Getter for additional properties for AnimeDetails. Returns the specified
+// element and whether it was found
+func (a AnimeDetails) Get(fieldName string) (value interface{}, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for AnimeDetails
+func (a *AnimeDetails) Set(fieldName string, value interface{}) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]interface{})
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for AnimeDetails to handle AdditionalProperties
+func (a *AnimeDetails) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if raw, found := object["airing"]; found {
+		err = json.Unmarshal(raw, &a.Airing)
+		if err != nil {
+			return fmt.Errorf("error reading 'airing': %w", err)
+		}
+		delete(object, "airing")
+	}
+
+	if raw, found := object["duration"]; found {
+		err = json.Unmarshal(raw, &a.Duration)
+		if err != nil {
+			return fmt.Errorf("error reading 'duration': %w", err)
+		}
+		delete(object, "duration")
+	}
+
+	if raw, found := object["episodes"]; found {
+		err = json.Unmarshal(raw, &a.Episodes)
+		if err != nil {
+			return fmt.Errorf("error reading 'episodes': %w", err)
+		}
+		delete(object, "episodes")
+	}
+
+	if raw, found := object["mal_id"]; found {
+		err = json.Unmarshal(raw, &a.MalId)
+		if err != nil {
+			return fmt.Errorf("error reading 'mal_id': %w", err)
+		}
+		delete(object, "mal_id")
+	}
+
+	if raw, found := object["rating"]; found {
+		err = json.Unmarshal(raw, &a.Rating)
+		if err != nil {
+			return fmt.Errorf("error reading 'rating': %w", err)
+		}
+		delete(object, "rating")
+	}
+
+	if raw, found := object["score"]; found {
+		err = json.Unmarshal(raw, &a.Score)
+		if err != nil {
+			return fmt.Errorf("error reading 'score': %w", err)
+		}
+		delete(object, "score")
+	}
+
+	if raw, found := object["season"]; found {
+		err = json.Unmarshal(raw, &a.Season)
+		if err != nil {
+			return fmt.Errorf("error reading 'season': %w", err)
+		}
+		delete(object, "season")
+	}
+
+	if raw, found := object["status"]; found {
+		err = json.Unmarshal(raw, &a.Status)
+		if err != nil {
+			return fmt.Errorf("error reading 'status': %w", err)
+		}
+		delete(object, "status")
+	}
+
+	if raw, found := object["synopsis"]; found {
+		err = json.Unmarshal(raw, &a.Synopsis)
+		if err != nil {
+			return fmt.Errorf("error reading 'synopsis': %w", err)
+		}
+		delete(object, "synopsis")
+	}
+
+	if raw, found := object["title"]; found {
+		err = json.Unmarshal(raw, &a.Title)
+		if err != nil {
+			return fmt.Errorf("error reading 'title': %w", err)
+		}
+		delete(object, "title")
+	}
+
+	if raw, found := object["title_english"]; found {
+		err = json.Unmarshal(raw, &a.TitleEnglish)
+		if err != nil {
+			return fmt.Errorf("error reading 'title_english': %w", err)
+		}
+		delete(object, "title_english")
+	}
+
+	if raw, found := object["title_japanese"]; found {
+		err = json.Unmarshal(raw, &a.TitleJapanese)
+		if err != nil {
+			return fmt.Errorf("error reading 'title_japanese': %w", err)
+		}
+		delete(object, "title_japanese")
+	}
+
+	if raw, found := object["type"]; found {
+		err = json.Unmarshal(raw, &a.Type)
+		if err != nil {
+			return fmt.Errorf("error reading 'type': %w", err)
+		}
+		delete(object, "type")
+	}
+
+	if raw, found := object["url"]; found {
+		err = json.Unmarshal(raw, &a.Url)
+		if err != nil {
+			return fmt.Errorf("error reading 'url': %w", err)
+		}
+		delete(object, "url")
+	}
+
+	if raw, found := object["year"]; found {
+		err = json.Unmarshal(raw, &a.Year)
+		if err != nil {
+			return fmt.Errorf("error reading 'year': %w", err)
+		}
+		delete(object, "year")
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]interface{})
+		for fieldName, fieldBuf := range object {
+			var fieldVal interface{}
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return fmt.Errorf("error unmarshaling field %s: %w", fieldName, err)
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for AnimeDetails to handle AdditionalProperties
+func (a AnimeDetails) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	if a.Airing != nil {
+		object["airing"], err = json.Marshal(a.Airing)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'airing': %w", err)
+		}
+	}
+
+	if a.Duration != nil {
+		object["duration"], err = json.Marshal(a.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'duration': %w", err)
+		}
+	}
+
+	if a.Episodes != nil {
+		object["episodes"], err = json.Marshal(a.Episodes)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'episodes': %w", err)
+		}
+	}
+
+	if a.MalId != nil {
+		object["mal_id"], err = json.Marshal(a.MalId)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'mal_id': %w", err)
+		}
+	}
+
+	if a.Rating != nil {
+		object["rating"], err = json.Marshal(a.Rating)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'rating': %w", err)
+		}
+	}
+
+	if a.Score != nil {
+		object["score"], err = json.Marshal(a.Score)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'score': %w", err)
+		}
+	}
+
+	if a.Season != nil {
+		object["season"], err = json.Marshal(a.Season)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'season': %w", err)
+		}
+	}
+
+	if a.Status != nil {
+		object["status"], err = json.Marshal(a.Status)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'status': %w", err)
+		}
+	}
+
+	if a.Synopsis != nil {
+		object["synopsis"], err = json.Marshal(a.Synopsis)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'synopsis': %w", err)
+		}
+	}
+
+	if a.Title != nil {
+		object["title"], err = json.Marshal(a.Title)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'title': %w", err)
+		}
+	}
+
+	if a.TitleEnglish != nil {
+		object["title_english"], err = json.Marshal(a.TitleEnglish)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'title_english': %w", err)
+		}
+	}
+
+	if a.TitleJapanese != nil {
+		object["title_japanese"], err = json.Marshal(a.TitleJapanese)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'title_japanese': %w", err)
+		}
+	}
+
+	if a.Type != nil {
+		object["type"], err = json.Marshal(a.Type)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'type': %w", err)
+		}
+	}
+
+	if a.Url != nil {
+		object["url"], err = json.Marshal(a.Url)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'url': %w", err)
+		}
+	}
+
+	if a.Year != nil {
+		object["year"], err = json.Marshal(a.Year)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'year': %w", err)
+		}
+	}
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling '%s': %w", fieldName, err)
+		}
+	}
+	return json.Marshal(object)
+}
+
+// RequestEditorFn  is the function signature for the RequestEditor callback function
+type RequestEditorFn func(ctx context.Context, req *http.Request) error
+
+// Doer performs HTTP requests.
+//
+// The standard http.Client implements this interface.
+type HttpRequestDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client which conforms to the OpenAPI3 specification for this service.
+type Client struct {
+	// The endpoint of the server conforming to this interface, with scheme,
+	// https://api.deepmap.com for example. This can contain a path relative
+	// to the server, such as https://api.deepmap.com/dev-test, and all the
+	// paths in the swagger spec will be appended to the server.
+	Server string
+
+	// Doer for performing requests, typically a *http.Client with any
+	// customized settings, such as certificate chains.
+	Client HttpRequestDoer
+
+	// A list of callbacks for modifying requests which are generated before sending over
+	// the network.
+	RequestEditors []RequestEditorFn
+}
+
+// ClientOption allows setting custom parameters during construction
+type ClientOption func(*Client) error
+
+// Creates a new Client, with reasonable defaults
+func NewClient(server string, opts ...ClientOption) (*Client, error) {
+	// create a client with sane default values
+	client := Client{
+		Server: server,
+	}
+	// mutate client and add all optional params
+	for _, o := range opts {
+		if err := o(&client); err != nil {
+			return nil, err
+		}
+	}
+	// ensure the server URL always has a trailing slash
+	if !strings.HasSuffix(client.Server, "/") {
+		client.Server += "/"
+	}
+	// create httpClient, if not already present
+	if client.Client == nil {
+		client.Client = &http.Client{}
+	}
+	return &client, nil
+}
+
+// WithHTTPClient allows overriding the default Doer, which is
+// automatically created using http.Client. This is useful for tests.
+func WithHTTPClient(doer HttpRequestDoer) ClientOption {
+	return func(c *Client) error {
+		c.Client = doer
+		return nil
+	}
+}
+
+// WithRequestEditorFn allows setting up a callback function, which will be
+// called right before sending the request. This can be used to mutate the request.
+func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
+	return func(c *Client) error {
+		c.RequestEditors = append(c.RequestEditors, fn)
+		return nil
+	}
+}
+
+// The interface specification for the client above.
+type ClientInterface interface {
+	// SearchAnime request
+	SearchAnime(ctx context.Context, params *SearchAnimeParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetAnime request
+	GetAnime(ctx context.Context, animeId AnimeId, params *GetAnimeParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListEpisodes request
+	ListEpisodes(ctx context.Context, animeId AnimeId, params *ListEpisodesParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetEpisode request
+	GetEpisode(ctx context.Context, animeId AnimeId, episodeNum EpisodeNum, params *GetEpisodeParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListDownloads request
+	ListDownloads(ctx context.Context, params *ListDownloadsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// AddDownloadsWithBody request with any body
+	AddDownloadsWithBody(ctx context.Context, params *AddDownloadsParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	AddDownloads(ctx context.Context, params *AddDownloadsParams, body AddDownloadsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// CancelDownload request
+	CancelDownload(ctx context.Context, jobId JobId, params *CancelDownloadParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetDownload request
+	GetDownload(ctx context.Context, jobId JobId, params *GetDownloadParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PauseDownload request
+	PauseDownload(ctx context.Context, jobId JobId, params *PauseDownloadParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ResumeDownload request
+	ResumeDownload(ctx context.Context, jobId JobId, params *ResumeDownloadParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// StreamEvents request
+	StreamEvents(ctx context.Context, params *StreamEventsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListJellyfinItems request
+	ListJellyfinItems(ctx context.Context, params *ListJellyfinItemsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// AddJellyfinItemWithBody request with any body
+	AddJellyfinItemWithBody(ctx context.Context, params *AddJellyfinItemParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	AddJellyfinItem(ctx context.Context, params *AddJellyfinItemParams, body AddJellyfinItemJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// RemoveJellyfinItem request
+	RemoveJellyfinItem(ctx context.Context, animeId AnimeId, params *RemoveJellyfinItemParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetJellyfinItem request
+	GetJellyfinItem(ctx context.Context, animeId AnimeId, params *GetJellyfinItemParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// UpdateJellyfinItemWithBody request with any body
+	UpdateJellyfinItemWithBody(ctx context.Context, animeId AnimeId, params *UpdateJellyfinItemParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	UpdateJellyfinItem(ctx context.Context, animeId AnimeId, params *UpdateJellyfinItemParams, body UpdateJellyfinItemJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// RefreshJellyfin request
+	RefreshJellyfin(ctx context.Context, params *RefreshJellyfinParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetJellyfinStatus request
+	GetJellyfinStatus(ctx context.Context, params *GetJellyfinStatusParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListProfiles request
+	ListProfiles(ctx context.Context, params *ListProfilesParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// CreateProfileWithBody request with any body
+	CreateProfileWithBody(ctx context.Context, params *CreateProfileParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	CreateProfile(ctx context.Context, params *CreateProfileParams, body CreateProfileJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetSettings request
+	GetSettings(ctx context.Context, params *GetSettingsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// UpdateSettingsWithBody request with any body
+	UpdateSettingsWithBody(ctx context.Context, params *UpdateSettingsParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	UpdateSettings(ctx context.Context, params *UpdateSettingsParams, body UpdateSettingsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// StreamEpisode request
+	StreamEpisode(ctx context.Context, animeId AnimeId, episodeNum EpisodeNum, params *StreamEpisodeParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListWatchlist request
+	ListWatchlist(ctx context.Context, params *ListWatchlistParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// AddToWatchlistWithBody request with any body
+	AddToWatchlistWithBody(ctx context.Context, params *AddToWatchlistParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	AddToWatchlist(ctx context.Context, params *AddToWatchlistParams, body AddToWatchlistJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// RemoveFromWatchlist request
+	RemoveFromWatchlist(ctx context.Context, source Source, animeId AnimeId, params *RemoveFromWatchlistParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetWatchlistEntry request
+	GetWatchlistEntry(ctx context.Context, source Source, animeId AnimeId, params *GetWatchlistEntryParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// UpdateWatchlistEntryWithBody request with any body
+	UpdateWatchlistEntryWithBody(ctx context.Context, source Source, animeId AnimeId, params *UpdateWatchlistEntryParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	UpdateWatchlistEntry(ctx context.Context, source Source, animeId AnimeId, params *UpdateWatchlistEntryParams, body UpdateWatchlistEntryJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+}
+
+func (c *Client) SearchAnime(ctx context.Context, params *SearchAnimeParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSearchAnimeRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetAnime(ctx context.Context, animeId AnimeId, params *GetAnimeParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetAnimeRequest(c.Server, animeId, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListEpisodes(ctx context.Context, animeId AnimeId, params *ListEpisodesParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListEpisodesRequest(c.Server, animeId, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetEpisode(ctx context.Context, animeId AnimeId, episodeNum EpisodeNum, params *GetEpisodeParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetEpisodeRequest(c.Server, animeId, episodeNum, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListDownloads(ctx context.Context, params *ListDownloadsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListDownloadsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) AddDownloadsWithBody(ctx context.Context, params *AddDownloadsParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewAddDownloadsRequestWithBody(c.Server, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) AddDownloads(ctx context.Context, params *AddDownloadsParams, body AddDownloadsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewAddDownloadsRequest(c.Server, params, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CancelDownload(ctx context.Context, jobId JobId, params *CancelDownloadParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCancelDownloadRequest(c.Server, jobId, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetDownload(ctx context.Context, jobId JobId, params *GetDownloadParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetDownloadRequest(c.Server, jobId, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PauseDownload(ctx context.Context, jobId JobId, params *PauseDownloadParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPauseDownloadRequest(c.Server, jobId, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ResumeDownload(ctx context.Context, jobId JobId, params *ResumeDownloadParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewResumeDownloadRequest(c.Server, jobId, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) StreamEvents(ctx context.Context, params *StreamEventsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewStreamEventsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListJellyfinItems(ctx context.Context, params *ListJellyfinItemsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListJellyfinItemsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) AddJellyfinItemWithBody(ctx context.Context, params *AddJellyfinItemParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewAddJellyfinItemRequestWithBody(c.Server, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) AddJellyfinItem(ctx context.Context, params *AddJellyfinItemParams, body AddJellyfinItemJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewAddJellyfinItemRequest(c.Server, params, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) RemoveJellyfinItem(ctx context.Context, animeId AnimeId, params *RemoveJellyfinItemParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewRemoveJellyfinItemRequest(c.Server, animeId, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetJellyfinItem(ctx context.Context, animeId AnimeId, params *GetJellyfinItemParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetJellyfinItemRequest(c.Server, animeId, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) UpdateJellyfinItemWithBody(ctx context.Context, animeId AnimeId, params *UpdateJellyfinItemParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateJellyfinItemRequestWithBody(c.Server, animeId, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) UpdateJellyfinItem(ctx context.Context, animeId AnimeId, params *UpdateJellyfinItemParams, body UpdateJellyfinItemJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateJellyfinItemRequest(c.Server, animeId, params, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) RefreshJellyfin(ctx context.Context, params *RefreshJellyfinParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewRefreshJellyfinRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetJellyfinStatus(ctx context.Context, params *GetJellyfinStatusParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetJellyfinStatusRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListProfiles(ctx context.Context, params *ListProfilesParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListProfilesRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CreateProfileWithBody(ctx context.Context, params *CreateProfileParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreateProfileRequestWithBody(c.Server, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CreateProfile(ctx context.Context, params *CreateProfileParams, body CreateProfileJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreateProfileRequest(c.Server, params, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetSettings(ctx context.Context, params *GetSettingsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetSettingsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) UpdateSettingsWithBody(ctx context.Context, params *UpdateSettingsParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateSettingsRequestWithBody(c.Server, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) UpdateSettings(ctx context.Context, params *UpdateSettingsParams, body UpdateSettingsJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateSettingsRequest(c.Server, params, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) StreamEpisode(ctx context.Context, animeId AnimeId, episodeNum EpisodeNum, params *StreamEpisodeParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewStreamEpisodeRequest(c.Server, animeId, episodeNum, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ListWatchlist(ctx context.Context, params *ListWatchlistParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListWatchlistRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) AddToWatchlistWithBody(ctx context.Context, params *AddToWatchlistParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewAddToWatchlistRequestWithBody(c.Server, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) AddToWatchlist(ctx context.Context, params *AddToWatchlistParams, body AddToWatchlistJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewAddToWatchlistRequest(c.Server, params, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) RemoveFromWatchlist(ctx context.Context, source Source, animeId AnimeId, params *RemoveFromWatchlistParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewRemoveFromWatchlistRequest(c.Server, source, animeId, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetWatchlistEntry(ctx context.Context, source Source, animeId AnimeId, params *GetWatchlistEntryParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetWatchlistEntryRequest(c.Server, source, animeId, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) UpdateWatchlistEntryWithBody(ctx context.Context, source Source, animeId AnimeId, params *UpdateWatchlistEntryParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateWatchlistEntryRequestWithBody(c.Server, source, animeId, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) UpdateWatchlistEntry(ctx context.Context, source Source, animeId AnimeId, params *UpdateWatchlistEntryParams, body UpdateWatchlistEntryJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUpdateWatchlistEntryRequest(c.Server, source, animeId, params, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+// NewSearchAnimeRequest generates requests for SearchAnime
+func NewSearchAnimeRequest(server string, params *SearchAnimeParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/anime")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "q", runtime.ParamLocationQuery, params.Q); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewGetAnimeRequest generates requests for GetAnime
+func NewGetAnimeRequest(server string, animeId AnimeId, params *GetAnimeParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "animeId", runtime.ParamLocationPath, animeId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/anime/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewListEpisodesRequest generates requests for ListEpisodes
+func NewListEpisodesRequest(server string, animeId AnimeId, params *ListEpisodesParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "animeId", runtime.ParamLocationPath, animeId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/anime/%s/episodes", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewGetEpisodeRequest generates requests for GetEpisode
+func NewGetEpisodeRequest(server string, animeId AnimeId, episodeNum EpisodeNum, params *GetEpisodeParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "animeId", runtime.ParamLocationPath, animeId)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "episodeNum", runtime.ParamLocationPath, episodeNum)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/anime/%s/episodes/%s", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewListDownloadsRequest generates requests for ListDownloads
+func NewListDownloadsRequest(server string, params *ListDownloadsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/downloads")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewAddDownloadsRequest calls the generic AddDownloads builder with application/json body
+func NewAddDownloadsRequest(server string, params *AddDownloadsParams, body AddDownloadsJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewAddDownloadsRequestWithBody(server, params, "application/json", bodyReader)
+}
+
+// NewAddDownloadsRequestWithBody generates requests for AddDownloads with any type of body
+func NewAddDownloadsRequestWithBody(server string, params *AddDownloadsParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/downloads")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewCancelDownloadRequest generates requests for CancelDownload
+func NewCancelDownloadRequest(server string, jobId JobId, params *CancelDownloadParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "jobId", runtime.ParamLocationPath, jobId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/downloads/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewGetDownloadRequest generates requests for GetDownload
+func NewGetDownloadRequest(server string, jobId JobId, params *GetDownloadParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "jobId", runtime.ParamLocationPath, jobId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/downloads/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewPauseDownloadRequest generates requests for PauseDownload
+func NewPauseDownloadRequest(server string, jobId JobId, params *PauseDownloadParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "jobId", runtime.ParamLocationPath, jobId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/downloads/%s/pause", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewResumeDownloadRequest generates requests for ResumeDownload
+func NewResumeDownloadRequest(server string, jobId JobId, params *ResumeDownloadParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "jobId", runtime.ParamLocationPath, jobId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/downloads/%s/resume", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewStreamEventsRequest generates requests for StreamEvents
+func NewStreamEventsRequest(server string, params *StreamEventsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/events")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Types != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "types", runtime.ParamLocationQuery, *params.Types); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.LastEventId != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "lastEventId", runtime.ParamLocationQuery, *params.LastEventId); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.LastEventID != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "Last-Event-ID", runtime.ParamLocationHeader, *params.LastEventID)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("Last-Event-ID", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewListJellyfinItemsRequest generates requests for ListJellyfinItems
+func NewListJellyfinItemsRequest(server string, params *ListJellyfinItemsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/jellyfin/items")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewAddJellyfinItemRequest calls the generic AddJellyfinItem builder with application/json body
+func NewAddJellyfinItemRequest(server string, params *AddJellyfinItemParams, body AddJellyfinItemJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewAddJellyfinItemRequestWithBody(server, params, "application/json", bodyReader)
+}
+
+// NewAddJellyfinItemRequestWithBody generates requests for AddJellyfinItem with any type of body
+func NewAddJellyfinItemRequestWithBody(server string, params *AddJellyfinItemParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/jellyfin/items")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewRemoveJellyfinItemRequest generates requests for RemoveJellyfinItem
+func NewRemoveJellyfinItemRequest(server string, animeId AnimeId, params *RemoveJellyfinItemParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "animeId", runtime.ParamLocationPath, animeId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/jellyfin/items/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewGetJellyfinItemRequest generates requests for GetJellyfinItem
+func NewGetJellyfinItemRequest(server string, animeId AnimeId, params *GetJellyfinItemParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "animeId", runtime.ParamLocationPath, animeId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/jellyfin/items/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewUpdateJellyfinItemRequest calls the generic UpdateJellyfinItem builder with application/json body
+func NewUpdateJellyfinItemRequest(server string, animeId AnimeId, params *UpdateJellyfinItemParams, body UpdateJellyfinItemJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewUpdateJellyfinItemRequestWithBody(server, animeId, params, "application/json", bodyReader)
+}
+
+// NewUpdateJellyfinItemRequestWithBody generates requests for UpdateJellyfinItem with any type of body
+func NewUpdateJellyfinItemRequestWithBody(server string, animeId AnimeId, params *UpdateJellyfinItemParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "animeId", runtime.ParamLocationPath, animeId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/jellyfin/items/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PATCH", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewRefreshJellyfinRequest generates requests for RefreshJellyfin
+func NewRefreshJellyfinRequest(server string, params *RefreshJellyfinParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/jellyfin/refresh")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewGetJellyfinStatusRequest generates requests for GetJellyfinStatus
+func NewGetJellyfinStatusRequest(server string, params *GetJellyfinStatusParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/jellyfin/status")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewListProfilesRequest generates requests for ListProfiles
+func NewListProfilesRequest(server string, params *ListProfilesParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/profiles")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewCreateProfileRequest calls the generic CreateProfile builder with application/json body
+func NewCreateProfileRequest(server string, params *CreateProfileParams, body CreateProfileJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewCreateProfileRequestWithBody(server, params, "application/json", bodyReader)
+}
+
+// NewCreateProfileRequestWithBody generates requests for CreateProfile with any type of body
+func NewCreateProfileRequestWithBody(server string, params *CreateProfileParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/profiles")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewGetSettingsRequest generates requests for GetSettings
+func NewGetSettingsRequest(server string, params *GetSettingsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/settings")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewUpdateSettingsRequest calls the generic UpdateSettings builder with application/json body
+func NewUpdateSettingsRequest(server string, params *UpdateSettingsParams, body UpdateSettingsJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewUpdateSettingsRequestWithBody(server, params, "application/json", bodyReader)
+}
+
+// NewUpdateSettingsRequestWithBody generates requests for UpdateSettings with any type of body
+func NewUpdateSettingsRequestWithBody(server string, params *UpdateSettingsParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/settings")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PATCH", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewStreamEpisodeRequest generates requests for StreamEpisode
+func NewStreamEpisodeRequest(server string, animeId AnimeId, episodeNum EpisodeNum, params *StreamEpisodeParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "animeId", runtime.ParamLocationPath, animeId)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "episodeNum", runtime.ParamLocationPath, episodeNum)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/stream/%s/%s", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Profile != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "profile", runtime.ParamLocationQuery, *params.Profile); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Res != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "res", runtime.ParamLocationQuery, *params.Res); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+		if params.Range != nil {
+			var headerParam1 string
+
+			headerParam1, err = runtime.StyleParamWithLocation("simple", false, "Range", runtime.ParamLocationHeader, *params.Range)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("Range", headerParam1)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewListWatchlistRequest generates requests for ListWatchlist
+func NewListWatchlistRequest(server string, params *ListWatchlistParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/watchlist")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Status != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "status", runtime.ParamLocationQuery, *params.Status); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Favorites != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "favorites", runtime.ParamLocationQuery, *params.Favorites); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewAddToWatchlistRequest calls the generic AddToWatchlist builder with application/json body
+func NewAddToWatchlistRequest(server string, params *AddToWatchlistParams, body AddToWatchlistJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewAddToWatchlistRequestWithBody(server, params, "application/json", bodyReader)
+}
+
+// NewAddToWatchlistRequestWithBody generates requests for AddToWatchlist with any type of body
+func NewAddToWatchlistRequestWithBody(server string, params *AddToWatchlistParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/watchlist")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewRemoveFromWatchlistRequest generates requests for RemoveFromWatchlist
+func NewRemoveFromWatchlistRequest(server string, source Source, animeId AnimeId, params *RemoveFromWatchlistParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "source", runtime.ParamLocationPath, source)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "animeId", runtime.ParamLocationPath, animeId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/watchlist/%s/%s", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewGetWatchlistEntryRequest generates requests for GetWatchlistEntry
+func NewGetWatchlistEntryRequest(server string, source Source, animeId AnimeId, params *GetWatchlistEntryParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "source", runtime.ParamLocationPath, source)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "animeId", runtime.ParamLocationPath, animeId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/watchlist/%s/%s", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewUpdateWatchlistEntryRequest calls the generic UpdateWatchlistEntry builder with application/json body
+func NewUpdateWatchlistEntryRequest(server string, source Source, animeId AnimeId, params *UpdateWatchlistEntryParams, body UpdateWatchlistEntryJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewUpdateWatchlistEntryRequestWithBody(server, source, animeId, params, "application/json", bodyReader)
+}
+
+// NewUpdateWatchlistEntryRequestWithBody generates requests for UpdateWatchlistEntry with any type of body
+func NewUpdateWatchlistEntryRequestWithBody(server string, source Source, animeId AnimeId, params *UpdateWatchlistEntryParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "source", runtime.ParamLocationPath, source)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "animeId", runtime.ParamLocationPath, animeId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/watchlist/%s/%s", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PATCH", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		if params.XAniProfile != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "X-Ani-Profile", runtime.ParamLocationHeader, *params.XAniProfile)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-Ani-Profile", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
+	for _, r := range c.RequestEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, r := range additionalEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClientWithResponses builds on ClientInterface to offer response payloads
+type ClientWithResponses struct {
+	ClientInterface
+}
+
+// NewClientWithResponses creates a new ClientWithResponses, which wraps
+// Client with return type handling
+func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
+	client, err := NewClient(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientWithResponses{client}, nil
+}
+
+// WithBaseURL overrides the baseURL.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		newBaseURL, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		c.Server = newBaseURL.String()
+		return nil
+	}
+}
+
+// ClientWithResponsesInterface is the interface specification for the client with responses above.
+type ClientWithResponsesInterface interface {
+	// SearchAnimeWithResponse request
+	SearchAnimeWithResponse(ctx context.Context, params *SearchAnimeParams, reqEditors ...RequestEditorFn) (*SearchAnimeResponse, error)
+
+	// GetAnimeWithResponse request
+	GetAnimeWithResponse(ctx context.Context, animeId AnimeId, params *GetAnimeParams, reqEditors ...RequestEditorFn) (*GetAnimeResponse, error)
+
+	// ListEpisodesWithResponse request
+	ListEpisodesWithResponse(ctx context.Context, animeId AnimeId, params *ListEpisodesParams, reqEditors ...RequestEditorFn) (*ListEpisodesResponse, error)
+
+	// GetEpisodeWithResponse request
+	GetEpisodeWithResponse(ctx context.Context, animeId AnimeId, episodeNum EpisodeNum, params *GetEpisodeParams, reqEditors ...RequestEditorFn) (*GetEpisodeResponse, error)
+
+	// ListDownloadsWithResponse request
+	ListDownloadsWithResponse(ctx context.Context, params *ListDownloadsParams, reqEditors ...RequestEditorFn) (*ListDownloadsResponse, error)
+
+	// AddDownloadsWithBodyWithResponse request with any body
+	AddDownloadsWithBodyWithResponse(ctx context.Context, params *AddDownloadsParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*AddDownloadsResponse, error)
+
+	AddDownloadsWithResponse(ctx context.Context, params *AddDownloadsParams, body AddDownloadsJSONRequestBody, reqEditors ...RequestEditorFn) (*AddDownloadsResponse, error)
+
+	// CancelDownloadWithResponse request
+	CancelDownloadWithResponse(ctx context.Context, jobId JobId, params *CancelDownloadParams, reqEditors ...RequestEditorFn) (*CancelDownloadResponse, error)
+
+	// GetDownloadWithResponse request
+	GetDownloadWithResponse(ctx context.Context, jobId JobId, params *GetDownloadParams, reqEditors ...RequestEditorFn) (*GetDownloadResponse, error)
+
+	// PauseDownloadWithResponse request
+	PauseDownloadWithResponse(ctx context.Context, jobId JobId, params *PauseDownloadParams, reqEditors ...RequestEditorFn) (*PauseDownloadResponse, error)
+
+	// ResumeDownloadWithResponse request
+	ResumeDownloadWithResponse(ctx context.Context, jobId JobId, params *ResumeDownloadParams, reqEditors ...RequestEditorFn) (*ResumeDownloadResponse, error)
+
+	// StreamEventsWithResponse request
+	StreamEventsWithResponse(ctx context.Context, params *StreamEventsParams, reqEditors ...RequestEditorFn) (*StreamEventsResponse, error)
+
+	// ListJellyfinItemsWithResponse request
+	ListJellyfinItemsWithResponse(ctx context.Context, params *ListJellyfinItemsParams, reqEditors ...RequestEditorFn) (*ListJellyfinItemsResponse, error)
+
+	// AddJellyfinItemWithBodyWithResponse request with any body
+	AddJellyfinItemWithBodyWithResponse(ctx context.Context, params *AddJellyfinItemParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*AddJellyfinItemResponse, error)
+
+	AddJellyfinItemWithResponse(ctx context.Context, params *AddJellyfinItemParams, body AddJellyfinItemJSONRequestBody, reqEditors ...RequestEditorFn) (*AddJellyfinItemResponse, error)
+
+	// RemoveJellyfinItemWithResponse request
+	RemoveJellyfinItemWithResponse(ctx context.Context, animeId AnimeId, params *RemoveJellyfinItemParams, reqEditors ...RequestEditorFn) (*RemoveJellyfinItemResponse, error)
+
+	// GetJellyfinItemWithResponse request
+	GetJellyfinItemWithResponse(ctx context.Context, animeId AnimeId, params *GetJellyfinItemParams, reqEditors ...RequestEditorFn) (*GetJellyfinItemResponse, error)
+
+	// UpdateJellyfinItemWithBodyWithResponse request with any body
+	UpdateJellyfinItemWithBodyWithResponse(ctx context.Context, animeId AnimeId, params *UpdateJellyfinItemParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateJellyfinItemResponse, error)
+
+	UpdateJellyfinItemWithResponse(ctx context.Context, animeId AnimeId, params *UpdateJellyfinItemParams, body UpdateJellyfinItemJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateJellyfinItemResponse, error)
+
+	// RefreshJellyfinWithResponse request
+	RefreshJellyfinWithResponse(ctx context.Context, params *RefreshJellyfinParams, reqEditors ...RequestEditorFn) (*RefreshJellyfinResponse, error)
+
+	// GetJellyfinStatusWithResponse request
+	GetJellyfinStatusWithResponse(ctx context.Context, params *GetJellyfinStatusParams, reqEditors ...RequestEditorFn) (*GetJellyfinStatusResponse, error)
+
+	// ListProfilesWithResponse request
+	ListProfilesWithResponse(ctx context.Context, params *ListProfilesParams, reqEditors ...RequestEditorFn) (*ListProfilesResponse, error)
+
+	// CreateProfileWithBodyWithResponse request with any body
+	CreateProfileWithBodyWithResponse(ctx context.Context, params *CreateProfileParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateProfileResponse, error)
+
+	CreateProfileWithResponse(ctx context.Context, params *CreateProfileParams, body CreateProfileJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateProfileResponse, error)
+
+	// GetSettingsWithResponse request
+	GetSettingsWithResponse(ctx context.Context, params *GetSettingsParams, reqEditors ...RequestEditorFn) (*GetSettingsResponse, error)
+
+	// UpdateSettingsWithBodyWithResponse request with any body
+	UpdateSettingsWithBodyWithResponse(ctx context.Context, params *UpdateSettingsParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateSettingsResponse, error)
+
+	UpdateSettingsWithResponse(ctx context.Context, params *UpdateSettingsParams, body UpdateSettingsJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateSettingsResponse, error)
+
+	// StreamEpisodeWithResponse request
+	StreamEpisodeWithResponse(ctx context.Context, animeId AnimeId, episodeNum EpisodeNum, params *StreamEpisodeParams, reqEditors ...RequestEditorFn) (*StreamEpisodeResponse, error)
+
+	// ListWatchlistWithResponse request
+	ListWatchlistWithResponse(ctx context.Context, params *ListWatchlistParams, reqEditors ...RequestEditorFn) (*ListWatchlistResponse, error)
+
+	// AddToWatchlistWithBodyWithResponse request with any body
+	AddToWatchlistWithBodyWithResponse(ctx context.Context, params *AddToWatchlistParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*AddToWatchlistResponse, error)
+
+	AddToWatchlistWithResponse(ctx context.Context, params *AddToWatchlistParams, body AddToWatchlistJSONRequestBody, reqEditors ...RequestEditorFn) (*AddToWatchlistResponse, error)
+
+	// RemoveFromWatchlistWithResponse request
+	RemoveFromWatchlistWithResponse(ctx context.Context, source Source, animeId AnimeId, params *RemoveFromWatchlistParams, reqEditors ...RequestEditorFn) (*RemoveFromWatchlistResponse, error)
+
+	// GetWatchlistEntryWithResponse request
+	GetWatchlistEntryWithResponse(ctx context.Context, source Source, animeId AnimeId, params *GetWatchlistEntryParams, reqEditors ...RequestEditorFn) (*GetWatchlistEntryResponse, error)
+
+	// UpdateWatchlistEntryWithBodyWithResponse request with any body
+	UpdateWatchlistEntryWithBodyWithResponse(ctx context.Context, source Source, animeId AnimeId, params *UpdateWatchlistEntryParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateWatchlistEntryResponse, error)
+
+	UpdateWatchlistEntryWithResponse(ctx context.Context, source Source, animeId AnimeId, params *UpdateWatchlistEntryParams, body UpdateWatchlistEntryJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateWatchlistEntryResponse, error)
+}
+
+type SearchAnimeResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Data []Anime `json:"data"`
+	}
+	JSON400 *BadRequest
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r SearchAnimeResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SearchAnimeResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetAnimeResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Data Anime `json:"data"`
+	}
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON404 *NotFound
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r GetAnimeResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetAnimeResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListEpisodesResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Data []Episode `json:"data"`
+	}
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON404 *NotFound
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r ListEpisodesResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListEpisodesResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetEpisodeResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Data EpisodeDetails `json:"data"`
+	}
+	JSON400 *BadRequest
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON404 *NotFound
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r GetEpisodeResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetEpisodeResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListDownloadsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Data []DownloadJob `json:"data"`
+	}
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r ListDownloadsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListDownloadsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type AddDownloadsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON201      *struct {
+		Data []DownloadJob `json:"data"`
+	}
+	JSON400 *BadRequest
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON404 *NotFound
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r AddDownloadsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r AddDownloadsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CancelDownloadResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *BadRequest
+	JSON401      *Unauthorized
+	JSON403      *Forbidden
+	JSON404      *NotFound
+	JSON429      *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r CancelDownloadResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CancelDownloadResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetDownloadResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Data DownloadJob `json:"data"`
+	}
+	JSON400 *BadRequest
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON404 *NotFound
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r GetDownloadResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetDownloadResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PauseDownloadResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Data DownloadJob `json:"data"`
+	}
+	JSON400 *BadRequest
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON404 *NotFound
+	JSON409 *Conflict
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r PauseDownloadResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PauseDownloadResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ResumeDownloadResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Data DownloadJob `json:"data"`
+	}
+	JSON400 *BadRequest
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON404 *NotFound
+	JSON409 *Conflict
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r ResumeDownloadResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ResumeDownloadResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type StreamEventsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON401      *AuthError
+	JSON403      *AuthError
+	JSON429      *struct {
+		Message string `json:"message"`
+	}
+}
+
+// Status returns HTTPResponse.Status
+func (r StreamEventsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r StreamEventsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListJellyfinItemsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Data []JellyfinItem `json:"data"`
+	}
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r ListJellyfinItemsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListJellyfinItemsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type AddJellyfinItemResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON201      *struct {
+		Data JellyfinItem `json:"data"`
+	}
+	JSON400 *BadRequest
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON404 *NotFound
+	JSON409 *Conflict
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r AddJellyfinItemResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r AddJellyfinItemResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type RemoveJellyfinItemResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON401      *Unauthorized
+	JSON403      *Forbidden
+	JSON404      *NotFound
+	JSON429      *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r RemoveJellyfinItemResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r RemoveJellyfinItemResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetJellyfinItemResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Data JellyfinItem `json:"data"`
+	}
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON404 *NotFound
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r GetJellyfinItemResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetJellyfinItemResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type UpdateJellyfinItemResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Data JellyfinItem `json:"data"`
+	}
+	JSON400 *BadRequest
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON404 *NotFound
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r UpdateJellyfinItemResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r UpdateJellyfinItemResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type RefreshJellyfinResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON202      *struct {
+		Data JellyfinStatus `json:"data"`
+	}
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON409 *Conflict
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r RefreshJellyfinResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r RefreshJellyfinResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetJellyfinStatusResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Data JellyfinStatus `json:"data"`
+	}
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r GetJellyfinStatusResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetJellyfinStatusResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListProfilesResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Data []Profile `json:"data"`
+	}
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r ListProfilesResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListProfilesResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CreateProfileResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON201      *struct {
+		Data ProfileName `json:"data"`
+	}
+	JSON400 *BadRequest
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON409 *Conflict
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r CreateProfileResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CreateProfileResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetSettingsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Data Settings `json:"data"`
+	}
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r GetSettingsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetSettingsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type UpdateSettingsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Data Settings `json:"data"`
+	}
+	JSON400 *BadRequest
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r UpdateSettingsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r UpdateSettingsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type StreamEpisodeResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON401      *AuthError
+	JSON403      *AuthError
+	JSON429      *struct {
+		Message string `json:"message"`
+	}
+}
+
+// Status returns HTTPResponse.Status
+func (r StreamEpisodeResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r StreamEpisodeResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListWatchlistResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Data []WatchlistEntry `json:"data"`
+	}
+	JSON400 *BadRequest
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r ListWatchlistResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListWatchlistResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type AddToWatchlistResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON201      *struct {
+		Data WatchlistEntry `json:"data"`
+	}
+	JSON400 *BadRequest
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON404 *NotFound
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r AddToWatchlistResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r AddToWatchlistResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type RemoveFromWatchlistResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON401      *Unauthorized
+	JSON403      *Forbidden
+	JSON404      *NotFound
+	JSON429      *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r RemoveFromWatchlistResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r RemoveFromWatchlistResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetWatchlistEntryResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Data WatchlistEntry `json:"data"`
+	}
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON404 *NotFound
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r GetWatchlistEntryResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetWatchlistEntryResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type UpdateWatchlistEntryResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Data WatchlistEntry `json:"data"`
+	}
+	JSON400 *BadRequest
+	JSON401 *Unauthorized
+	JSON403 *Forbidden
+	JSON404 *NotFound
+	JSON429 *TooManyRequests
+}
+
+// Status returns HTTPResponse.Status
+func (r UpdateWatchlistEntryResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r UpdateWatchlistEntryResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// SearchAnimeWithResponse request returning *SearchAnimeResponse
+func (c *ClientWithResponses) SearchAnimeWithResponse(ctx context.Context, params *SearchAnimeParams, reqEditors ...RequestEditorFn) (*SearchAnimeResponse, error) {
+	rsp, err := c.SearchAnime(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSearchAnimeResponse(rsp)
+}
+
+// GetAnimeWithResponse request returning *GetAnimeResponse
+func (c *ClientWithResponses) GetAnimeWithResponse(ctx context.Context, animeId AnimeId, params *GetAnimeParams, reqEditors ...RequestEditorFn) (*GetAnimeResponse, error) {
+	rsp, err := c.GetAnime(ctx, animeId, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetAnimeResponse(rsp)
+}
+
+// ListEpisodesWithResponse request returning *ListEpisodesResponse
+func (c *ClientWithResponses) ListEpisodesWithResponse(ctx context.Context, animeId AnimeId, params *ListEpisodesParams, reqEditors ...RequestEditorFn) (*ListEpisodesResponse, error) {
+	rsp, err := c.ListEpisodes(ctx, animeId, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListEpisodesResponse(rsp)
+}
+
+// GetEpisodeWithResponse request returning *GetEpisodeResponse
+func (c *ClientWithResponses) GetEpisodeWithResponse(ctx context.Context, animeId AnimeId, episodeNum EpisodeNum, params *GetEpisodeParams, reqEditors ...RequestEditorFn) (*GetEpisodeResponse, error) {
+	rsp, err := c.GetEpisode(ctx, animeId, episodeNum, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetEpisodeResponse(rsp)
+}
+
+// ListDownloadsWithResponse request returning *ListDownloadsResponse
+func (c *ClientWithResponses) ListDownloadsWithResponse(ctx context.Context, params *ListDownloadsParams, reqEditors ...RequestEditorFn) (*ListDownloadsResponse, error) {
+	rsp, err := c.ListDownloads(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListDownloadsResponse(rsp)
+}
+
+// AddDownloadsWithBodyWithResponse request with arbitrary body returning *AddDownloadsResponse
+func (c *ClientWithResponses) AddDownloadsWithBodyWithResponse(ctx context.Context, params *AddDownloadsParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*AddDownloadsResponse, error) {
+	rsp, err := c.AddDownloadsWithBody(ctx, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAddDownloadsResponse(rsp)
+}
+
+func (c *ClientWithResponses) AddDownloadsWithResponse(ctx context.Context, params *AddDownloadsParams, body AddDownloadsJSONRequestBody, reqEditors ...RequestEditorFn) (*AddDownloadsResponse, error) {
+	rsp, err := c.AddDownloads(ctx, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAddDownloadsResponse(rsp)
+}
+
+// CancelDownloadWithResponse request returning *CancelDownloadResponse
+func (c *ClientWithResponses) CancelDownloadWithResponse(ctx context.Context, jobId JobId, params *CancelDownloadParams, reqEditors ...RequestEditorFn) (*CancelDownloadResponse, error) {
+	rsp, err := c.CancelDownload(ctx, jobId, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCancelDownloadResponse(rsp)
+}
+
+// GetDownloadWithResponse request returning *GetDownloadResponse
+func (c *ClientWithResponses) GetDownloadWithResponse(ctx context.Context, jobId JobId, params *GetDownloadParams, reqEditors ...RequestEditorFn) (*GetDownloadResponse, error) {
+	rsp, err := c.GetDownload(ctx, jobId, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetDownloadResponse(rsp)
+}
+
+// PauseDownloadWithResponse request returning *PauseDownloadResponse
+func (c *ClientWithResponses) PauseDownloadWithResponse(ctx context.Context, jobId JobId, params *PauseDownloadParams, reqEditors ...RequestEditorFn) (*PauseDownloadResponse, error) {
+	rsp, err := c.PauseDownload(ctx, jobId, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePauseDownloadResponse(rsp)
+}
+
+// ResumeDownloadWithResponse request returning *ResumeDownloadResponse
+func (c *ClientWithResponses) ResumeDownloadWithResponse(ctx context.Context, jobId JobId, params *ResumeDownloadParams, reqEditors ...RequestEditorFn) (*ResumeDownloadResponse, error) {
+	rsp, err := c.ResumeDownload(ctx, jobId, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseResumeDownloadResponse(rsp)
+}
+
+// StreamEventsWithResponse request returning *StreamEventsResponse
+func (c *ClientWithResponses) StreamEventsWithResponse(ctx context.Context, params *StreamEventsParams, reqEditors ...RequestEditorFn) (*StreamEventsResponse, error) {
+	rsp, err := c.StreamEvents(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseStreamEventsResponse(rsp)
+}
+
+// ListJellyfinItemsWithResponse request returning *ListJellyfinItemsResponse
+func (c *ClientWithResponses) ListJellyfinItemsWithResponse(ctx context.Context, params *ListJellyfinItemsParams, reqEditors ...RequestEditorFn) (*ListJellyfinItemsResponse, error) {
+	rsp, err := c.ListJellyfinItems(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListJellyfinItemsResponse(rsp)
+}
+
+// AddJellyfinItemWithBodyWithResponse request with arbitrary body returning *AddJellyfinItemResponse
+func (c *ClientWithResponses) AddJellyfinItemWithBodyWithResponse(ctx context.Context, params *AddJellyfinItemParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*AddJellyfinItemResponse, error) {
+	rsp, err := c.AddJellyfinItemWithBody(ctx, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAddJellyfinItemResponse(rsp)
+}
+
+func (c *ClientWithResponses) AddJellyfinItemWithResponse(ctx context.Context, params *AddJellyfinItemParams, body AddJellyfinItemJSONRequestBody, reqEditors ...RequestEditorFn) (*AddJellyfinItemResponse, error) {
+	rsp, err := c.AddJellyfinItem(ctx, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAddJellyfinItemResponse(rsp)
+}
+
+// RemoveJellyfinItemWithResponse request returning *RemoveJellyfinItemResponse
+func (c *ClientWithResponses) RemoveJellyfinItemWithResponse(ctx context.Context, animeId AnimeId, params *RemoveJellyfinItemParams, reqEditors ...RequestEditorFn) (*RemoveJellyfinItemResponse, error) {
+	rsp, err := c.RemoveJellyfinItem(ctx, animeId, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRemoveJellyfinItemResponse(rsp)
+}
+
+// GetJellyfinItemWithResponse request returning *GetJellyfinItemResponse
+func (c *ClientWithResponses) GetJellyfinItemWithResponse(ctx context.Context, animeId AnimeId, params *GetJellyfinItemParams, reqEditors ...RequestEditorFn) (*GetJellyfinItemResponse, error) {
+	rsp, err := c.GetJellyfinItem(ctx, animeId, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetJellyfinItemResponse(rsp)
+}
+
+// UpdateJellyfinItemWithBodyWithResponse request with arbitrary body returning *UpdateJellyfinItemResponse
+func (c *ClientWithResponses) UpdateJellyfinItemWithBodyWithResponse(ctx context.Context, animeId AnimeId, params *UpdateJellyfinItemParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateJellyfinItemResponse, error) {
+	rsp, err := c.UpdateJellyfinItemWithBody(ctx, animeId, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdateJellyfinItemResponse(rsp)
+}
+
+func (c *ClientWithResponses) UpdateJellyfinItemWithResponse(ctx context.Context, animeId AnimeId, params *UpdateJellyfinItemParams, body UpdateJellyfinItemJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateJellyfinItemResponse, error) {
+	rsp, err := c.UpdateJellyfinItem(ctx, animeId, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdateJellyfinItemResponse(rsp)
+}
+
+// RefreshJellyfinWithResponse request returning *RefreshJellyfinResponse
+func (c *ClientWithResponses) RefreshJellyfinWithResponse(ctx context.Context, params *RefreshJellyfinParams, reqEditors ...RequestEditorFn) (*RefreshJellyfinResponse, error) {
+	rsp, err := c.RefreshJellyfin(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRefreshJellyfinResponse(rsp)
+}
+
+// GetJellyfinStatusWithResponse request returning *GetJellyfinStatusResponse
+func (c *ClientWithResponses) GetJellyfinStatusWithResponse(ctx context.Context, params *GetJellyfinStatusParams, reqEditors ...RequestEditorFn) (*GetJellyfinStatusResponse, error) {
+	rsp, err := c.GetJellyfinStatus(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetJellyfinStatusResponse(rsp)
+}
+
+// ListProfilesWithResponse request returning *ListProfilesResponse
+func (c *ClientWithResponses) ListProfilesWithResponse(ctx context.Context, params *ListProfilesParams, reqEditors ...RequestEditorFn) (*ListProfilesResponse, error) {
+	rsp, err := c.ListProfiles(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListProfilesResponse(rsp)
+}
+
+// CreateProfileWithBodyWithResponse request with arbitrary body returning *CreateProfileResponse
+func (c *ClientWithResponses) CreateProfileWithBodyWithResponse(ctx context.Context, params *CreateProfileParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateProfileResponse, error) {
+	rsp, err := c.CreateProfileWithBody(ctx, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCreateProfileResponse(rsp)
+}
+
+func (c *ClientWithResponses) CreateProfileWithResponse(ctx context.Context, params *CreateProfileParams, body CreateProfileJSONRequestBody, reqEditors ...RequestEditorFn) (*CreateProfileResponse, error) {
+	rsp, err := c.CreateProfile(ctx, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCreateProfileResponse(rsp)
+}
+
+// GetSettingsWithResponse request returning *GetSettingsResponse
+func (c *ClientWithResponses) GetSettingsWithResponse(ctx context.Context, params *GetSettingsParams, reqEditors ...RequestEditorFn) (*GetSettingsResponse, error) {
+	rsp, err := c.GetSettings(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetSettingsResponse(rsp)
+}
+
+// UpdateSettingsWithBodyWithResponse request with arbitrary body returning *UpdateSettingsResponse
+func (c *ClientWithResponses) UpdateSettingsWithBodyWithResponse(ctx context.Context, params *UpdateSettingsParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateSettingsResponse, error) {
+	rsp, err := c.UpdateSettingsWithBody(ctx, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdateSettingsResponse(rsp)
+}
+
+func (c *ClientWithResponses) UpdateSettingsWithResponse(ctx context.Context, params *UpdateSettingsParams, body UpdateSettingsJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateSettingsResponse, error) {
+	rsp, err := c.UpdateSettings(ctx, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdateSettingsResponse(rsp)
+}
+
+// StreamEpisodeWithResponse request returning *StreamEpisodeResponse
+func (c *ClientWithResponses) StreamEpisodeWithResponse(ctx context.Context, animeId AnimeId, episodeNum EpisodeNum, params *StreamEpisodeParams, reqEditors ...RequestEditorFn) (*StreamEpisodeResponse, error) {
+	rsp, err := c.StreamEpisode(ctx, animeId, episodeNum, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseStreamEpisodeResponse(rsp)
+}
+
+// ListWatchlistWithResponse request returning *ListWatchlistResponse
+func (c *ClientWithResponses) ListWatchlistWithResponse(ctx context.Context, params *ListWatchlistParams, reqEditors ...RequestEditorFn) (*ListWatchlistResponse, error) {
+	rsp, err := c.ListWatchlist(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListWatchlistResponse(rsp)
+}
+
+// AddToWatchlistWithBodyWithResponse request with arbitrary body returning *AddToWatchlistResponse
+func (c *ClientWithResponses) AddToWatchlistWithBodyWithResponse(ctx context.Context, params *AddToWatchlistParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*AddToWatchlistResponse, error) {
+	rsp, err := c.AddToWatchlistWithBody(ctx, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAddToWatchlistResponse(rsp)
+}
+
+func (c *ClientWithResponses) AddToWatchlistWithResponse(ctx context.Context, params *AddToWatchlistParams, body AddToWatchlistJSONRequestBody, reqEditors ...RequestEditorFn) (*AddToWatchlistResponse, error) {
+	rsp, err := c.AddToWatchlist(ctx, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAddToWatchlistResponse(rsp)
+}
+
+// RemoveFromWatchlistWithResponse request returning *RemoveFromWatchlistResponse
+func (c *ClientWithResponses) RemoveFromWatchlistWithResponse(ctx context.Context, source Source, animeId AnimeId, params *RemoveFromWatchlistParams, reqEditors ...RequestEditorFn) (*RemoveFromWatchlistResponse, error) {
+	rsp, err := c.RemoveFromWatchlist(ctx, source, animeId, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRemoveFromWatchlistResponse(rsp)
+}
+
+// GetWatchlistEntryWithResponse request returning *GetWatchlistEntryResponse
+func (c *ClientWithResponses) GetWatchlistEntryWithResponse(ctx context.Context, source Source, animeId AnimeId, params *GetWatchlistEntryParams, reqEditors ...RequestEditorFn) (*GetWatchlistEntryResponse, error) {
+	rsp, err := c.GetWatchlistEntry(ctx, source, animeId, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetWatchlistEntryResponse(rsp)
+}
+
+// UpdateWatchlistEntryWithBodyWithResponse request with arbitrary body returning *UpdateWatchlistEntryResponse
+func (c *ClientWithResponses) UpdateWatchlistEntryWithBodyWithResponse(ctx context.Context, source Source, animeId AnimeId, params *UpdateWatchlistEntryParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateWatchlistEntryResponse, error) {
+	rsp, err := c.UpdateWatchlistEntryWithBody(ctx, source, animeId, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdateWatchlistEntryResponse(rsp)
+}
+
+func (c *ClientWithResponses) UpdateWatchlistEntryWithResponse(ctx context.Context, source Source, animeId AnimeId, params *UpdateWatchlistEntryParams, body UpdateWatchlistEntryJSONRequestBody, reqEditors ...RequestEditorFn) (*UpdateWatchlistEntryResponse, error) {
+	rsp, err := c.UpdateWatchlistEntry(ctx, source, animeId, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUpdateWatchlistEntryResponse(rsp)
+}
+
+// ParseSearchAnimeResponse parses an HTTP response from a SearchAnimeWithResponse call
+func ParseSearchAnimeResponse(rsp *http.Response) (*SearchAnimeResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SearchAnimeResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Data []Anime `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetAnimeResponse parses an HTTP response from a GetAnimeWithResponse call
+func ParseGetAnimeResponse(rsp *http.Response) (*GetAnimeResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetAnimeResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Data Anime `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListEpisodesResponse parses an HTTP response from a ListEpisodesWithResponse call
+func ParseListEpisodesResponse(rsp *http.Response) (*ListEpisodesResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListEpisodesResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Data []Episode `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetEpisodeResponse parses an HTTP response from a GetEpisodeWithResponse call
+func ParseGetEpisodeResponse(rsp *http.Response) (*GetEpisodeResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetEpisodeResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Data EpisodeDetails `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListDownloadsResponse parses an HTTP response from a ListDownloadsWithResponse call
+func ParseListDownloadsResponse(rsp *http.Response) (*ListDownloadsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListDownloadsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Data []DownloadJob `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseAddDownloadsResponse parses an HTTP response from a AddDownloadsWithResponse call
+func ParseAddDownloadsResponse(rsp *http.Response) (*AddDownloadsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &AddDownloadsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 201:
+		var dest struct {
+			Data []DownloadJob `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON201 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseCancelDownloadResponse parses an HTTP response from a CancelDownloadWithResponse call
+func ParseCancelDownloadResponse(rsp *http.Response) (*CancelDownloadResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CancelDownloadResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetDownloadResponse parses an HTTP response from a GetDownloadWithResponse call
+func ParseGetDownloadResponse(rsp *http.Response) (*GetDownloadResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetDownloadResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Data DownloadJob `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePauseDownloadResponse parses an HTTP response from a PauseDownloadWithResponse call
+func ParsePauseDownloadResponse(rsp *http.Response) (*PauseDownloadResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PauseDownloadResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Data DownloadJob `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest Conflict
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseResumeDownloadResponse parses an HTTP response from a ResumeDownloadWithResponse call
+func ParseResumeDownloadResponse(rsp *http.Response) (*ResumeDownloadResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ResumeDownloadResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Data DownloadJob `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest Conflict
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseStreamEventsResponse parses an HTTP response from a StreamEventsWithResponse call
+func ParseStreamEventsResponse(rsp *http.Response) (*StreamEventsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &StreamEventsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest AuthError
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest AuthError
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListJellyfinItemsResponse parses an HTTP response from a ListJellyfinItemsWithResponse call
+func ParseListJellyfinItemsResponse(rsp *http.Response) (*ListJellyfinItemsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListJellyfinItemsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Data []JellyfinItem `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseAddJellyfinItemResponse parses an HTTP response from a AddJellyfinItemWithResponse call
+func ParseAddJellyfinItemResponse(rsp *http.Response) (*AddJellyfinItemResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &AddJellyfinItemResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 201:
+		var dest struct {
+			Data JellyfinItem `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON201 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest Conflict
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseRemoveJellyfinItemResponse parses an HTTP response from a RemoveJellyfinItemWithResponse call
+func ParseRemoveJellyfinItemResponse(rsp *http.Response) (*RemoveJellyfinItemResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &RemoveJellyfinItemResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetJellyfinItemResponse parses an HTTP response from a GetJellyfinItemWithResponse call
+func ParseGetJellyfinItemResponse(rsp *http.Response) (*GetJellyfinItemResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetJellyfinItemResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Data JellyfinItem `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseUpdateJellyfinItemResponse parses an HTTP response from a UpdateJellyfinItemWithResponse call
+func ParseUpdateJellyfinItemResponse(rsp *http.Response) (*UpdateJellyfinItemResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &UpdateJellyfinItemResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Data JellyfinItem `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseRefreshJellyfinResponse parses an HTTP response from a RefreshJellyfinWithResponse call
+func ParseRefreshJellyfinResponse(rsp *http.Response) (*RefreshJellyfinResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &RefreshJellyfinResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 202:
+		var dest struct {
+			Data JellyfinStatus `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON202 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest Conflict
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetJellyfinStatusResponse parses an HTTP response from a GetJellyfinStatusWithResponse call
+func ParseGetJellyfinStatusResponse(rsp *http.Response) (*GetJellyfinStatusResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetJellyfinStatusResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Data JellyfinStatus `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListProfilesResponse parses an HTTP response from a ListProfilesWithResponse call
+func ParseListProfilesResponse(rsp *http.Response) (*ListProfilesResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListProfilesResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Data []Profile `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseCreateProfileResponse parses an HTTP response from a CreateProfileWithResponse call
+func ParseCreateProfileResponse(rsp *http.Response) (*CreateProfileResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CreateProfileResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 201:
+		var dest struct {
+			Data ProfileName `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON201 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest Conflict
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetSettingsResponse parses an HTTP response from a GetSettingsWithResponse call
+func ParseGetSettingsResponse(rsp *http.Response) (*GetSettingsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetSettingsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Data Settings `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseUpdateSettingsResponse parses an HTTP response from a UpdateSettingsWithResponse call
+func ParseUpdateSettingsResponse(rsp *http.Response) (*UpdateSettingsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &UpdateSettingsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Data Settings `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseStreamEpisodeResponse parses an HTTP response from a StreamEpisodeWithResponse call
+func ParseStreamEpisodeResponse(rsp *http.Response) (*StreamEpisodeResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &StreamEpisodeResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest AuthError
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest AuthError
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListWatchlistResponse parses an HTTP response from a ListWatchlistWithResponse call
+func ParseListWatchlistResponse(rsp *http.Response) (*ListWatchlistResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListWatchlistResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Data []WatchlistEntry `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseAddToWatchlistResponse parses an HTTP response from a AddToWatchlistWithResponse call
+func ParseAddToWatchlistResponse(rsp *http.Response) (*AddToWatchlistResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &AddToWatchlistResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 201:
+		var dest struct {
+			Data WatchlistEntry `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON201 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseRemoveFromWatchlistResponse parses an HTTP response from a RemoveFromWatchlistWithResponse call
+func ParseRemoveFromWatchlistResponse(rsp *http.Response) (*RemoveFromWatchlistResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &RemoveFromWatchlistResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetWatchlistEntryResponse parses an HTTP response from a GetWatchlistEntryWithResponse call
+func ParseGetWatchlistEntryResponse(rsp *http.Response) (*GetWatchlistEntryResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetWatchlistEntryResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Data WatchlistEntry `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseUpdateWatchlistEntryResponse parses an HTTP response from a UpdateWatchlistEntryWithResponse call
+func ParseUpdateWatchlistEntryResponse(rsp *http.Response) (*UpdateWatchlistEntryResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &UpdateWatchlistEntryResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Data WatchlistEntry `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest TooManyRequests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient(t *testing.T) {
+	var got *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"code":"not_found","message":"anime not found"}}`))
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL+"/ani/", WithApiKey("key"), WithProfile("sara"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := c.GetEpisodeWithResponse(context.Background(), "hunter x hunter", 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.URL.EscapedPath() != "/ani/api/v1/anime/hunter%20x%20hunter/episodes/1" {
+		t.Fatalf("unexpected path %s", got.URL.EscapedPath())
+	}
+	if got.Header.Get("Authorization") != "Bearer key" || got.Header.Get("X-Ani-Profile") != "sara" {
+		t.Fatalf("unexpected headers %v", got.Header)
+	}
+	if res.JSON404 == nil || res.JSON404.Error.Code != ErrorCodeNotFound {
+		t.Fatalf("the error should be decoded, got %d %s", res.StatusCode(), res.Body)
+	}
+}
+
+func TestStreamUrl(t *testing.T) {
+	u, err := StreamUrl("https://example.com/ani", "key", "sara", "one-piece", 1100, "720")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u != "https://example.com/ani/stream/one-piece/1100?api_key=key&profile=sara&res=720" {
+		t.Fatalf("unexpected url %s", u)
+	}
+}
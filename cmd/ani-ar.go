@@ -4,30 +4,70 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net"
 	"os"
+	"os/signal"
 	"strconv"
-	"time"
+	"strings"
+	"syscall"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/urfave/cli/v2"
 
 	"github.com/ani/ani-ar/api"
+	"github.com/ani/ani-ar/cast"
 	"github.com/ani/ani-ar/download"
 	"github.com/ani/ani-ar/fetcher"
 	"github.com/ani/ani-ar/gui"
+	"github.com/ani/ani-ar/history"
 	"github.com/ani/ani-ar/jellyfin"
+	"github.com/ani/ani-ar/party"
 	"github.com/ani/ani-ar/player"
+	"github.com/ani/ani-ar/profile"
+	"github.com/ani/ani-ar/skip"
+	"github.com/ani/ani-ar/types"
 )
 
 func main() {
 	app := &cli.App{
 		Name:  "ani-ar",
 		Usage: "watch anime from terminal with arabic sub",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "ui-lang",
+				Value: gui.LangEnglish,
+				Usage: "the language of the tui, ar lays it out right to left",
+			},
+			&cli.StringFlag{
+				Name:  "profile",
+				Value: profile.Default,
+				Usage: "the profile keeping the history, the watchlist and the settings",
+			},
+		},
+		Before: func(ctx *cli.Context) error {
+			return profile.SetActive(ctx.String("profile"))
+		},
 		Commands: []*cli.Command{
 			{
-				Name:  "jelly",
-				Flags: []cli.Flag{},
+				Name: "jelly",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "addr",
+						Value: "",
+						Usage: "also serve the api with the library routes on the address, eg. 127.0.0.1:8000",
+					},
+				},
 				Action: func(ctx *cli.Context) error {
+					if addr := ctx.String("addr"); addr != "" {
+						go func() {
+							_, err := api.Serve(&api.ServerConfig{
+								HttpAddr:        addr,
+								ShowStartBanner: true,
+								Routes:          []api.RouteGroup{jellyfin.InitiateRoutes},
+							})
+							log.Fatal(err)
+						}()
+					}
 					return jellyfin.InfiniteLoop()
 				},
 				Subcommands: []*cli.Command{
@@ -39,21 +79,7 @@ func main() {
 					},
 				},
 			},
-			{
-				Name:  "serve",
-				Flags: []cli.Flag{},
-				Action: func(ctx *cli.Context) error {
-					_, err := api.Serve(&api.ServerConfig{
-						HttpAddr:                         "127.0.0.1:8000",
-						ShowStartBanner:                  true,
-						TimeToWaitBeforeGracefulShutdown: time.Second,
-					})
-					if err != nil {
-						return err
-					}
-					return nil
-				},
-			},
+			serveCommand(),
 			{
 				Name: "search",
 				Flags: []cli.Flag{
@@ -80,24 +106,217 @@ func main() {
 			{
 				Name: "watch",
 				Args: true,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "cast",
+						Value: "",
+						Usage: "cast the episode to a Chromecast or a DLNA/UPnP renderer by its name or host",
+					},
+					&cli.BoolFlag{
+						Name:  "proxy",
+						Usage: "play through a local stream proxy instead of the raw video url",
+					},
+					&cli.BoolFlag{
+						Name:  "skip",
+						Usage: "skip the opening and ending (requires mpv)",
+					},
+					&cli.BoolFlag{
+						Name:  "print-url",
+						Usage: "only print the video url, eg. ani-ar watch x 5 --print-url | xargs mpv, the headers the video needs are printed to stderr",
+					},
+					&cli.StringFlag{
+						Name:  "fallback",
+						Value: player.FallbackPrint,
+						Usage: "what to do when no player is installed: " + strings.Join(player.Fallbacks, ", "),
+					},
+					&cli.StringFlag{
+						Name:  "serve-addr",
+						Value: "127.0.0.1:0",
+						Usage: "address the stream proxy of the serve fallback listens on, eg. 0.0.0.0:8000 to play it from another device",
+					},
+				},
 				Action: func(ctx *cli.Context) error {
 					title := ctx.Args().First()
 					episode := ctx.Args().Get(1)
 					animeEpisode, _ := strconv.Atoi(episode)
-					result := fetcher.GetDefaultFetcher().GetAnimeResult(title)
-					if result == nil {
-						return errors.New("can't find anime")
+					videoTitle := fmt.Sprintf("%s-episode-%v", title, animeEpisode)
+
+					var device *cast.Device
+					if name := ctx.String("cast"); name != "" {
+						d, err := player.FindCastDevice(name)
+						if err != nil {
+							return err
+						}
+						device = d
+					}
+
+					if ctx.Bool("print-url") && ctx.Bool("proxy") {
+						return errors.New("--print-url can't be used with --proxy, the proxy stops with the command")
+					}
+
+					var video *types.AniVideo
+					proxied := ctx.Bool("proxy")
+					if proxied {
+						u, err := startEpisodeProxy(title, animeEpisode, device)
+						if err != nil {
+							return err
+						}
+						video = &types.AniVideo{Src: u}
+					} else {
+						result := fetcher.GetDefaultFetcher().GetAnimeResult(title)
+						if result == nil {
+							return errors.New("can't find anime")
+						}
+						episodes := fetcher.GetDefaultFetcher().GetEpisodes(*result)
+						if animeEpisode < 1 || animeEpisode > len(episodes) {
+							return errors.New("episode out of range")
+						}
+						ep := episodes[animeEpisode-1]
+						log.Println("getting the episode video...")
+						video = types.SelectVideo(ep.GetPlayersWithQuality(), profile.GetSettings(profile.Active()).Quality)
+						if video == nil {
+							return errors.New("no video sources found for the episode")
+						}
+						log.Println("found it")
+						// the devices can't send the headers the video host expects, the proxy adds them
+						if device != nil && len(video.Headers) > 0 && !ctx.Bool("print-url") {
+							u, err := startEpisodeProxy(title, animeEpisode, device)
+							if err != nil {
+								return err
+							}
+							video, proxied = &types.AniVideo{Src: u}, true
+						}
+					}
+
+					if ctx.Bool("print-url") {
+						fmt.Println(video.Src)
+						// the url alone is refused by the hosts needing headers, they
+						// go to stderr with the command so the url can still be piped
+						if len(video.Headers) > 0 {
+							log.Println("the video host requires these headers:")
+							player.PrintCommand(os.Stderr, *video, videoTitle)
+						}
+						return nil
+					}
+
+					if device != nil {
+						err := device.Cast(video.Src, videoTitle)
+						if err != nil {
+							return err
+						}
+						log.Printf("casting to %s\n", device.Name)
+						history.GetStore().MarkWatched(title, animeEpisode)
+						if proxied {
+							// the device streams from us, keep the proxy alive
+							log.Println("press ctrl+c to stop the stream proxy")
+							waitForInterrupt()
+						}
+						return nil
+					}
+					if ctx.Bool("skip") {
+						session, err := player.RunVideoWithSkipTimes(
+							*video,
+							videoTitle,
+							getSkipTimes(title, animeEpisode),
+						)
+						if err != nil {
+							return err
+						}
+						history.GetStore().MarkWatched(title, animeEpisode)
+						return session.Wait()
+					}
+					cmd, err := player.RunVideo(*video, videoTitle)
+					if errors.Is(err, player.ErrNoPlayers) {
+						return runPlayerFallback(ctx.String("fallback"), ctx.String("serve-addr"), video, title, animeEpisode, videoTitle)
+					}
+					if err != nil {
+						return err
 					}
-					episodes := fetcher.GetDefaultFetcher().GetEpisodes(*result)
-					ep := episodes[animeEpisode-1]
-					log.Println("getting the episode video...")
-					videoUrl := ep.GetPlayerUrl()
-					log.Println("found it")
-					_, err := player.RunVideo(
-						videoUrl,
-						fmt.Sprintf("%s-episode-%v", title, animeEpisode),
-					)
-					return err
+					history.GetStore().MarkWatched(title, animeEpisode)
+					if ctx.Bool("proxy") {
+						return cmd.Wait()
+					}
+					return nil
+				},
+			},
+			listCommand(),
+			profileCommand(),
+			notifyCommand(),
+			apikeyCommand(),
+			devicesCommand(),
+			{
+				Name:  "party",
+				Usage: "watch an episode in sync with friends",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "host",
+						Args:  true,
+						Usage: "host a watch party for an anime episode",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "addr",
+								Value: "0.0.0.0:8000",
+								Usage: "address the party server listens on",
+							},
+						},
+						Action: func(ctx *cli.Context) error {
+							episode, _ := strconv.Atoi(ctx.Args().Get(1))
+							source := party.Source{AnimeId: ctx.Args().First(), Episode: episode}
+							session, err := startPartySession(source)
+							if err != nil {
+								return err
+							}
+							hub := party.NewHub(source, session)
+
+							serveErr := make(chan error, 1)
+							go func() {
+								// the friends only need the party, the api stays private
+								_, err := api.Serve(&api.ServerConfig{
+									HttpAddr:        ctx.String("addr"),
+									ShowStartBanner: true,
+									Party:           hub,
+									PartyOnly:       true,
+								})
+								serveErr <- err
+							}()
+							runErr := make(chan error, 1)
+							go func() {
+								runErr <- hub.Run()
+							}()
+
+							select {
+							case err := <-serveErr:
+								return err
+							case err := <-runErr:
+								// the party ends when the host closes the player
+								if errors.Is(err, player.ErrMpvClosed) {
+									return nil
+								}
+								return err
+							}
+						},
+					},
+					{
+						Name:  "join",
+						Args:  true,
+						Usage: "join a watch party by the host address (eg. 192.168.1.10:8000)",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:    "api-key",
+								Usage:   "the api key when the host requires keys, it needs the stream scope",
+								EnvVars: []string{"ANI_AR_API_KEY"},
+							},
+						},
+						Action: func(ctx *cli.Context) error {
+							err := party.Join(ctx.Args().First(), ctx.String("api-key"), func(source party.Source) (party.Session, error) {
+								return startPartySession(source)
+							})
+							if errors.Is(err, player.ErrMpvClosed) {
+								return nil
+							}
+							return err
+						},
+					},
 				},
 			},
 			{
@@ -121,7 +340,10 @@ func main() {
 			},
 		},
 		Action: func(ctx *cli.Context) error {
-			p := tea.NewProgram(gui.InitialModel())
+			if err := gui.SetLanguage(ctx.String("ui-lang")); err != nil {
+				return err
+			}
+			p := tea.NewProgram(gui.InitialModel(), tea.WithAltScreen(), tea.WithMouseCellMotion())
 			if _, err := p.Run(); err != nil {
 				return err
 			}
@@ -129,7 +351,167 @@ func main() {
 		},
 	}
 
-	if err := app.Run(os.Args); err != nil {
+	if err := app.Run(withFlagsFirst(app, os.Args)); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// urfave/cli stops parsing the flags at the first argument, this moves the flags
+// written after the arguments (eg. `ani-ar watch x 5 --print-url`) before them
+func withFlagsFirst(app *cli.App, args []string) []string {
+	var cmd *cli.Command
+	commands := app.Commands
+	i := 1
+	for ; i < len(args); i++ {
+		var next *cli.Command
+		for _, c := range commands {
+			if c.HasName(args[i]) {
+				next = c
+			}
+		}
+		if next == nil {
+			break
+		}
+		cmd = next
+		commands = next.Subcommands
+	}
+	if cmd == nil {
+		return args
+	}
+
+	var flags, positional []string
+	for j := i; j < len(args); j++ {
+		arg := args[j]
+		if arg == "--" {
+			positional = append(positional, args[j:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			positional = append(positional, arg)
+			continue
+		}
+		flags = append(flags, arg)
+		name := strings.TrimLeft(arg, "-")
+		if !strings.Contains(name, "=") && flagTakesValue(cmd, name) && j+1 < len(args) {
+			j++
+			flags = append(flags, args[j])
+		}
+	}
+
+	reordered := append([]string{}, args[:i]...)
+	reordered = append(reordered, flags...)
+	return append(reordered, positional...)
+}
+
+func flagTakesValue(cmd *cli.Command, name string) bool {
+	for _, f := range cmd.Flags {
+		for _, n := range f.Names() {
+			if n == name {
+				_, isBool := f.(*cli.BoolFlag)
+				return !isBool
+			}
+		}
+	}
+	return false
+}
+
+// used when no player is installed, eg. on a server or over ssh
+func runPlayerFallback(fallback, serveAddr string, video *types.AniVideo, title string, episode int, videoTitle string) error {
+	switch fallback {
+	case player.FallbackPrint:
+		log.Println(player.ErrNoPlayers)
+		player.PrintCommand(os.Stdout, *video, videoTitle)
+		return nil
+	case player.FallbackBrowser:
+		return player.OpenInBrowser(video.Src)
+	case player.FallbackServe:
+		// the proxy isn't authenticated, it's only reachable from this machine unless asked otherwise
+		l, err := api.StartStreamProxy(serveAddr, fetcher.GetDefaultFetcher())
+		if err != nil {
+			return err
+		}
+		addr := l.Addr().(*net.TCPAddr)
+		port := strconv.Itoa(addr.Port)
+		if !addr.IP.IsUnspecified() {
+			fmt.Println(api.StreamUrl("http://"+net.JoinHostPort(addr.IP.String(), port), title, episode))
+		} else {
+			fmt.Println(api.StreamUrl("http://"+net.JoinHostPort("127.0.0.1", port), title, episode))
+			if ip := localNetworkIP(); ip != "" {
+				fmt.Println(api.StreamUrl("http://"+net.JoinHostPort(ip, port), title, episode))
+			}
+		}
+		log.Println("press ctrl+c to stop the stream proxy")
+		waitForInterrupt()
+		return nil
+	}
+	return fmt.Errorf("unknown fallback %q, use one of: %s", fallback, strings.Join(player.Fallbacks, ", "))
+}
+
+// returns the ip this machine uses on the local network, nothing is sent by the udp dial
+func localNetworkIP() string {
+	conn, err := net.Dial("udp", "8.8.8.8:80")
+	if err != nil {
+		return ""
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP.String()
+}
+
+func waitForInterrupt() {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	<-c
+}
+
+// looks up the opening and ending of the episode, failures only disable the skipping
+func getSkipTimes(title string, episode int) []skip.Interval {
+	malId := api.GetJikanApi().GetMalId(title)
+	if malId == 0 {
+		log.Println("couldn't find the anime on MyAnimeList, skipping is disabled")
+		return nil
+	}
+	intervals, err := skip.GetDefaultProvider().GetSkipTimes(malId, episode)
+	if err != nil {
+		log.Printf("couldn't get the skip times, reason: %v\n", err)
+		return nil
+	}
+	log.Printf("found %d interval(s) to skip\n", len(intervals))
+	return intervals
+}
+
+// resolves the party episode with the local fetcher and plays it in an ipc controlled mpv
+func startPartySession(source party.Source) (*player.MpvSession, error) {
+	result := fetcher.GetDefaultFetcher().GetAnimeResult(source.AnimeId)
+	if result == nil {
+		return nil, errors.New("can't find anime")
+	}
+	episodes := fetcher.GetDefaultFetcher().GetEpisodes(*result)
+	if source.Episode < 1 || source.Episode > len(episodes) {
+		return nil, errors.New("episode out of range")
+	}
+	log.Println("getting the episode video...")
+	video := types.SelectVideo(episodes[source.Episode-1].GetPlayersWithQuality(), profile.GetSettings(profile.Active()).Quality)
+	if video == nil {
+		return nil, errors.New("no video sources found for the episode")
+	}
+	return player.StartMpvSession(*video, fmt.Sprintf("%s-episode-%v", source.AnimeId, source.Episode))
+}
+
+// starts a local stream proxy for the episode and returns its url, when casting
+// the proxy listens on the interface the device can reach us from
+func startEpisodeProxy(title string, episode int, device *cast.Device) (string, error) {
+	listenHost, urlHost := "127.0.0.1", "127.0.0.1"
+	if device != nil {
+		ip, err := device.LocalIP()
+		if err != nil {
+			return "", err
+		}
+		listenHost, urlHost = ip, ip
+	}
+	l, err := api.StartStreamProxy(net.JoinHostPort(listenHost, "0"), fetcher.GetDefaultFetcher())
+	if err != nil {
+		return "", err
+	}
+	port := strconv.Itoa(l.Addr().(*net.TCPAddr).Port)
+	return api.StreamUrl("http://"+net.JoinHostPort(urlHost, port), title, episode), nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ani/ani-ar/apikey"
+)
+
+func apikeyCommand() *cli.Command {
+	listKeys := func(ctx *cli.Context) error {
+		keys := apikey.GetStore().List()
+		if len(keys) == 0 {
+			fmt.Println("no api keys, the api is open to anyone who can reach it")
+			return nil
+		}
+		for _, k := range keys {
+			fmt.Printf("%s  %s  [%s]  created %s\n", k.Id, k.Name, strings.Join(k.Scopes, ", "), k.CreatedAt.Format("2006-01-02 15:04"))
+		}
+		return nil
+	}
+
+	return &cli.Command{
+		Name:   "apikey",
+		Usage:  "manage the api keys, once a key is created the api of `ani-ar serve` requires one",
+		Action: listKeys,
+		Subcommands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  "list the api keys with their scopes",
+				Action: listKeys,
+			},
+			{
+				Name:  "create",
+				Args:  true,
+				Usage: "create an api key, eg. ani-ar apikey create tv --scope read --scope stream",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:  "scope",
+						Usage: "the scopes of the key: read, stream, library:write or admin",
+						Value: cli.NewStringSlice(apikey.ScopeRead, apikey.ScopeStream),
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					token, key, err := apikey.GetStore().Create(ctx.Args().First(), ctx.StringSlice("scope"))
+					if err != nil {
+						return err
+					}
+					fmt.Printf("created the %s api key (%s) with the scopes: %s\n", key.Name, key.Id, strings.Join(key.Scopes, ", "))
+					fmt.Println("save it now, it can't be shown again:")
+					fmt.Println(token)
+					return nil
+				},
+			},
+			{
+				Name:  "revoke",
+				Args:  true,
+				Usage: "revoke an api key by its id or name",
+				Action: func(ctx *cli.Context) error {
+					return apikey.GetStore().Revoke(ctx.Args().First())
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ani/ani-ar/cast"
+	"github.com/ani/ani-ar/player"
+)
+
+// parsePosition reads the positions of `devices seek`: seconds, [hh:]mm:ss or
+// a go duration like 1m30s
+func parsePosition(s string) (time.Duration, error) {
+	invalid := fmt.Errorf("invalid position %q, eg. 90, 1:30, 1:02:03 or 1m30s", s)
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return d, nil
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, invalid
+	}
+	var seconds int
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, invalid
+		}
+		seconds = seconds*60 + n
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+func devicesCommand() *cli.Command {
+	listDevices := func(ctx *cli.Context) error {
+		devices, err := player.DiscoverCastDevices()
+		if err != nil {
+			return err
+		}
+		if len(devices) == 0 {
+			return errors.New("no cast devices found")
+		}
+		for _, d := range devices {
+			fmt.Println(d.String())
+		}
+		return nil
+	}
+
+	// control runs the action on the device named by the first argument
+	control := func(action func(d *cast.Device, ctx *cli.Context) error) cli.ActionFunc {
+		return func(ctx *cli.Context) error {
+			name := ctx.Args().First()
+			if name == "" {
+				return errors.New("the device name or host is required")
+			}
+			device, err := player.FindCastDevice(name)
+			if err != nil {
+				return err
+			}
+			return action(device, ctx)
+		}
+	}
+
+	return &cli.Command{
+		Name:   "devices",
+		Usage:  "list the Chromecasts and DLNA/UPnP renderers on the local network and control their playback",
+		Action: listDevices,
+		Subcommands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  "list the devices",
+				Action: listDevices,
+			},
+			{
+				Name:  "play",
+				Args:  true,
+				Usage: "resume the playback of a device, eg. ani-ar devices play \"living room\"",
+				Action: control(func(d *cast.Device, ctx *cli.Context) error {
+					return d.Play()
+				}),
+			},
+			{
+				Name:  "pause",
+				Args:  true,
+				Usage: "pause the playback of a device",
+				Action: control(func(d *cast.Device, ctx *cli.Context) error {
+					return d.Pause()
+				}),
+			},
+			{
+				Name:  "stop",
+				Args:  true,
+				Usage: "stop the playback of a device",
+				Action: control(func(d *cast.Device, ctx *cli.Context) error {
+					return d.Stop()
+				}),
+			},
+			{
+				Name:  "seek",
+				Args:  true,
+				Usage: "jump to a position of the episode playing on a device, eg. ani-ar devices seek \"living room\" 12:30",
+				Action: control(func(d *cast.Device, ctx *cli.Context) error {
+					position, err := parsePosition(ctx.Args().Get(1))
+					if err != nil {
+						return err
+					}
+					return d.Seek(position)
+				}),
+			},
+		},
+	}
+}
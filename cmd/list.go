@@ -0,0 +1,171 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ani/ani-ar/api"
+	"github.com/ani/ani-ar/fetcher"
+	"github.com/ani/ani-ar/watchlist"
+)
+
+var watchlistSourceFlag = &cli.StringFlag{
+	Name:  "source",
+	Value: "",
+	Usage: "the fetcher the anime comes from, the default fetcher when empty",
+}
+
+func watchlistSource(ctx *cli.Context) string {
+	if source := ctx.String("source"); source != "" {
+		return source
+	}
+	return fetcher.GetFetcherName(fetcher.GetDefaultFetcher())
+}
+
+func printWatchlistEntry(e watchlist.Entry) {
+	favorite := ""
+	if e.Favorite {
+		favorite = " ★"
+	}
+	fmt.Printf("[%s] %s - {id: %s, source: %s}%s\n", e.Status, e.Title, e.Id, e.Source, favorite)
+}
+
+func listCommand() *cli.Command {
+	showList := func(ctx *cli.Context) error {
+		status := ctx.String("status")
+		if status != "" && !watchlist.IsValidStatus(status) {
+			return watchlist.ErrInvalidStatus
+		}
+		store, err := watchlist.GetStore()
+		if err != nil {
+			return err
+		}
+		entries := store.List(status, ctx.Bool("favorites"))
+		if len(entries) == 0 {
+			fmt.Println("the watchlist is empty")
+			return nil
+		}
+		for _, e := range entries {
+			printWatchlistEntry(e)
+		}
+		return nil
+	}
+	listFlags := []cli.Flag{
+		&cli.StringFlag{
+			Name:  "status",
+			Value: "",
+			Usage: "only list the anime with the status: " + strings.Join(watchlist.Statuses, ", "),
+		},
+		&cli.BoolFlag{
+			Name:  "favorites",
+			Usage: "only list the favorite anime",
+		},
+	}
+
+	return &cli.Command{
+		Name:   "list",
+		Usage:  "manage the watchlist",
+		Flags:  listFlags,
+		Action: showList,
+		Subcommands: []*cli.Command{
+			{
+				Name:   "show",
+				Usage:  "list the anime in the watchlist",
+				Flags:  listFlags,
+				Action: showList,
+			},
+			{
+				Name:  "add",
+				Args:  true,
+				Usage: "add an anime to the watchlist by its title or id",
+				Flags: []cli.Flag{
+					watchlistSourceFlag,
+					&cli.StringFlag{
+						Name:  "status",
+						Value: watchlist.StatusPlanToWatch,
+						Usage: "one of " + strings.Join(watchlist.Statuses, ", "),
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					store, err := watchlist.GetStore()
+					if err != nil {
+						return err
+					}
+					f, err := fetcher.GetFetcherByName(watchlistSource(ctx))
+					if err != nil {
+						return err
+					}
+					anime := f.GetAnimeResult(ctx.Args().First())
+					if anime == nil {
+						return errors.New("anime not found")
+					}
+					entry := api.NewWatchlistEntry(f, *anime)
+					entry.Status = ctx.String("status")
+					added, err := store.Add(entry)
+					if err != nil {
+						return err
+					}
+					printWatchlistEntry(added)
+					return nil
+				},
+			},
+			{
+				Name:  "remove",
+				Args:  true,
+				Usage: "remove an anime from the watchlist by its id",
+				Flags: []cli.Flag{watchlistSourceFlag},
+				Action: func(ctx *cli.Context) error {
+					store, err := watchlist.GetStore()
+					if err != nil {
+						return err
+					}
+					return store.Remove(watchlistSource(ctx), ctx.Args().First())
+				},
+			},
+			{
+				Name:  "status",
+				Args:  true,
+				Usage: "change the status of an anime, eg. ani-ar list status hunter-x-hunter-2011 watching",
+				Flags: []cli.Flag{watchlistSourceFlag},
+				Action: func(ctx *cli.Context) error {
+					store, err := watchlist.GetStore()
+					if err != nil {
+						return err
+					}
+					entry, err := store.SetStatus(watchlistSource(ctx), ctx.Args().First(), ctx.Args().Get(1))
+					if err != nil {
+						return err
+					}
+					printWatchlistEntry(entry)
+					return nil
+				},
+			},
+			{
+				Name:  "fav",
+				Args:  true,
+				Usage: "add an anime of the watchlist to the favorites or remove it from them",
+				Flags: []cli.Flag{watchlistSourceFlag},
+				Action: func(ctx *cli.Context) error {
+					store, err := watchlist.GetStore()
+					if err != nil {
+						return err
+					}
+					source, id := watchlistSource(ctx), ctx.Args().First()
+					current, found := store.Get(source, id)
+					if !found {
+						return watchlist.ErrNotFound
+					}
+					entry, err := store.SetFavorite(source, id, !current.Favorite)
+					if err != nil {
+						return err
+					}
+					printWatchlistEntry(entry)
+					return nil
+				},
+			},
+		},
+	}
+}
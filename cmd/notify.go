@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ani/ani-ar/notify"
+	"github.com/ani/ani-ar/profile"
+)
+
+func loadNotifyBackends() (*notify.Config, []notify.Backend, error) {
+	config, err := notify.LoadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	backends, err := config.GetBackends()
+	if err != nil {
+		return nil, nil, err
+	}
+	return config, backends, nil
+}
+
+func notifyCommand() *cli.Command {
+	watch := func(ctx *cli.Context) error {
+		config, backends, err := loadNotifyBackends()
+		if err != nil {
+			return err
+		}
+		interval := ctx.Duration("interval")
+		if interval == 0 {
+			if interval, err = config.GetInterval(); err != nil {
+				return err
+			}
+		}
+		runCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		fmt.Printf("checking the followed anime of the %s profile every %s\n", profile.Active(), interval)
+		return notify.NewChecker(profile.Active(), backends).Run(runCtx, interval)
+	}
+
+	return &cli.Command{
+		Name:   "notify",
+		Usage:  "notify when the followed anime (watchlist and history) get new episodes, the backends are set in notify.json",
+		Action: watch,
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:  "interval",
+				Usage: "how often the anime are checked, the interval of notify.json (or an hour) when it's not set",
+			},
+		},
+		Subcommands: []*cli.Command{
+			{
+				Name:  "check",
+				Usage: "check the followed anime once",
+				Action: func(ctx *cli.Context) error {
+					_, backends, err := loadNotifyBackends()
+					if err != nil {
+						return err
+					}
+					sent, err := notify.NewChecker(profile.Active(), backends).Check(ctx.Context)
+					for _, n := range sent {
+						fmt.Printf("%s: %s\n", n.Title, n.Message)
+					}
+					if err != nil {
+						return err
+					}
+					if len(sent) == 0 {
+						fmt.Println("no new episodes")
+					}
+					return nil
+				},
+			},
+			{
+				Name:  "test",
+				Usage: "send a test notification with every backend",
+				Action: func(ctx *cli.Context) error {
+					_, backends, err := loadNotifyBackends()
+					if err != nil {
+						return err
+					}
+					sendCtx, cancel := context.WithTimeout(ctx.Context, 30*time.Second)
+					defer cancel()
+					return notify.Send(sendCtx, backends, notify.Notification{
+						Profile: profile.Active(),
+						Title:   "ani-ar",
+						Message: "the notifications work",
+					})
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ani/ani-ar/fetcher"
+	"github.com/ani/ani-ar/profile"
+)
+
+func printProfileSettings(name string) {
+	s := profile.GetSettings(name)
+	orDefault := func(v, def string) string {
+		if v == "" {
+			return def + " (default)"
+		}
+		return v
+	}
+	fmt.Printf("profile: %s\n", name)
+	fmt.Printf("  source: %s\n", orDefault(s.Source, "anime3rb"))
+	fmt.Printf("  quality: %s\n", orDefault(s.Quality, "best"))
+	fmt.Printf("  translation: %s\n", orDefault(s.Translation, profile.TranslationSub))
+}
+
+func profileCommand() *cli.Command {
+	listProfiles := func(ctx *cli.Context) error {
+		for _, name := range profile.List() {
+			if name == profile.Active() {
+				fmt.Println("* " + name)
+			} else {
+				fmt.Println("  " + name)
+			}
+		}
+		return nil
+	}
+
+	return &cli.Command{
+		Name:   "profile",
+		Usage:  "manage the profiles, every profile has its own history, watchlist and settings",
+		Action: listProfiles,
+		Subcommands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  "list the profiles, the active one is marked with *",
+				Action: listProfiles,
+			},
+			{
+				Name:  "create",
+				Args:  true,
+				Usage: "create a profile, eg. ani-ar profile create sara",
+				Action: func(ctx *cli.Context) error {
+					return profile.Create(ctx.Args().First())
+				},
+			},
+			{
+				Name:  "remove",
+				Args:  true,
+				Usage: "remove a profile with its history, watchlist and settings",
+				Action: func(ctx *cli.Context) error {
+					return profile.Remove(ctx.Args().First())
+				},
+			},
+			{
+				Name:  "settings",
+				Usage: "show the settings of the profile picked with --profile",
+				Action: func(ctx *cli.Context) error {
+					printProfileSettings(profile.Active())
+					return nil
+				},
+			},
+			{
+				Name:  "set",
+				Args:  true,
+				Usage: "change a setting of the profile picked with --profile: source, quality or translation, an empty value resets it, eg. ani-ar --profile sara profile set translation dub",
+				Action: func(ctx *cli.Context) error {
+					name := profile.Active()
+					s := profile.GetSettings(name)
+					value := ctx.Args().Get(1)
+					switch ctx.Args().First() {
+					case "source":
+						s.Source = value
+					case "quality":
+						s.Quality = value
+					case "translation":
+						s.Translation = value
+					default:
+						return errors.New("unknown setting, it should be source, quality or translation")
+					}
+					if err := fetcher.ValidateSettings(s); err != nil {
+						return err
+					}
+					if err := profile.SaveSettings(name, s); err != nil {
+						return err
+					}
+					printProfileSettings(name)
+					return nil
+				},
+			},
+		},
+	}
+}
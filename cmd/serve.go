@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/ani/ani-ar/api"
+	"github.com/ani/ani-ar/cache"
+	"github.com/ani/ani-ar/config"
+	"github.com/ani/ani-ar/jellyfin"
+	"github.com/ani/ani-ar/notify"
+	"github.com/ani/ani-ar/profile"
+	"github.com/ani/ani-ar/scraper"
+)
+
+func serveCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "serve the api and the stream proxy, every flag can also be set with its environment variable",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "addr",
+				Value:   "127.0.0.1:8000",
+				Usage:   "the address the http server listens on",
+				EnvVars: []string{"ANI_AR_ADDR"},
+			},
+			&cli.StringSliceFlag{
+				Name:    "origins",
+				Usage:   "the allowed cors origins, it can be repeated or comma separated (default to *)",
+				EnvVars: []string{"ANI_AR_ALLOWED_ORIGINS"},
+			},
+			&cli.StringSliceFlag{
+				Name:    "trusted-proxies",
+				Usage:   "the reverse proxies ips or cidr ranges whose X-Forwarded-* headers are trusted",
+				EnvVars: []string{"ANI_AR_TRUSTED_PROXIES"},
+			},
+			&cli.StringFlag{
+				Name:    "base-path",
+				Value:   "",
+				Usage:   "serve every route under the path, eg. /ani behind a reverse proxy",
+				EnvVars: []string{"ANI_AR_BASE_PATH"},
+			},
+			&cli.DurationFlag{
+				Name:    "read-timeout",
+				Value:   10 * time.Minute,
+				Usage:   "the maximum duration for reading a request",
+				EnvVars: []string{"ANI_AR_READ_TIMEOUT"},
+			},
+			&cli.DurationFlag{
+				Name:    "write-timeout",
+				Value:   0,
+				Usage:   "the maximum duration for writing a response, 0 disables it so the video streams aren't cut",
+				EnvVars: []string{"ANI_AR_WRITE_TIMEOUT"},
+			},
+			&cli.DurationFlag{
+				Name:    "shutdown-wait",
+				Value:   time.Second,
+				Usage:   "how long to wait after ctrl+c or SIGTERM before the shutdown starts",
+				EnvVars: []string{"ANI_AR_SHUTDOWN_WAIT"},
+			},
+			&cli.DurationFlag{
+				Name:    "shutdown-timeout",
+				Value:   10 * time.Second,
+				Usage:   "how long the shutdown waits for the open requests before closing them",
+				EnvVars: []string{"ANI_AR_SHUTDOWN_TIMEOUT"},
+			},
+			&cli.BoolFlag{
+				Name:    "notify",
+				Usage:   "check the followed anime of every profile for new episodes, the backends of notify.json are notified and the /api/events subscribers get them",
+				EnvVars: []string{"ANI_AR_NOTIFY"},
+			},
+			&cli.StringFlag{
+				Name:    "cache",
+				Value:   "memory",
+				Usage:   "where the responses of the sources and jikan are cached, memory or disk",
+				EnvVars: []string{"ANI_AR_CACHE"},
+			},
+			&cli.StringFlag{
+				Name:    "cache-dir",
+				Value:   "",
+				Usage:   "the folder of the disk cache, default to the cache folder of the config folder",
+				EnvVars: []string{"ANI_AR_CACHE_DIR"},
+			},
+			&cli.StringFlag{
+				Name:    "cache-ttl",
+				Value:   "",
+				Usage:   "the ttls of the cached resources, eg. search=30m,videos=10m (0 disables one)",
+				EnvVars: []string{"ANI_AR_CACHE_TTL"},
+			},
+			&cli.StringFlag{
+				Name:    "rate-limit",
+				Value:   "",
+				Usage:   "the requests a client ip can send to the route groups, eg. search=30/1m,anime=120/1m,api=600/1m,stream=300/1m (0 disables one)",
+				EnvVars: []string{"ANI_AR_RATE_LIMIT"},
+			},
+			&cli.StringFlag{
+				Name:    "key-rate-limit",
+				Value:   "",
+				Usage:   "the requests an api key can send to the route groups, eg. search=60/1m,anime=300/1m,api=1200/1m,stream=600/1m (0 disables one)",
+				EnvVars: []string{"ANI_AR_KEY_RATE_LIMIT"},
+			},
+			&cli.IntFlag{
+				Name:    "scraper-concurrency",
+				Value:   scraper.DefaultConcurrency,
+				Usage:   "the requests to the anime sources and jikan in flight at once, 0 removes the cap",
+				EnvVars: []string{"ANI_AR_SCRAPER_CONCURRENCY"},
+			},
+			&cli.StringFlag{
+				Name:    "https",
+				Value:   "",
+				Usage:   "serve over https on the address, eg. 0.0.0.0:443",
+				EnvVars: []string{"ANI_AR_HTTPS_ADDR"},
+			},
+			&cli.StringSliceFlag{
+				Name:    "domain",
+				Usage:   "a domain of the certificate, it can be repeated (default to the host of the https address)",
+				EnvVars: []string{"ANI_AR_DOMAINS"},
+			},
+			&cli.StringFlag{
+				Name:    "cert",
+				Value:   "",
+				Usage:   "the certificate file, the certificates are issued with acme when it isn't set",
+				EnvVars: []string{"ANI_AR_CERT_FILE"},
+			},
+			&cli.StringFlag{
+				Name:    "key",
+				Value:   "",
+				Usage:   "the key file of the certificate",
+				EnvVars: []string{"ANI_AR_KEY_FILE"},
+			},
+			&cli.StringFlag{
+				Name:    "redirect",
+				Value:   "",
+				Usage:   "the address of an http server redirecting to https and answering the acme challenges, eg. 0.0.0.0:80",
+				EnvVars: []string{"ANI_AR_REDIRECT_ADDR"},
+			},
+			&cli.StringFlag{
+				Name:    "acme-url",
+				Value:   "",
+				Usage:   "the acme directory url, default to let's encrypt",
+				EnvVars: []string{"ANI_AR_ACME_URL"},
+			},
+			&cli.StringFlag{
+				Name:    "acme-email",
+				Value:   "",
+				Usage:   "the contact email of the acme account",
+				EnvVars: []string{"ANI_AR_ACME_EMAIL"},
+			},
+			&cli.StringFlag{
+				Name:    "acme-cache",
+				Value:   "",
+				Usage:   "the folder the acme certificates are kept in, default to the config folder",
+				EnvVars: []string{"ANI_AR_ACME_CACHE"},
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			httpAddr := ctx.String("addr")
+			if ctx.String("https") != "" {
+				// the http server only redirects to https
+				httpAddr = ctx.String("redirect")
+			}
+			if err := setupCache(ctx.String("cache"), ctx.String("cache-dir"), ctx.String("cache-ttl")); err != nil {
+				return err
+			}
+			rateLimits, err := api.ParseRateLimits(ctx.String("rate-limit"), api.DefaultRateLimits)
+			if err != nil {
+				return err
+			}
+			keyRateLimits, err := api.ParseRateLimits(ctx.String("key-rate-limit"), api.DefaultKeyRateLimits)
+			if err != nil {
+				return err
+			}
+			scraper.SetConcurrency(ctx.Int("scraper-concurrency"))
+			if ctx.Bool("notify") {
+				if err := startNotifyCheckers(ctx.Context); err != nil {
+					return err
+				}
+			}
+			_, err = api.Serve(&api.ServerConfig{
+				HttpAddr:                         httpAddr,
+				HttpsAddr:                        ctx.String("https"),
+				CertificateDomains:               ctx.StringSlice("domain"),
+				CertFile:                         ctx.String("cert"),
+				KeyFile:                          ctx.String("key"),
+				CertificateCacheDir:              ctx.String("acme-cache"),
+				AcmeDirectoryUrl:                 ctx.String("acme-url"),
+				AcmeEmail:                        ctx.String("acme-email"),
+				AllowedOrigins:                   ctx.StringSlice("origins"),
+				TrustedProxies:                   ctx.StringSlice("trusted-proxies"),
+				BasePath:                         ctx.String("base-path"),
+				ReadTimeout:                      ctx.Duration("read-timeout"),
+				WriteTimeout:                     ctx.Duration("write-timeout"),
+				ShowStartBanner:                  true,
+				TimeToWaitBeforeGracefulShutdown: ctx.Duration("shutdown-wait"),
+				ShutdownTimeout:                  ctx.Duration("shutdown-timeout"),
+				Routes:                           []api.RouteGroup{jellyfin.InitiateRoutes},
+				RateLimits:                       rateLimits,
+				KeyRateLimits:                    keyRateLimits,
+			})
+			return err
+		},
+	}
+}
+
+// startNotifyCheckers runs a new episodes checker for every profile in the
+// background, without backends the detections are only sent as events
+func startNotifyCheckers(ctx context.Context) error {
+	config, backends, err := loadNotifyBackends()
+	if err != nil && !errors.Is(err, notify.ErrNoBackends) {
+		return err
+	}
+	if config == nil {
+		if config, err = notify.LoadConfig(); err != nil {
+			return err
+		}
+	}
+	interval, err := config.GetInterval()
+	if err != nil {
+		return err
+	}
+	for _, name := range profile.List() {
+		go notify.NewChecker(name, backends).Run(ctx, interval)
+	}
+	return nil
+}
+
+var errUnknownCacheBackend = errors.New("unknown cache, it should be memory or disk")
+
+// setupCache picks the backend of the default cache and the ttls of its resources
+func setupCache(backend, dir, ttls string) error {
+	if err := cache.SetTTLs(ttls); err != nil {
+		return err
+	}
+	switch backend {
+	case "memory", "":
+		return nil
+	case "disk":
+		if dir == "" {
+			dir = config.Path("cache")
+		}
+		disk, err := cache.NewDisk(dir)
+		if err != nil {
+			return err
+		}
+		cache.SetDefault(cache.New(disk))
+		return nil
+	}
+	return errUnknownCacheBackend
+}
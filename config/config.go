@@ -0,0 +1,26 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/kirsle/configdir"
+)
+
+// Dir returns the ani-ar config folder, `ANI_AR_CONFIG_DIR` replaces it when it's set
+func Dir() string {
+	if dir := os.Getenv("ANI_AR_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(configdir.LocalConfig(), "ani-ar")
+}
+
+// Path returns the path of a file inside the config folder
+func Path(name string) string {
+	return filepath.Join(Dir(), name)
+}
+
+// EnsureDir creates the config folder if it doesn't exist
+func EnsureDir() error {
+	return os.MkdirAll(Dir(), os.ModePerm)
+}
@@ -1,6 +1,7 @@
 package download
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -13,6 +14,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/ani/ani-ar/fetcher"
+	"github.com/ani/ani-ar/history"
 	"github.com/ani/ani-ar/types"
 )
 
@@ -20,24 +22,12 @@ type Downloader struct {
 	Fetcher fetcher.Fetcher
 }
 
-var p *tea.Program
-
 type progressWriter struct {
 	total      int
 	downloaded int
-	file       *os.File
-	reader     io.Reader
 	onProgress func(float64)
 }
 
-func (pw *progressWriter) Start() {
-	// TeeReader calls pw.Write() each time a new response is received
-	_, err := io.Copy(pw.file, io.TeeReader(pw.reader, pw))
-	if err != nil {
-		p.Send(progressErrMsg{err})
-	}
-}
-
 func (pw *progressWriter) Write(p []byte) (int, error) {
 	pw.downloaded += len(p)
 	if pw.total > 0 && pw.onProgress != nil {
@@ -69,55 +59,134 @@ func (d *Downloader) getEpisodes(title string) ([]types.AniEpisode, error) {
 	return episodes, nil
 }
 
-func (d *Downloader) downloadEpisodeToDisk(episode types.AniEpisode, path string) error {
+// ErrPaused is the cancel cause of the paused downloads, their partial file is kept to resume them
+var ErrPaused = errors.New("the download is paused")
+
+// partial downloads are written next to the episode file and renamed once they are done
+const partialSuffix = ".part"
+
+// episodeVideo returns the video of the episode in the quality (the best one
+// when it's empty or missing) with the request headers its host expects
+func episodeVideo(episode types.AniEpisode, quality string) (*types.AniVideo, error) {
+	if episode.GetPlayersWithQuality == nil {
+		return nil, errors.New("the episode has no video sources")
+	}
+	video := types.SelectVideo(episode.GetPlayersWithQuality(), quality)
+	if video == nil {
+		return nil, errors.New("no video sources found for the episode")
+	}
+	return video, nil
+}
+
+// DownloadEpisodeTo writes the episode video in the quality to path, onProgress
+// is called with the downloaded ratio when the video size is known. It doesn't
+// print anything so it can be used from the tui. The partial file of a paused
+// download (see ErrPaused) is resumed by the next call, a failed download
+// doesn't leave a partial file
+func (d *Downloader) DownloadEpisodeTo(ctx context.Context, episode types.AniEpisode, quality string, path string, onProgress func(float64)) (err error) {
 	log.Printf("downloading episode (%v) to %s\n", episode.Number, path)
-	url := episode.GetPlayerUrl()
-	log.Printf("found the episode url : %s\n", url)
-	// Create the file
-	out, err := os.Create(path)
+	video, err := episodeVideo(episode, quality)
 	if err != nil {
 		return err
 	}
-	defer out.Close()
+	log.Printf("found the episode url : %s\n", video.Src)
+
+	partialPath := path + partialSuffix
+	defer func() {
+		if err != nil && !errors.Is(context.Cause(ctx), ErrPaused) {
+			os.Remove(partialPath)
+		}
+	}()
 
 	// Get the data
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, video.Src, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range video.Headers {
+		req.Header.Set(k, v)
+	}
+	var downloaded int64
+	if info, err := os.Stat(partialPath); err == nil && info.Size() > 0 {
+		downloaded = info.Size()
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", downloaded))
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	// Check for a successful response
-	if resp.StatusCode != http.StatusOK {
+	// Check for a successful response, the hosts ignoring the range send the whole video again
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && downloaded > 0:
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	case resp.StatusCode == http.StatusOK:
+		downloaded = 0
+	default:
 		return errors.New("failed to fetch video data")
 	}
 
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	// Create the file
+	out, err := os.OpenFile(partialPath, flags, 0644)
+	if err != nil {
+		return err
+	}
+
 	pw := &progressWriter{
-		total:  int(resp.ContentLength),
-		file:   out,
-		reader: resp.Body,
-		onProgress: func(ratio float64) {
-			p.Send(progressMsg(ratio))
-		},
+		downloaded: int(downloaded),
+		onProgress: onProgress,
+	}
+	if resp.ContentLength > 0 {
+		pw.total = int(downloaded + resp.ContentLength)
+	}
+	// TeeReader calls pw.Write() each time a new response is received
+	_, err = io.Copy(out, io.TeeReader(resp.Body, pw))
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(partialPath, path); err != nil {
+		return err
 	}
+	return history.GetStore().MarkDownloaded(episode.Anime.Id, episode.Number, path)
+}
 
-	m := model{
-		pw:       pw,
+// downloads the episode while showing a progress bar in its own bubbletea program
+func (d *Downloader) downloadEpisodeToDisk(episode types.AniEpisode, path string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := tea.NewProgram(model{
 		progress: progress.New(progress.WithDefaultGradient()),
-	}
-	// Start Bubble Tea
-	p = tea.NewProgram(m)
+	})
 
-	// Start the download
-	go pw.Start()
+	done := make(chan error, 1)
+	go func() {
+		err := d.DownloadEpisodeTo(ctx, episode, "", path, func(ratio float64) {
+			p.Send(progressMsg(ratio))
+		})
+		if err != nil {
+			p.Send(progressErrMsg{err})
+		} else {
+			p.Send(progressMsg(1.0))
+		}
+		done <- err
+	}()
 
 	if _, err := p.Run(); err != nil {
 		fmt.Println("error running program:", err)
 		os.Exit(1)
 	}
-	// Copy the response body to the file and update the progress bar
-	_, err = io.Copy(out, resp.Body)
-	return err
+	// the program quits early when the download is cancelled
+	cancel()
+	return <-done
 }
 
 func (d *Downloader) DownloadAllEpisodes(title string, path string) error {
@@ -0,0 +1,125 @@
+package download
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ani/ani-ar/types"
+)
+
+func TestDownloadEpisodeSendsTheHostHeaders(t *testing.T) {
+	t.Setenv("ANI_AR_CONFIG_DIR", t.TempDir())
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the hosts of the protected sources refuse the requests without their referer
+		if r.Header.Get("Referer") != "https://allanime.to" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer srv.Close()
+
+	episode := types.AniEpisode{
+		Anime:  types.AniResult{Id: "anime"},
+		Number: 1,
+		GetPlayersWithQuality: func() []types.AniVideo {
+			return []types.AniVideo{
+				{Src: srv.URL + "/480", Res: "480", Headers: map[string]string{"Referer": "https://allanime.to"}},
+				{Src: srv.URL + "/1080", Res: "1080", Headers: map[string]string{"Referer": "https://allanime.to"}},
+			}
+		},
+		GetPlayerUrl: func() string { return srv.URL + "/1080" },
+	}
+
+	tests := map[string]string{
+		// the tui queue doesn't pick a quality
+		"":    "/1080",
+		"480": "/480",
+	}
+	for quality, want := range tests {
+		path := filepath.Join(t.TempDir(), "episode.mp4")
+		if err := (&Downloader{}).DownloadEpisodeTo(context.Background(), episode, quality, path, nil); err != nil {
+			t.Fatalf("quality %q: %v", quality, err)
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != want {
+			t.Fatalf("quality %q downloaded %s, want %s", quality, b, want)
+		}
+	}
+}
+
+func TestQueueFileIsLocked(t *testing.T) {
+	// the config folder doesn't exist on the first run
+	path := filepath.Join(t.TempDir(), "ani-ar", "downloads.json")
+	q, err := LoadQueue(path, GetDownloader(1), t.TempDir(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadQueue(path, GetDownloader(1), t.TempDir(), nil); err != ErrQueueLocked {
+		t.Fatalf("got %v, want ErrQueueLocked while the queue is loaded", err)
+	}
+
+	q.lock.Close()
+	if _, err := LoadQueue(path, GetDownloader(1), t.TempDir(), nil); err != nil {
+		t.Fatalf("the queue should load once the lock is released, got %v", err)
+	}
+}
+
+func TestQueueRoots(t *testing.T) {
+	dir, other := t.TempDir(), t.TempDir()
+	t.Setenv("ANI_AR_DOWNLOADS_ROOTS", other+string(filepath.ListSeparator)+"relative")
+	q := &Queue{dir: dir}
+
+	tests := map[string]bool{
+		dir:                                    true,
+		filepath.Join(dir, "anime"):            true,
+		filepath.Join(dir, "anime", "..", "x"): true,
+		filepath.Join(other, "anime"):          true,
+		dir + "-other":                         false,
+		filepath.Join(dir, "..", "escaped"):    false,
+		filepath.Join(dir, "..") + "/":         false,
+		"relative":                             false,
+		"relative/anime":                       false,
+		"":                                     false,
+	}
+	for path, want := range tests {
+		if got := q.InRoots(path); got != want {
+			t.Errorf("%q: got %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestQueueIsntChangedWhenItCantBeSaved(t *testing.T) {
+	// the folder of the jobs file is a file, it can't be written
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	q := &Queue{dir: t.TempDir(), path: filepath.Join(blocker, "downloads.json"), wake: make(chan struct{}, 1)}
+
+	episode := types.AniEpisode{Anime: types.AniResult{Id: "anime"}, Number: 1}
+	jobs, err := q.AddWith(JobOptions{}, episode)
+	if err == nil || jobs != nil {
+		t.Fatalf("the save error should be returned, got %v %v", jobs, err)
+	}
+	if len(q.Jobs()) != 0 || q.nextId != 0 {
+		t.Fatalf("the jobs shouldn't be queued without being saved, got %+v", q.Jobs())
+	}
+
+	q.path = ""
+	jobs, err = q.AddWith(JobOptions{}, episode)
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("got %v %v", jobs, err)
+	}
+	q.jobs[0].Status = JobDone
+	if job, err := q.Pause(jobs[0].Id); err != ErrJobNotPending || job.Status != JobDone {
+		t.Fatalf("a finished job shouldn't be paused, got %s %v", job.Status, err)
+	}
+}
@@ -27,7 +27,6 @@ func finalPause() tea.Cmd {
 }
 
 type model struct {
-	pw       *progressWriter
 	progress progress.Model
 	err      error
 }
@@ -0,0 +1,542 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ani/ani-ar/events"
+	"github.com/ani/ani-ar/fetcher"
+	"github.com/ani/ani-ar/filelock"
+	"github.com/ani/ani-ar/types"
+	"github.com/goccy/go-json"
+)
+
+const (
+	JobQueued      = "queued"
+	JobDownloading = "downloading"
+	JobPaused      = "paused"
+	JobDone        = "done"
+	JobFailed      = "failed"
+	JobCancelled   = "cancelled"
+)
+
+var (
+	ErrJobNotFound   = errors.New("download job not found")
+	ErrJobNotPaused  = errors.New("only the paused or failed jobs can be resumed")
+	ErrJobNotPending = errors.New("only the queued or downloading jobs can be paused")
+	ErrQueueLocked   = errors.New("the download queue is used by another ani-ar server")
+	// the cancel cause of the removed jobs
+	errCancelled = errors.New("the download is cancelled")
+)
+
+// Job is a queued episode download
+type Job struct {
+	Id int `json:"id"`
+	// the profile and the source the episode is found again with after a restart
+	Profile  string           `json:"profile,omitempty"`
+	Source   string           `json:"source,omitempty"`
+	Episode  types.AniEpisode `json:"episode"`
+	Quality  string           `json:"quality,omitempty"`
+	Path     string           `json:"path"`
+	Status   string           `json:"status"`
+	Progress float64          `json:"progress"`
+	Error    string           `json:"error,omitempty"`
+	AddedAt  time.Time        `json:"addedAt"`
+}
+
+// JobOptions are the options of the added jobs, the empty ones use the queue defaults
+type JobOptions struct {
+	Profile string
+	Source  string
+	Quality string
+	// the folder the anime folders are created in
+	Dir string
+}
+
+// Queue downloads the added episodes one after the other in the background,
+// the queues loaded from a file keep their jobs across restarts
+type Queue struct {
+	downloader *Downloader
+	dir        string
+	// the jobs file, the queue isn't saved without it
+	path string
+	// the lock of the jobs file, it's held as long as the process runs
+	lock *os.File
+	// called from the queue goroutine every time a job changes
+	onUpdate func(Job)
+
+	mu     sync.Mutex
+	jobs   []*Job
+	nextId int
+	wake   chan struct{}
+	// cancels the running download
+	cancelRunning context.CancelCauseFunc
+}
+
+func NewQueue(d *Downloader, dir string, onUpdate func(Job)) *Queue {
+	q := &Queue{
+		downloader: d,
+		dir:        dir,
+		onUpdate:   onUpdate,
+		wake:       make(chan struct{}, 1),
+	}
+	go q.run()
+	return q
+}
+
+// LoadQueue returns the queue of the jobs file, the interrupted downloads are
+// queued again and resumed from their partial file. The file is locked so
+// two servers don't run the same jobs, ErrQueueLocked is returned when
+// another process has it
+func LoadQueue(path string, d *Downloader, dir string, onUpdate func(Job)) (*Queue, error) {
+	lock, err := filelock.TryLock(path + ".lock")
+	if errors.Is(err, filelock.ErrLocked) {
+		return nil, ErrQueueLocked
+	}
+	if err != nil {
+		return nil, err
+	}
+	q := &Queue{
+		downloader: d,
+		dir:        dir,
+		path:       path,
+		lock:       lock,
+		onUpdate:   onUpdate,
+		wake:       make(chan struct{}, 1),
+	}
+	b, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		lock.Close()
+		return nil, err
+	}
+	if len(b) > 0 {
+		var saved struct {
+			Jobs []*Job `json:"jobs"`
+		}
+		if err := json.Unmarshal(b, &saved); err != nil {
+			lock.Close()
+			return nil, errors.New("couldn't parse the downloads file, reason: " + err.Error())
+		}
+		q.jobs = saved.Jobs
+	}
+	for _, job := range q.jobs {
+		if job.Status == JobDownloading {
+			job.Status = JobQueued
+		}
+		q.nextId = max(q.nextId, job.Id)
+	}
+	go q.run()
+	q.signal()
+	return q, nil
+}
+
+// Roots returns the folders the jobs can be saved in, the folder of the queue
+// and the ones of `ANI_AR_DOWNLOADS_ROOTS` (separated like PATH)
+func (q *Queue) Roots() []string {
+	roots := []string{filepath.Clean(q.dir)}
+	for _, root := range filepath.SplitList(os.Getenv("ANI_AR_DOWNLOADS_ROOTS")) {
+		if filepath.IsAbs(root) {
+			roots = append(roots, filepath.Clean(root))
+		}
+	}
+	return roots
+}
+
+// InRoots reports whether the absolute folder is one of the roots or inside one
+func (q *Queue) InRoots(dir string) bool {
+	if !filepath.IsAbs(dir) {
+		return false
+	}
+	dir = filepath.Clean(dir)
+	for _, root := range q.Roots() {
+		if dir == root || strings.HasPrefix(dir, strings.TrimSuffix(root, string(filepath.Separator))+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultDir returns `ANI_AR_DOWNLOADS_DIR` or ~/Downloads/ani-ar
+func DefaultDir() string {
+	if dir := os.Getenv("ANI_AR_DOWNLOADS_DIR"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "downloads"
+	}
+	return filepath.Join(home, "Downloads", "ani-ar")
+}
+
+// Dir returns the folder the jobs are saved in by default
+func (q *Queue) Dir() string {
+	return q.dir
+}
+
+// EpisodePath returns where the episode is saved in dir, every anime gets its own folder
+func EpisodePath(dir string, episode types.AniEpisode) string {
+	name := strings.NewReplacer("/", "-", "\\", "-", ":", "-").Replace(episode.Anime.DisplayName)
+	if name == "" {
+		name = episode.Anime.Id
+	}
+	return filepath.Join(dir, name, fmt.Sprintf("%s-episode-%v.mp4", name, episode.Number))
+}
+
+// must be called with the lock held
+func (q *Queue) save() error {
+	if q.path == "" {
+		return nil
+	}
+	b, err := json.Marshal(struct {
+		Jobs []*Job `json:"jobs"`
+	}{q.jobs})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(q.path), os.ModePerm); err != nil {
+		return errors.New("couldn't save the downloads file, reason: " + err.Error())
+	}
+	if err := os.WriteFile(q.path, b, 0644); err != nil {
+		return errors.New("couldn't save the downloads file, reason: " + err.Error())
+	}
+	return nil
+}
+
+func (q *Queue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Add queues the episodes, the ones already waiting in the queue are skipped
+func (q *Queue) Add(episodes ...types.AniEpisode) error {
+	_, err := q.AddWith(JobOptions{}, episodes...)
+	return err
+}
+
+// AddWith queues the episodes with the options and returns the added jobs,
+// the ones already waiting in the queue are skipped. nothing is queued when
+// the jobs file can't be saved
+func (q *Queue) AddWith(opts JobOptions, episodes ...types.AniEpisode) ([]Job, error) {
+	dir := opts.Dir
+	if dir == "" {
+		dir = q.dir
+	}
+	q.mu.Lock()
+	jobsCount, nextId := len(q.jobs), q.nextId
+	var added []Job
+	for _, ep := range episodes {
+		if q.isPending(ep) {
+			continue
+		}
+		q.nextId++
+		job := &Job{
+			Id:      q.nextId,
+			Profile: opts.Profile,
+			Source:  opts.Source,
+			Episode: ep,
+			Quality: opts.Quality,
+			Path:    EpisodePath(dir, ep),
+			Status:  JobQueued,
+			AddedAt: time.Now(),
+		}
+		q.jobs = append(q.jobs, job)
+		added = append(added, *job)
+	}
+	if err := q.save(); err != nil {
+		q.jobs, q.nextId = q.jobs[:jobsCount], nextId
+		q.mu.Unlock()
+		return nil, err
+	}
+	q.mu.Unlock()
+
+	for _, job := range added {
+		q.notify(job)
+	}
+	q.signal()
+	return added, nil
+}
+
+func (q *Queue) isPending(ep types.AniEpisode) bool {
+	for _, job := range q.jobs {
+		if job.Episode.Anime.Id == ep.Anime.Id && job.Episode.Number == ep.Number &&
+			(job.Status == JobQueued || job.Status == JobDownloading || job.Status == JobPaused) {
+			return true
+		}
+	}
+	return false
+}
+
+// Jobs returns a copy of all the jobs in the order they were added
+func (q *Queue) Jobs() []Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	jobs := make([]Job, len(q.jobs))
+	for i, job := range q.jobs {
+		jobs[i] = *job
+	}
+	return jobs
+}
+
+// must be called with the lock held
+func (q *Queue) find(id int) (int, *Job) {
+	for i, job := range q.jobs {
+		if job.Id == id {
+			return i, job
+		}
+	}
+	return -1, nil
+}
+
+func (q *Queue) Get(id int) (Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	_, job := q.find(id)
+	if job == nil {
+		return Job{}, ErrJobNotFound
+	}
+	return *job, nil
+}
+
+// Pause stops the job until it's resumed, a running download keeps its partial file
+func (q *Queue) Pause(id int) (Job, error) {
+	q.mu.Lock()
+	_, job := q.find(id)
+	if job == nil {
+		q.mu.Unlock()
+		return Job{}, ErrJobNotFound
+	}
+	if job.Status != JobQueued && job.Status != JobDownloading {
+		q.mu.Unlock()
+		return *job, ErrJobNotPending
+	}
+	if job.Status == JobDownloading && q.cancelRunning != nil {
+		q.cancelRunning(ErrPaused)
+	}
+	job.Status = JobPaused
+	err := q.save()
+	copied := *job
+	q.mu.Unlock()
+	q.notify(copied)
+	return copied, err
+}
+
+// Resume queues the paused job again, or retries the failed one
+func (q *Queue) Resume(id int) (Job, error) {
+	q.mu.Lock()
+	_, job := q.find(id)
+	if job == nil {
+		q.mu.Unlock()
+		return Job{}, ErrJobNotFound
+	}
+	if job.Status != JobPaused && job.Status != JobFailed {
+		q.mu.Unlock()
+		return *job, ErrJobNotPaused
+	}
+	job.Status = JobQueued
+	job.Error = ""
+	err := q.save()
+	copied := *job
+	q.mu.Unlock()
+	q.notify(copied)
+	q.signal()
+	return copied, err
+}
+
+// Remove cancels the job and removes it from the queue, the downloaded
+// episode is kept but the partial file of an unfinished one is removed
+func (q *Queue) Remove(id int) error {
+	q.mu.Lock()
+	i, job := q.find(id)
+	if job == nil {
+		q.mu.Unlock()
+		return ErrJobNotFound
+	}
+	if job.Status == JobDownloading && q.cancelRunning != nil {
+		// the download removes its partial file
+		q.cancelRunning(errCancelled)
+	} else if job.Status != JobDone {
+		os.Remove(job.Path + partialSuffix)
+	}
+	q.jobs = append(q.jobs[:i], q.jobs[i+1:]...)
+	err := q.save()
+	copied := *job
+	q.mu.Unlock()
+	copied.Status = JobCancelled
+	q.notify(copied)
+	return err
+}
+
+func (q *Queue) notify(job Job) {
+	if q.onUpdate != nil {
+		q.onUpdate(job)
+	}
+	publishJob(job)
+}
+
+// JobEvent is the data of the download events
+type JobEvent struct {
+	Id       int     `json:"id"`
+	AnimeId  string  `json:"animeId"`
+	Anime    string  `json:"anime"`
+	Episode  int     `json:"episode"`
+	Path     string  `json:"path"`
+	Status   string  `json:"status"`
+	Progress float64 `json:"progress"`
+	Error    string  `json:"error,omitempty"`
+}
+
+func publishJob(job Job) {
+	var eventType string
+	switch {
+	case job.Status == JobQueued:
+		eventType = events.DownloadQueued
+	case job.Status == JobDownloading && job.Progress == 0:
+		eventType = events.DownloadStarted
+	case job.Status == JobDownloading:
+		eventType = events.DownloadProgress
+	case job.Status == JobPaused:
+		eventType = events.DownloadPaused
+	case job.Status == JobCancelled:
+		eventType = events.DownloadCancelled
+	case job.Status == JobDone:
+		eventType = events.DownloadDone
+	default:
+		eventType = events.DownloadFailed
+	}
+	events.Publish(eventType, JobEvent{
+		Id:       job.Id,
+		AnimeId:  job.Episode.Anime.Id,
+		Anime:    job.Episode.Anime.DisplayName,
+		Episode:  job.Episode.Number,
+		Path:     job.Path,
+		Status:   job.Status,
+		Progress: job.Progress,
+		Error:    job.Error,
+	})
+}
+
+// updates the job under the lock and notifies with a copy of it, the
+// queue is saved when the status changes
+func (q *Queue) update(job *Job, change func(*Job)) {
+	q.mu.Lock()
+	status := job.Status
+	change(job)
+	if job.Status != status {
+		q.saveInBackground()
+	}
+	copied := *job
+	q.mu.Unlock()
+	q.notify(copied)
+}
+
+// saves the changes of the queue goroutine, nobody is waiting for their
+// error so it's logged and the file is saved again with the next change.
+// must be called with the lock held
+func (q *Queue) saveInBackground() {
+	if err := q.save(); err != nil {
+		log.Println(err)
+	}
+}
+
+// next marks the first queued job as downloading and returns it with the
+// context of its download, both under the lock so a pause can't be missed
+func (q *Queue) next() (*Job, context.Context) {
+	q.mu.Lock()
+	for _, job := range q.jobs {
+		if job.Status == JobQueued {
+			ctx, cancel := context.WithCancelCause(context.Background())
+			q.cancelRunning = cancel
+			job.Status = JobDownloading
+			q.saveInBackground()
+			copied := *job
+			q.mu.Unlock()
+			q.notify(copied)
+			return job, ctx
+		}
+	}
+	q.mu.Unlock()
+	return nil, nil
+}
+
+func (q *Queue) run() {
+	for range q.wake {
+		for job, ctx := q.next(); job != nil; job, ctx = q.next() {
+			q.download(ctx, job)
+		}
+	}
+}
+
+// resolve returns the episode of the job, the episodes loaded from the jobs
+// file are found again in the profile fetcher or the fetcher of their source
+func (q *Queue) resolve(job *Job) (types.AniEpisode, error) {
+	q.mu.Lock()
+	episode, profileName, source := job.Episode, job.Profile, job.Source
+	q.mu.Unlock()
+	if episode.GetPlayersWithQuality != nil {
+		return episode, nil
+	}
+
+	var f fetcher.Fetcher
+	if profileName != "" {
+		f = fetcher.GetProfileFetcher(profileName)
+	}
+	if f == nil || (source != "" && fetcher.GetFetcherName(f) != source) {
+		var err error
+		if f, err = fetcher.GetFetcherByName(source); err != nil {
+			return episode, err
+		}
+	}
+	anime := f.GetAnimeResult(episode.Anime.Id)
+	if anime == nil {
+		return episode, errors.New("anime not found")
+	}
+	episodes := f.GetEpisodes(*anime)
+	if episode.Number < 1 || episode.Number > len(episodes) {
+		return episode, errors.New("episode out of range")
+	}
+	return episodes[episode.Number-1], nil
+}
+
+func (q *Queue) download(ctx context.Context, job *Job) {
+	episode, err := q.resolve(job)
+	if err == nil {
+		// the progress is reported by percent to not flood the listener
+		lastPercent := 0
+		err = q.downloader.DownloadEpisodeTo(ctx, episode, job.Quality, job.Path, func(ratio float64) {
+			if percent := int(ratio * 100); percent > lastPercent {
+				lastPercent = percent
+				q.update(job, func(j *Job) { j.Progress = ratio })
+			}
+		})
+	}
+
+	// the status is checked under the lock a pause or a removal takes too
+	q.mu.Lock()
+	q.cancelRunning = nil
+	if cause := context.Cause(ctx); errors.Is(cause, errCancelled) || (cause != nil && err != nil) {
+		// removed, or paused before the download finished, the status is already set
+		q.mu.Unlock()
+		return
+	}
+	if err != nil {
+		job.Status = JobFailed
+		job.Error = err.Error()
+	} else {
+		// a pause that came once the episode was written doesn't undo it
+		job.Status = JobDone
+		job.Progress = 1
+	}
+	q.saveInBackground()
+	copied := *job
+	q.mu.Unlock()
+	q.notify(copied)
+}
@@ -0,0 +1,141 @@
+package events
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// the types of the events, they are grouped by their first part so the
+// subscribers can filter on a group (eg. download)
+const (
+	DownloadQueued    = "download.queued"
+	DownloadStarted   = "download.started"
+	DownloadProgress  = "download.progress"
+	DownloadPaused    = "download.paused"
+	DownloadCancelled = "download.cancelled"
+	DownloadDone      = "download.done"
+	DownloadFailed    = "download.failed"
+
+	JellyfinRevisionStarted = "jellyfin.revision.started"
+	JellyfinRevisionDiff    = "jellyfin.revision.diff"
+	JellyfinRevisionDone    = "jellyfin.revision.done"
+	JellyfinRevisionFailed  = "jellyfin.revision.failed"
+	JellyfinItemAdded       = "jellyfin.item.added"
+	JellyfinItemUpdated     = "jellyfin.item.updated"
+	JellyfinItemRemoved     = "jellyfin.item.removed"
+	JellyfinRefreshStarted  = "jellyfin.refresh.started"
+	JellyfinRefreshDone     = "jellyfin.refresh.done"
+	JellyfinRefreshFailed   = "jellyfin.refresh.failed"
+
+	EpisodesNew = "episodes.new"
+)
+
+// Event is something that happened in a long running operation, its data
+// depends on its type
+type Event struct {
+	Id   uint64      `json:"id"`
+	Type string      `json:"type"`
+	Time time.Time   `json:"time"`
+	Data interface{} `json:"data"`
+}
+
+// Matches reports whether the event is one of the types or groups, every event matches no filters
+func (e Event) Matches(filters []string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	for _, f := range filters {
+		if e.Type == f || strings.HasPrefix(e.Type, f+".") {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// the last events are kept for the subscribers reconnecting with their last event id
+	historySize = 100
+	// the events of a subscriber that doesn't keep up are dropped past it
+	subscriberBuffer = 64
+)
+
+// Bus dispatches the published events to the subscribers
+type Bus struct {
+	mu          sync.Mutex
+	nextId      uint64
+	history     []Event
+	subscribers map[*Subscription]struct{}
+}
+
+// Subscription receives the events matching its filters on C until it's closed
+type Subscription struct {
+	C       <-chan Event
+	c       chan Event
+	filters []string
+	bus     *Bus
+	once    sync.Once
+}
+
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[*Subscription]struct{})}
+}
+
+var bus = NewBus()
+
+// Publish sends an event of the type to the subscribers of the default bus
+func Publish(eventType string, data interface{}) {
+	bus.Publish(eventType, data)
+}
+
+// Subscribe subscribes to the default bus
+func Subscribe(lastId uint64, filters []string) *Subscription {
+	return bus.Subscribe(lastId, filters)
+}
+
+func (b *Bus) Publish(eventType string, data interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextId++
+	e := Event{Id: b.nextId, Type: eventType, Time: time.Now(), Data: data}
+	b.history = append(b.history, e)
+	if len(b.history) > historySize {
+		b.history = b.history[len(b.history)-historySize:]
+	}
+	for s := range b.subscribers {
+		if !e.Matches(s.filters) {
+			continue
+		}
+		select {
+		case s.c <- e:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a subscription to the events matching the filters (types
+// or groups), the kept events after lastId are sent first when it's set
+func (b *Bus) Subscribe(lastId uint64, filters []string) *Subscription {
+	c := make(chan Event, subscriberBuffer+historySize)
+	s := &Subscription{C: c, c: c, filters: filters, bus: b}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if lastId > 0 {
+		for _, e := range b.history {
+			if e.Id > lastId && e.Matches(filters) {
+				c <- e
+			}
+		}
+	}
+	b.subscribers[s] = struct{}{}
+	return s
+}
+
+// Close stops the subscription, C isn't closed so it can't be mistaken for an event
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.bus.mu.Lock()
+		defer s.bus.mu.Unlock()
+		delete(s.bus.subscribers, s)
+	})
+}
@@ -0,0 +1,112 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func receive(t *testing.T, s *Subscription) Event {
+	t.Helper()
+	select {
+	case e := <-s.C:
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for an event")
+		return Event{}
+	}
+}
+
+func assertNoEvent(t *testing.T, s *Subscription) {
+	t.Helper()
+	select {
+	case e := <-s.C:
+		t.Fatalf("got the unexpected event %+v", e)
+	default:
+	}
+}
+
+func TestSubscribersGetTheirEvents(t *testing.T) {
+	b := NewBus()
+	all := b.Subscribe(0, nil)
+	downloads := b.Subscribe(0, []string{"download"})
+	revisions := b.Subscribe(0, []string{JellyfinRevisionDone, EpisodesNew})
+
+	b.Publish(DownloadQueued, "naruto 1")
+	b.Publish(JellyfinRevisionDone, nil)
+	// not in the download group
+	b.Publish("downloads.other", nil)
+
+	for i, want := range []string{DownloadQueued, JellyfinRevisionDone, "downloads.other"} {
+		if e := receive(t, all); e.Type != want || e.Id != uint64(i+1) {
+			t.Fatalf("got %+v, want the event %d of %s", e, i+1, want)
+		}
+	}
+	if e := receive(t, downloads); e.Type != DownloadQueued || e.Data != "naruto 1" {
+		t.Fatalf("got %+v", e)
+	}
+	assertNoEvent(t, downloads)
+	if e := receive(t, revisions); e.Type != JellyfinRevisionDone {
+		t.Fatalf("got %+v", e)
+	}
+	assertNoEvent(t, revisions)
+
+	all.Close()
+	all.Close()
+	b.Publish(DownloadDone, nil)
+	assertNoEvent(t, all)
+	if e := receive(t, downloads); e.Type != DownloadDone {
+		t.Fatalf("got %+v", e)
+	}
+}
+
+func TestSubscribeReplaysTheEventsAfterTheLastId(t *testing.T) {
+	b := NewBus()
+	for i := 0; i < historySize+50; i++ {
+		b.Publish(DownloadProgress, i)
+	}
+	if len(b.history) != historySize || b.history[0].Id != 51 {
+		t.Fatalf("the history should keep the last %d events, got %d from %d", historySize, len(b.history), b.history[0].Id)
+	}
+
+	// the events before the history are gone
+	s := b.Subscribe(1, nil)
+	for id := uint64(51); id <= historySize+50; id++ {
+		if e := receive(t, s); e.Id != id {
+			t.Fatalf("got the event %d, want %d", e.Id, id)
+		}
+	}
+	assertNoEvent(t, s)
+
+	// the replay is filtered too
+	b.Publish(DownloadDone, nil)
+	b.Publish(EpisodesNew, nil)
+	s = b.Subscribe(historySize+50, []string{"episodes"})
+	if e := receive(t, s); e.Type != EpisodesNew || e.Id != historySize+52 {
+		t.Fatalf("got %+v", e)
+	}
+	assertNoEvent(t, s)
+
+	// without a last id only the new events are sent
+	s = b.Subscribe(0, nil)
+	assertNoEvent(t, s)
+}
+
+func TestSlowSubscribersDropTheEvents(t *testing.T) {
+	b := NewBus()
+	s := b.Subscribe(0, nil)
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 2*(subscriberBuffer+historySize); i++ {
+			b.Publish(DownloadProgress, i)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the publisher shouldn't wait for the subscribers")
+	}
+	if len(s.C) != subscriberBuffer+historySize {
+		t.Fatalf("got %d events", len(s.C))
+	}
+}
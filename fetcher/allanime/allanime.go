@@ -2,38 +2,67 @@ package allanime
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/ani/ani-ar/cache"
+	"github.com/ani/ani-ar/scraper"
 	"github.com/ani/ani-ar/types"
 	"github.com/goccy/go-json"
 	"gopkg.in/vansante/go-ffprobe.v2"
 )
 
-type AllAnimeFetcher struct{}
+type AllAnimeFetcher struct {
+	// sub or dub
+	translationType string
+}
 
 const allanimeApi = "https://api.allanime.day"
 
-const subType = "sub"
+const (
+	subType = "sub"
+	dubType = "dub"
+)
 
-// TODO: will we support dubbed animes ??
-// const dubType = "dub"
+// the allanime video hosts reject requests without this referer
+const allanimeReferer = "https://allmanga.to"
 
 func GetAllAnimeFetcher() *AllAnimeFetcher {
-	return &AllAnimeFetcher{}
+	return &AllAnimeFetcher{translationType: subType}
+}
+
+// GetAllAnimeDubFetcher returns the fetcher of the dubbed episodes
+func GetAllAnimeDubFetcher() *AllAnimeFetcher {
+	return &AllAnimeFetcher{translationType: dubType}
+}
+
+// the cache keys of the fetcher, the sub and dub episodes differ
+func (a *AllAnimeFetcher) cacheKey(key string) string {
+	return "allanime." + a.translationType + ":" + key
 }
 
 func (a *AllAnimeFetcher) Search(q string) []types.AniResult {
+	results, _ := cache.Fetch(cache.Search, a.cacheKey(q), func() ([]types.AniResult, error) {
+		results := a.search(q)
+		if len(results) == 0 {
+			return results, cache.ErrNotCached
+		}
+		return results, nil
+	})
+	return results
+}
+
+func (a *AllAnimeFetcher) search(q string) []types.AniResult {
 	vars := AllAnimeSearchVariables{
 		Limit:           40,
 		Page:            1,
 		Search:          AllAnimeSearch{Query: q},
-		TranslationType: subType,
+		TranslationType: a.translationType,
 	}
 	query := `query($search: SearchInput, $limit: Int, $page: Int, $translationType: VaildTranslationTypeEnumType, $countryOrigin: VaildCountryOriginEnumType) {
 		shows(search: $search, limit: $limit, page: $page, translationType: $translationType, countryOrigin: $countryOrigin) {
@@ -78,6 +107,22 @@ func (a *AllAnimeFetcher) Search(q string) []types.AniResult {
 }
 
 func (a *AllAnimeFetcher) GetAnimeResult(id string) *types.AniResult {
+	r, _ := cache.Fetch(cache.Anime, a.cacheKey(id), func() (*types.AniResult, error) {
+		r := a.getAnimeResult(id)
+		if r == nil || r.Id == "" {
+			return r, cache.ErrNotCached
+		}
+		return r, nil
+	})
+	return r
+}
+
+// ForgetAnimeResult drops the cached anime of the id
+func (a *AllAnimeFetcher) ForgetAnimeResult(id string) {
+	cache.Default().Delete(cache.Anime, a.cacheKey(id))
+}
+
+func (a *AllAnimeFetcher) getAnimeResult(id string) *types.AniResult {
 	vars := AllAnimeGetByIdVariables{
 		Id: id,
 	}
@@ -106,7 +151,7 @@ func (a *AllAnimeFetcher) GetAnimeResult(id string) *types.AniResult {
 
 	show := decodedResponse.Data.Show
 	// TODO: recieve the translationType through vars
-	availableEpisodes, found := show.AvailableEpisodes[subType]
+	availableEpisodes, found := show.AvailableEpisodes[a.translationType]
 	if !found {
 		availableEpisodes, _ = strconv.Atoi(show.EpisodeCount)
 
@@ -131,7 +176,7 @@ func makeGraphqlRequest(query string, variables interface{}) (queryResult []byte
 		return nil, fmt.Errorf("error marshaling request body: %v", err)
 	}
 
-	res, err := http.Post(allanimeApi+"/api", "application/json", bytes.NewBuffer(reqBodyJSON))
+	res, err := scraper.Post(allanimeApi+"/api", "application/json", bytes.NewBuffer(reqBodyJSON))
 	if err != nil {
 		return nil, fmt.Errorf("error while searching %v", err)
 	}
@@ -156,11 +201,12 @@ func extractVideoLinks(response []byte) ([]types.AniVideo, error) {
 		if source.SourceName == "S-mp4" {
 			videoId := strings.Split(source.Downloads.DownloadUrl, "id=")[1]
 			downloadUrl := fmt.Sprintf("https://allanime.day/apivtwo/clock.json?id=%s", videoId)
-			resp, err := http.Get(downloadUrl)
+			resp, err := scraper.Get(downloadUrl)
 			if err != nil {
 				return nil, err
 			}
 			b, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
 			if err != nil {
 				return nil, err
 			}
@@ -184,7 +230,11 @@ func extractVideoLinks(response []byte) ([]types.AniVideo, error) {
 				// 	height = link.ResolutionStr
 				// }
 
-				videos = append(videos, types.AniVideo{Src: link.Src, Res: height})
+				videos = append(videos, types.AniVideo{
+					Src:     link.Src,
+					Res:     height,
+					Headers: map[string]string{"Referer": allanimeReferer},
+				})
 			}
 		}
 		// //////////////////// S-mp4 source ///////////////////////
@@ -203,6 +253,20 @@ func getHightFromFfprobe(data *ffprobe.ProbeData) int {
 }
 
 func (a *AllAnimeFetcher) lazyLoadEpisodeVideos(r types.AniResult, episodeNum int) ([]types.AniVideo, error) {
+	videos, err := cache.Fetch(cache.Videos, a.cacheKey(fmt.Sprintf("%s/%d", r.Id, episodeNum)), func() ([]types.AniVideo, error) {
+		videos, err := a.loadEpisodeVideos(r, episodeNum)
+		if err == nil && len(videos) == 0 {
+			return videos, cache.ErrNotCached
+		}
+		return videos, err
+	})
+	if errors.Is(err, cache.ErrNotCached) {
+		return videos, nil
+	}
+	return videos, err
+}
+
+func (a *AllAnimeFetcher) loadEpisodeVideos(r types.AniResult, episodeNum int) ([]types.AniVideo, error) {
 	episodeEmbedGql := `query Episode($showId: String!, $episodeString: String!, $translationType: VaildTranslationTypeEnumType!) {
     episode(showId: $showId, episodeString: $episodeString, translationType: $translationType) {
       episodeString
@@ -213,7 +277,7 @@ func (a *AllAnimeFetcher) lazyLoadEpisodeVideos(r types.AniResult, episodeNum in
 	variables := map[string]interface{}{
 		"showId":          r.Id,
 		"episodeString":   fmt.Sprintf("%v", episodeNum),
-		"translationType": subType,
+		"translationType": a.translationType,
 	}
 	response, err := makeGraphqlRequest(episodeEmbedGql, variables)
 
@@ -5,32 +5,31 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/ani/ani-ar/cache"
+	"github.com/ani/ani-ar/scraper"
 	"github.com/ani/ani-ar/types"
-	cache "github.com/patrickmn/go-cache"
 )
 
-type Anime3rb struct {
-	C *cache.Cache
-}
+// the values of the fetcher are cached in the default cache, see cache.Fetch
+type Anime3rb struct{}
 
 func GetAnime3rbFetcher() *Anime3rb {
-	return &Anime3rb{
-		C: cache.New(5*time.Minute, 10*time.Minute),
-	}
+	return &Anime3rb{}
 }
 
+// the cache keys of the fetcher
+const cachePrefix = "anime3rb:"
+
 const baseUrl = "https://anime3rb.com"
 
 // func (a *Anime3rb) getToken() string {
-// 	res, err := http.Get(baseUrl)
+// 	res, err := scraper.Get(baseUrl)
 // 	if err != nil {
 // 		fmt.Println(err.Error())
 // 		return ""
@@ -55,11 +54,22 @@ const baseUrl = "https://anime3rb.com"
 // }
 
 func (a *Anime3rb) GetAnimeResult(title string) *types.AniResult {
-	cacheKey := "anime:" + title
-	if cachedAnime, found := a.C.Get(cacheKey); found {
-		return cachedAnime.(*types.AniResult)
-	}
+	r, _ := cache.Fetch(cache.Anime, cachePrefix+title, func() (*types.AniResult, error) {
+		r := a.fetchAnimeResult(title)
+		if r == nil {
+			return nil, cache.ErrNotCached
+		}
+		return r, nil
+	})
+	return r
+}
 
+// ForgetAnimeResult drops the cached anime of the title
+func (a *Anime3rb) ForgetAnimeResult(title string) {
+	cache.Default().Delete(cache.Anime, cachePrefix+title)
+}
+
+func (a *Anime3rb) fetchAnimeResult(title string) *types.AniResult {
 	displayNameRe := regexp.MustCompile(
 		`<h1\s+class="text-2xl font-bold uppercase inline">(.*)<\/h1>`,
 	)
@@ -67,12 +77,15 @@ func (a *Anime3rb) GetAnimeResult(title string) *types.AniResult {
 	animeCoverRe := regexp.MustCompile(`<meta\s+property="og:image"\s+content="([^"]+)"`)
 
 	animePageUrl := fmt.Sprintf("%s/titles/%s", baseUrl, title)
-	res, err := http.Get(animePageUrl)
-	if res.StatusCode != 200 || err != nil {
+	res, err := scraper.Get(animePageUrl)
+	if err != nil {
 		return nil
 	}
-	log.Println("found anime page : status 200 OK")
 	defer res.Body.Close()
+	if res.StatusCode != 200 {
+		return nil
+	}
+	log.Println("found anime page : status 200 OK")
 	htmlBytes, err := io.ReadAll(res.Body)
 	if err != nil {
 		return nil
@@ -102,28 +115,23 @@ func (a *Anime3rb) GetAnimeResult(title string) *types.AniResult {
 	episodesCount := episodeNumberDoc.Find("p:nth-child(2)").Text()
 	epCoutnInt, _ := strconv.Atoi(episodesCount)
 
-	r := &types.AniResult{
+	return &types.AniResult{
 		Id:           title,
 		DisplayName:  displayName,
 		Episodes:     epCoutnInt,
 		DisplayCover: cover,
 	}
-	cachedItemsCount := a.C.ItemCount()
-	if cachedItemsCount > 100 {
-		a.C.Flush()
-	}
-	a.C.Set(cacheKey, r, cache.NoExpiration)
-	return r
 }
 
 func (a *Anime3rb) Search(key string) []types.AniResult {
-	cacheKey := "search:" + key
-	if results, found := a.C.Get(cacheKey); found {
-		return results.([]types.AniResult)
-	}
-	searchResults := a.searchPages(key, []types.AniResult{}, 1)
-	a.C.Set(cacheKey, searchResults, time.Hour)
-	return searchResults
+	results, _ := cache.Fetch(cache.Search, cachePrefix+key, func() ([]types.AniResult, error) {
+		results := a.searchPages(key, []types.AniResult{}, 1)
+		if len(results) == 0 {
+			return results, cache.ErrNotCached
+		}
+		return results, nil
+	})
+	return results
 }
 
 func (a *Anime3rb) searchPages(
@@ -135,7 +143,7 @@ func (a *Anime3rb) searchPages(
 		return results
 	}
 	searchUrl := fmt.Sprintf("%s/search?q=%s&page=%v", baseUrl, url.QueryEscape(key), page)
-	res, err := http.Get(searchUrl)
+	res, err := scraper.Get(searchUrl)
 	if err != nil {
 		fmt.Println(err.Error())
 		return nil
@@ -227,43 +235,47 @@ func getVideosUrl(html string) []types.AniVideo {
 
 func (a *Anime3rb) getMediasForEpisode(url string) func() []types.AniVideo {
 	return func() []types.AniVideo {
-		cacheKey := "episode.medias." + url
-		medias, found := a.C.Get(cacheKey)
-		if found {
-			return *medias.(*[]types.AniVideo)
-		}
-		res, err := http.Get(url)
-		if err != nil {
-			fmt.Println(err.Error())
-			return nil
-		}
-		resBytes, err := io.ReadAll(res.Body)
+		medias, _ := cache.Fetch(cache.Videos, cachePrefix+url, func() ([]types.AniVideo, error) {
+			medias := a.fetchMediasForEpisode(url)
+			if len(medias) == 0 {
+				return medias, cache.ErrNotCached
+			}
+			return medias, nil
+		})
+		return medias
+	}
+}
+
+func (a *Anime3rb) fetchMediasForEpisode(url string) []types.AniVideo {
+	res, err := scraper.Get(url)
+	if err != nil {
+		fmt.Println(err.Error())
+		return nil
+	}
+	resBytes, err := io.ReadAll(res.Body)
+	defer res.Body.Close()
+	if err != nil {
+		fmt.Println(err.Error())
+		return nil
+	}
+	html := string(resBytes)
+	re := regexp.MustCompile(`videoSource:\s*'([^']+)'`)
+	// Find the match
+	match := re.FindStringSubmatch(html)
+	if len(match) > 1 {
+		// Extracted URL
+		url := match[1]
+		// Replace escaped characters
+		unescapedURL := strings.ReplaceAll(url, `\/`, `/`)
+		unescapedURL = strings.ReplaceAll(unescapedURL, `\u0026`, `&`)
+
+		res, _ := scraper.Get(unescapedURL)
+		b, _ := io.ReadAll(res.Body)
 		defer res.Body.Close()
-		if err != nil {
-			fmt.Println(err.Error())
-			return nil
-		}
-		html := string(resBytes)
-		re := regexp.MustCompile(`videoSource:\s*'([^']+)'`)
-		// Find the match
-		match := re.FindStringSubmatch(html)
-		if len(match) > 1 {
-			// Extracted URL
-			url := match[1]
-			// Replace escaped characters
-			unescapedURL := strings.ReplaceAll(url, `\/`, `/`)
-			unescapedURL = strings.ReplaceAll(unescapedURL, `\u0026`, `&`)
-
-			res, _ := http.Get(unescapedURL)
-			b, _ := io.ReadAll(res.Body)
-			defer res.Body.Close()
-			fetchedMedias := getVideosUrl(string(b))
-			a.C.Set(cacheKey, &fetchedMedias, time.Hour*24*4) // 4 days
-			return fetchedMedias
-		} else {
-			fmt.Println("No URL found")
-			return nil
-		}
+		return getVideosUrl(string(b))
+	} else {
+		fmt.Println("No URL found")
+		return nil
 	}
 }
 
@@ -3,9 +3,11 @@ package fetcher
 // TODO: use plugin system for fetches and make them open source to allow people make their own fetchers
 import (
 	"errors"
+	"fmt"
 
 	"github.com/ani/ani-ar/fetcher/allanime"
 	"github.com/ani/ani-ar/fetcher/anime3rb"
+	"github.com/ani/ani-ar/profile"
 	"github.com/ani/ani-ar/types"
 )
 
@@ -15,6 +17,12 @@ type Fetcher interface {
 	GetEpisodes(types.AniResult) []types.AniEpisode
 }
 
+// Forgetter is a fetcher whose cached anime can be dropped, the next
+// GetAnimeResult of the id asks the source again
+type Forgetter interface {
+	ForgetAnimeResult(id string)
+}
+
 var fetchers = make(map[int]Fetcher)
 
 const (
@@ -22,6 +30,12 @@ const (
 	AllAnimeFetcher
 )
 
+// the names fetchers are recorded with, eg. in the watchlist
+var fetcherNames = map[int]string{
+	Anime3rbFetcher: "anime3rb",
+	AllAnimeFetcher: "allanime",
+}
+
 func init() {
 	registerFetcher(Anime3rbFetcher, anime3rb.GetAnime3rbFetcher())
 	registerFetcher(AllAnimeFetcher, allanime.GetAllAnimeFetcher())
@@ -43,8 +57,74 @@ func GetFetcher(name int) (Fetcher, error) {
 	return nil, errors.New("fetcher name is unknown")
 }
 
-// TODO: allow user to select the fetcher through args or something
+// the fetchers of the dubbed episodes, by the fetcher of the subbed ones
+var dubbedFetchers = map[int]Fetcher{
+	AllAnimeFetcher: allanime.GetAllAnimeDubFetcher(),
+}
+
+// GetDefaultFetcher returns the fetcher picked in the settings of the active profile
 func GetDefaultFetcher() Fetcher {
-	f, _ := GetFetcher(Anime3rbFetcher)
+	return GetProfileFetcher(profile.Active())
+}
+
+// GetProfileFetcher returns the fetcher of the source and translation picked
+// in the settings of the profile, anime3rb when the source isn't set
+func GetProfileFetcher(name string) Fetcher {
+	settings := profile.GetSettings(name)
+	id := Anime3rbFetcher
+	for i, n := range fetcherNames {
+		if n == settings.Source {
+			id = i
+		}
+	}
+	if dubbed, ok := dubbedFetchers[id]; ok && settings.Translation == profile.TranslationDub {
+		return dubbed
+	}
+	f, _ := GetFetcher(id)
 	return f
 }
+
+// GetFetcherName returns the name of a registered fetcher, empty when it's unknown
+func GetFetcherName(f Fetcher) string {
+	for name, registered := range fetchers {
+		if registered == f || dubbedFetchers[name] == f {
+			return fetcherNames[name]
+		}
+	}
+	return ""
+}
+
+// GetFetcherKey identifies the fetcher in the cache keys, the dubbed fetchers
+// share the name of the subbed ones so they get a .dub suffix (eg. allanime.dub)
+func GetFetcherKey(f Fetcher) string {
+	for id, registered := range fetchers {
+		if registered == f {
+			return fetcherNames[id]
+		}
+		if dubbedFetchers[id] == f {
+			return fetcherNames[id] + ".dub"
+		}
+	}
+	// eg. the fetchers of the tests
+	return fmt.Sprintf("%p", f)
+}
+
+// GetFetcherByName returns the registered fetcher with the name (eg. allanime)
+func GetFetcherByName(name string) (Fetcher, error) {
+	for id, n := range fetcherNames {
+		if n == name {
+			return GetFetcher(id)
+		}
+	}
+	return nil, errors.New("fetcher name is unknown")
+}
+
+// ValidateSettings checks the settings of a profile with the source among the fetchers
+func ValidateSettings(s profile.Settings) error {
+	if s.Source != "" {
+		if _, err := GetFetcherByName(s.Source); err != nil {
+			return err
+		}
+	}
+	return s.Validate()
+}
@@ -0,0 +1,38 @@
+// Package filelock takes the exclusive locks of files shared by the ani-ar
+// processes, eg. a server and the jelly loop working on the same config
+// folder. The locks are released when their file is closed or the process exits
+package filelock
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+var ErrLocked = errors.New("the file is locked by another process")
+
+// Lock waits for the lock of the file at path, it's created when it's missing
+func Lock(path string) (*os.File, error) {
+	return open(path, true)
+}
+
+// TryLock takes the lock of the file at path or fails with ErrLocked when
+// another process has it
+func TryLock(path string) (*os.File, error) {
+	return open(path, false)
+}
+
+func open(path string, wait bool) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := lock(f, wait); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
@@ -0,0 +1,40 @@
+package filelock
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ani-ar", "file.lock")
+	f, err := TryLock(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := TryLock(path); err != ErrLocked {
+		t.Fatalf("got %v, want ErrLocked", err)
+	}
+
+	locked := make(chan struct{})
+	go func() {
+		other, err := Lock(path)
+		if err != nil {
+			t.Error(err)
+		} else {
+			other.Close()
+		}
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		t.Fatal("Lock should wait for the lock to be released")
+	case <-time.After(50 * time.Millisecond):
+	}
+	f.Close()
+	select {
+	case <-locked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Lock should get the released lock")
+	}
+}
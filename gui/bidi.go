@@ -0,0 +1,237 @@
+package gui
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/bidi"
+)
+
+// the titles and episode names of anime3rb are arabic and they share the rows
+// with latin text, numbers and markers, a terminal doing bidi would move the
+// numbers and markers around them and one that doesn't shows them backwards.
+// ANI_AR_BIDI picks how the right to left text is written:
+//
+//	isolate (default) wraps it in unicode isolates, for terminals doing bidi (vte, konsole, iterm2, mlterm)
+//	reorder writes it in visual order, for terminals that don't (kitty, alacritty, wezterm, windows terminal)
+//	none writes it as it is
+const (
+	bidiIsolate = iota
+	bidiReorder
+	bidiNone
+)
+
+const (
+	firstStrongIsolate    = "\u2068"
+	rightToLeftIsolate    = "\u2067"
+	popDirectionalIsolate = "\u2069"
+)
+
+var bidiMode = detectBidiMode()
+
+func detectBidiMode() int {
+	switch strings.ToLower(os.Getenv("ANI_AR_BIDI")) {
+	case "reorder":
+		return bidiReorder
+	case "none":
+		return bidiNone
+	}
+	return bidiIsolate
+}
+
+func isRTL(r rune) bool {
+	props, _ := bidi.LookupRune(r)
+	return props.Class() == bidi.R || props.Class() == bidi.AL
+}
+
+func hasRTL(s string) bool {
+	return strings.IndexFunc(s, isRTL) != -1
+}
+
+// bidiText prepares a run of text, eg. a title, to be written inside a row,
+// rtl is the direction of the row: false for the english ui and true for the
+// arabic one
+func bidiText(s string, rtl bool) string {
+	if !hasRTL(s) && !rtl {
+		return s
+	}
+	switch bidiMode {
+	case bidiIsolate:
+		if rtl {
+			return rightToLeftIsolate + s + popDirectionalIsolate
+		}
+		return firstStrongIsolate + s + popDirectionalIsolate
+	case bidiReorder:
+		return visualOrder(s, rtl)
+	}
+	return s
+}
+
+// highlightedBidiText is bidiText highlighting the runes at the indexes of s
+func highlightedBidiText(s string, rtl bool, matches []int) string {
+	if bidiMode == bidiReorder && (hasRTL(s) || rtl) {
+		return highlightedVisualOrder(s, rtl, matches)
+	}
+	return bidiText(highlightMatches(s, matches), rtl)
+}
+
+// a run of runes with the same direction, start is the index of its first rune
+type bidiRun struct {
+	runes []rune
+	start int
+	rtl   bool
+}
+
+// visualOrder returns s in the order the runes are shown on a terminal that
+// doesn't do bidi: the rtl runs are reversed keeping the combining marks after
+// their letter and the numbers in them left to right, in an rtl row the order
+// of the runs is reversed as well. It's a simplified version of the unicode
+// bidi algorithm, good enough for titles
+func visualOrder(s string, rtl bool) string {
+	return highlightedVisualOrder(s, rtl, nil)
+}
+
+// highlightedVisualOrder is visualOrder highlighting the runes at the indexes
+// of s, the highlights can't be added once the runes are moved around
+func highlightedVisualOrder(s string, rtl bool, matches []int) string {
+	matched := make(map[int]bool, len(matches))
+	for _, i := range matches {
+		matched[i] = true
+	}
+	runes := []rune(s)
+	runs := splitBidiRuns(runes, rtl)
+	if rtl {
+		slices.Reverse(runs)
+	}
+	var b strings.Builder
+	for _, run := range runs {
+		for _, i := range visualIndexes(run) {
+			r := runes[i]
+			if mirrored, ok := mirroredRunes[r]; ok && run.rtl {
+				r = mirrored
+			}
+			if matched[i] {
+				b.WriteString(matchedStart + string(r) + matchedEnd)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
+
+// splitBidiRuns splits the text in runs of rtl and ltr runes, the numbers
+// take the direction of the letters before them, the neutral runes (spaces,
+// punctuation) between two runs of the same direction join them and the other
+// ones take the direction of the row
+func splitBidiRuns(runes []rune, rtl bool) []bidiRun {
+	direction := make([]int, len(runes))
+	const (
+		neutral = iota
+		ltr
+		rtlDir
+	)
+	lastStrong := neutral
+	for i, r := range runes {
+		props, _ := bidi.LookupRune(r)
+		switch props.Class() {
+		case bidi.R, bidi.AL:
+			direction[i] = rtlDir
+			lastStrong = rtlDir
+		case bidi.L:
+			direction[i] = ltr
+			lastStrong = ltr
+		case bidi.EN, bidi.AN:
+			direction[i] = lastStrong
+		case bidi.NSM:
+			// combining marks follow their letter
+			if i > 0 {
+				direction[i] = direction[i-1]
+			}
+		}
+	}
+	rowDir := ltr
+	if rtl {
+		rowDir = rtlDir
+	}
+	for i := 0; i < len(runes); {
+		if direction[i] != neutral {
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && direction[j] == neutral {
+			j++
+		}
+		before, after := rowDir, rowDir
+		if i > 0 {
+			before = direction[i-1]
+		}
+		if j < len(runes) {
+			after = direction[j]
+		}
+		dir := rowDir
+		if before == after {
+			dir = before
+		}
+		for k := i; k < j; k++ {
+			direction[k] = dir
+		}
+		i = j
+	}
+
+	var runs []bidiRun
+	for i, r := range runes {
+		isRunRTL := direction[i] == rtlDir
+		if len(runs) == 0 || runs[len(runs)-1].rtl != isRunRTL {
+			runs = append(runs, bidiRun{start: i, rtl: isRunRTL})
+		}
+		runs[len(runs)-1].runes = append(runs[len(runs)-1].runes, r)
+	}
+	return runs
+}
+
+var mirroredRunes = map[rune]rune{
+	'(': ')', ')': '(',
+	'[': ']', ']': '[',
+	'{': '}', '}': '{',
+	'<': '>', '>': '<',
+	'«': '»', '»': '«',
+}
+
+// visualIndexes returns the indexes of the runes of the run in the order they
+// are shown, an rtl run is reversed by clusters that keep their inner order:
+// a letter with its combining marks, or a number
+func visualIndexes(run bidiRun) []int {
+	indexes := make([]int, 0, len(run.runes))
+	if !run.rtl {
+		for i := range run.runes {
+			indexes = append(indexes, run.start+i)
+		}
+		return indexes
+	}
+	var clusters [][2]int
+	runes := run.runes
+	for i := 0; i < len(runes); {
+		j := i + 1
+		if unicode.IsDigit(runes[i]) {
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.' || runes[j] == ',') {
+				j++
+			}
+		} else {
+			for j < len(runes) && unicode.Is(unicode.Mn, runes[j]) {
+				j++
+			}
+		}
+		clusters = append(clusters, [2]int{i, j})
+		i = j
+	}
+	for c := len(clusters) - 1; c >= 0; c-- {
+		for i := clusters[c][0]; i < clusters[c][1]; i++ {
+			indexes = append(indexes, run.start+i)
+		}
+	}
+	return indexes
+}
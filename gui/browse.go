@@ -0,0 +1,207 @@
+package gui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ani/ani-ar/api"
+	"github.com/ani/ani-ar/watchlist"
+)
+
+// lines printed by the browse view before the tab list
+const browseHeaderLines = 2
+
+type browseTab struct {
+	title string
+	fetch func() ([]interface{}, error)
+	// local lists are loaded again every time they are shown
+	reload bool
+}
+
+// browseModel lists the seasonal and top anime from MyAnimeList and the
+// watchlist in tabs, every remote tab is loaded the first time it's shown
+type browseModel struct {
+	tabs         []browseTab
+	lists        []*ChoicesModel
+	loaded       []bool
+	active       int
+	watchlistTab int
+}
+
+func (b *browseModel) isWatchlistShown() bool {
+	return b.active == b.watchlistTab
+}
+
+func newBrowseModel(keys *keyMap) *browseModel {
+	jikan := api.GetJikanApi()
+	tabs := []browseTab{
+		{title: translate("This season"), fetch: jikanList(jikan.GetSeasonNow)},
+		{title: translate("Upcoming"), fetch: jikanList(jikan.GetSeasonUpcoming)},
+		{title: translate("Top airing"), fetch: jikanList(func() []*api.JikanAnimeInfo { return jikan.GetTopAnime("airing") })},
+		{title: translate("Most popular"), fetch: jikanList(func() []*api.JikanAnimeInfo { return jikan.GetTopAnime("bypopularity") })},
+		{title: translate("Watchlist"), fetch: watchlistEntries, reload: true},
+	}
+	b := &browseModel{
+		tabs:   tabs,
+		lists:  make([]*ChoicesModel, len(tabs)),
+		loaded: make([]bool, len(tabs)),
+	}
+	for i := range tabs {
+		b.lists[i] = initialChoicesModelForJikanAnime(keys)
+	}
+	// the watchlist is the last tab
+	b.watchlistTab = len(tabs) - 1
+	b.lists[b.watchlistTab] = initialChoicesModelForWatchlist(keys)
+	return b
+}
+
+func jikanList(fetch func() []*api.JikanAnimeInfo) func() ([]interface{}, error) {
+	return func() ([]interface{}, error) {
+		list := fetch()
+		results := make([]interface{}, len(list))
+		for i := range list {
+			results[i] = list[i]
+		}
+		return results, nil
+	}
+}
+
+func watchlistEntries() ([]interface{}, error) {
+	store, err := watchlist.GetStore()
+	if err != nil {
+		return nil, err
+	}
+	entries := store.List("", false)
+	results := make([]interface{}, len(entries))
+	for i := range entries {
+		results[i] = entries[i]
+	}
+	return results, nil
+}
+
+func initialChoicesModelForWatchlist(keys *keyMap) *ChoicesModel {
+	return &ChoicesModel{
+		keys:      keys,
+		spinner:   getSpinnerForChoices(),
+		textInput: getFilterTextInput(),
+		viewport:  getChoicesViewport(120),
+		choiceTextFunc: func(i interface{}) []string {
+			entry := i.(watchlist.Entry)
+			return []string{tr("[%s]", translate(entry.Status)), entry.Title}
+		},
+		choiceFormatFunc: func(i interface{}, matches [][]int) string {
+			entry := i.(watchlist.Entry)
+			formatted := joinRow(" ",
+				highlightMatches(tr("[%s]", translate(entry.Status)), matches[0]),
+				highlightedBidiText(entry.Title, isRTLUI(), matches[1]),
+			)
+			if entry.Favorite {
+				formatted = joinRow(" ", formatted, markersStyle.Render("★"))
+			}
+			return formatted
+		},
+	}
+}
+
+func initialChoicesModelForJikanAnime(keys *keyMap) *ChoicesModel {
+	return &ChoicesModel{
+		keys:      keys,
+		spinner:   getSpinnerForChoices(),
+		textInput: getFilterTextInput(),
+		viewport:  getChoicesViewport(120),
+		choiceTextFunc: func(i interface{}) []string {
+			return []string{i.(*api.JikanAnimeInfo).Title}
+		},
+		choiceFormatFunc: func(i interface{}, matches [][]int) string {
+			info := i.(*api.JikanAnimeInfo)
+			formatted := highlightedBidiText(info.Title, isRTLUI(), matches[0])
+			if info.Episodes > 0 {
+				formatted = joinRow(" - ", formatted, tr("%v episodes", info.Episodes))
+			}
+			if info.Score > 0 {
+				formatted = joinRow(" ", formatted, markersStyle.Render(fmt.Sprintf("★ %.2f", info.Score)))
+			}
+			return formatted
+		},
+	}
+}
+
+func (b *browseModel) Init() tea.Cmd {
+	cmds := make([]tea.Cmd, len(b.lists))
+	for i, list := range b.lists {
+		cmds[i] = list.Init()
+	}
+	return tea.Batch(cmds...)
+}
+
+func (b *browseModel) list() *ChoicesModel {
+	return b.lists[b.active]
+}
+
+// show switches to the tab and returns the command loading it if needed
+func (b *browseModel) show(tab int) tea.Cmd {
+	b.active = (tab + len(b.tabs)) % len(b.tabs)
+	if b.loaded[b.active] && !b.tabs[b.active].reload {
+		return nil
+	}
+	b.loaded[b.active] = true
+
+	i := b.active
+	fetch := b.tabs[i].fetch
+	b.list().startLoading(b.tabs[i].title)
+	return func() tea.Msg {
+		results, err := fetch()
+		return newBrowseLoadedEvent(i, results, err)
+	}
+}
+
+func (b *browseModel) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case BrowseLoadedEvent:
+		// the tab is filled even when another one is shown now
+		b.lists[msg.tab].Update(newChoicesShownEvent(msg.results))
+		if len(msg.results) == 0 {
+			// try again the next time the tab is shown
+			b.loaded[msg.tab] = false
+		}
+		return nil
+	case spinner.TickMsg:
+		cmds := make([]tea.Cmd, len(b.lists))
+		for i, list := range b.lists {
+			_, cmds[i] = list.Update(msg)
+		}
+		return tea.Batch(cmds...)
+	case tea.MouseMsg:
+		msg.Y -= browseHeaderLines
+		_, cmd := b.list().Update(msg)
+		return cmd
+	case tea.WindowSizeMsg:
+		for _, list := range b.lists {
+			list.Update(msg)
+		}
+		return nil
+	}
+	_, cmd := b.list().Update(msg)
+	return cmd
+}
+
+// choiceAt returns the index of the listed anime at the terminal cell x, y
+func (b *browseModel) choiceAt(x, y int) (int, bool) {
+	return b.list().choiceAt(x, y-browseHeaderLines)
+}
+
+func (b *browseModel) View() string {
+	tabs := make([]string, len(b.tabs))
+	for i, tab := range b.tabs {
+		if i == b.active {
+			tabs[i] = activeTabStyle.Render(bidiText(tab.title, isRTLUI()))
+		} else {
+			tabs[i] = inactiveTabStyle.Render(bidiText(tab.title, isRTLUI()))
+		}
+	}
+	msg := joinRow("  │  ", tabs...) + "\n\n"
+	msg += b.list().View()
+	return msg
+}
@@ -2,15 +2,21 @@ package gui
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/ani/ani-ar/cast"
+	"github.com/ani/ani-ar/history"
+	"github.com/ani/ani-ar/profile"
 	"github.com/ani/ani-ar/types"
 )
 
@@ -21,59 +27,165 @@ type ChoicesModel struct {
 	loading      bool
 	resultsShown bool
 
-	searchKey        string
-	choiceFormatFunc func(interface{}) string
-
-	textInput                textinput.Model
-	viewport                 viewport.Model
-	firstChoiceVisibleCursor int
+	searchKey string
+	// the parts of the choice the filter matches (eg. the title), as they
+	// are before bidiText changes their order
+	choiceTextFunc func(interface{}) []string
+	// formats the choice with the matched runes of every text part highlighted
+	choiceFormatFunc func(choice interface{}, matches [][]int) string
+	// optional, the choices it returns true for are not listed
+	hiddenFunc func(interface{}) bool
+
+	// optional, the number typed to jump to a choice, the listed position by default
+	numberFunc func(interface{}) int
+
+	keys      *keyMap
+	textInput textinput.Model
+	// keys go to the filter input only while filtering, so the stages can
+	// use letters as keybindings
+	filtering bool
+	viewport  viewport.Model
+
+	// digits typed to jump to a choice
+	jump   string
+	jumpAt time.Time
 }
 
 const vpHight = 20
 
+// lines printed by View before the list, used to find the clicked choice
+const choicesHeaderLines = 4
+
+// the digits typed within this delay make up one number
+const jumpTimeout = time.Second
+
 func getSpinnerForChoices() spinner.Model {
 	s := spinner.New()
 	s.Spinner = spinner.Line
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	s.Style = spinnerStyle
 	return s
 }
 
+func getChoicesViewport(width int) viewport.Model {
+	vp := viewport.New(width, vpHight)
+	// the cursor drives the scrolling, the viewport keys would scroll away from it
+	vp.KeyMap = viewport.KeyMap{}
+	vp.MouseWheelEnabled = false
+	return vp
+}
+
 func getFilterTextInput() textinput.Model {
 	ti := textinput.New()
-	ti.Placeholder = "Filter Search result"
-	ti.Focus()
+	ti.Placeholder = translate("press / to filter")
 	ti.CharLimit = 156
 	ti.Width = 20
 	return ti
 }
 
-func initialChoicesModelForAnimeTitles() *ChoicesModel {
-	vp := viewport.New(120, vpHight)
+func initialChoicesModelForAnimeTitles(keys *keyMap) *ChoicesModel {
+	vp := getChoicesViewport(120)
 	vp.SetContent(`loading...`)
 	return &ChoicesModel{
+		keys:      keys,
 		spinner:   getSpinnerForChoices(),
 		textInput: getFilterTextInput(),
 		viewport:  vp,
-		choiceFormatFunc: func(i interface{}) string {
+		choiceTextFunc: func(i interface{}) []string {
+			return []string{i.(types.AniResult).DisplayName}
+		},
+		choiceFormatFunc: func(i interface{}, matches [][]int) string {
 			anime := i.(types.AniResult)
-			suf := "episode"
+			episodes := tr("%v episode", anime.Episodes)
 			if anime.Episodes > 1 {
-				suf = "episodes"
+				episodes = tr("%v episodes", anime.Episodes)
 			}
-			return fmt.Sprintf("%s - %v %s", anime.DisplayName, anime.Episodes, suf)
+			return joinRow(" - ", highlightedBidiText(anime.DisplayName, isRTLUI(), matches[0]), episodes)
 		},
 	}
 }
 
-func initialChoicesModelForAnimeEpisode() *ChoicesModel {
-	vp := viewport.New(30, vpHight)
+// isSelected reports whether the episode number is selected for a download
+func initialChoicesModelForAnimeEpisode(keys *keyMap, isSelected func(int) bool) *ChoicesModel {
+	vp := getChoicesViewport(30)
 	return &ChoicesModel{
+		keys:      keys,
 		spinner:   getSpinnerForChoices(),
 		textInput: getFilterTextInput(),
 		viewport:  vp,
-		choiceFormatFunc: func(i interface{}) string {
+		choiceTextFunc: func(i interface{}) []string {
 			episode := i.(types.AniEpisode)
-			return fmt.Sprintf("episode #%v", episode.Number)
+			return []string{tr("episode #%v", episode.Number), episode.Title}
+		},
+		choiceFormatFunc: func(i interface{}, matches [][]int) string {
+			episode := i.(types.AniEpisode)
+			formatted := highlightMatches(tr("episode #%v", episode.Number), matches[0])
+			if isSelected(episode.Number) {
+				formatted = selectedStyle.Render(joinRow(" ", "●", formatted))
+			}
+			if episode.Title != "" {
+				formatted = joinRow(" - ", formatted, highlightedBidiText(episode.Title, isRTLUI(), matches[1]))
+			}
+			var flags []string
+			if episode.Filler {
+				flags = append(flags, fillerStyle.Render(tr("[filler]")))
+			}
+			if episode.Recap {
+				flags = append(flags, recapStyle.Render(tr("[recap]")))
+			}
+
+			h := history.GetStore()
+			markers := ""
+			if h.IsWatched(episode.Anime.Id, episode.Number) {
+				markers += " ✓"
+			}
+			if h.IsDownloaded(episode.Anime.Id, episode.Number) {
+				markers += " ↓"
+			}
+			return joinRow(" ", append([]string{formatted}, flags...)...) + markersStyle.Render(markers)
+		},
+		numberFunc: func(i interface{}) int {
+			return i.(types.AniEpisode).Number
+		},
+	}
+}
+
+func isFillerEpisode(i interface{}) bool {
+	return i.(types.AniEpisode).Filler
+}
+
+func initialChoicesModelForCastDevices(keys *keyMap) *ChoicesModel {
+	vp := getChoicesViewport(60)
+	return &ChoicesModel{
+		keys:      keys,
+		spinner:   getSpinnerForChoices(),
+		textInput: getFilterTextInput(),
+		viewport:  vp,
+		choiceTextFunc: func(i interface{}) []string {
+			return []string{i.(*cast.Device).String()}
+		},
+		choiceFormatFunc: func(i interface{}, matches [][]int) string {
+			return highlightMatches(i.(*cast.Device).String(), matches[0])
+		},
+	}
+}
+
+func initialChoicesModelForProfiles(keys *keyMap) *ChoicesModel {
+	vp := getChoicesViewport(60)
+	return &ChoicesModel{
+		keys:      keys,
+		spinner:   getSpinnerForChoices(),
+		textInput: getFilterTextInput(),
+		viewport:  vp,
+		choiceTextFunc: func(i interface{}) []string {
+			return []string{i.(string)}
+		},
+		choiceFormatFunc: func(i interface{}, matches [][]int) string {
+			name := i.(string)
+			formatted := highlightedBidiText(name, isRTLUI(), matches[0])
+			if name == profile.Active() {
+				return joinRow(" ", formatted, markersStyle.Render(tr("(active)")))
+			}
+			return formatted
 		},
 	}
 }
@@ -82,17 +194,52 @@ func (m *ChoicesModel) getSelectedChoice() interface{} {
 	return m.getFilteredChoices(m.choices)[m.cursor]
 }
 
-func (m *ChoicesModel) getFilteredChoices(choices []interface{}) []interface{} {
-	var filteredChoices []interface{}
+// returns the choice under the cursor, false when the filtered list is empty
+func (m *ChoicesModel) getHighlightedChoice() (interface{}, bool) {
+	filtered := m.getFilteredChoices(m.choices)
+	if m.cursor < 0 || m.cursor >= len(filtered) {
+		return nil, false
+	}
+	return filtered[m.cursor], true
+}
+
+// a listed choice with the indexes of the runes of its text parts matching the filter
+type rankedChoice struct {
+	choice  interface{}
+	score   int
+	matches [][]int
+}
+
+// ranks the visible choices by how well they fuzzy match the filter, the
+// order is kept when there is no filter
+func (m *ChoicesModel) rankChoices(choices []interface{}) []rankedChoice {
+	var ranked []rankedChoice
+	filterKey := m.textInput.Value()
 	for _, r := range choices {
-		formatted := m.choiceFormatFunc(r)
-		filterKey := m.textInput.Value()
-		if filterKey != "" {
-			if !strings.Contains(strings.ToLower(formatted), strings.ToLower(filterKey)) {
-				continue
-			}
+		if m.hiddenFunc != nil && m.hiddenFunc(r) {
+			continue
+		}
+		// the logical text is matched, the formatted one has the bidi
+		// isolates and the rtl runes in visual order
+		parts := m.choiceTextFunc(r)
+		score, positions, ok := fuzzyMatch(filterKey, strings.Join(parts, " "))
+		if !ok {
+			continue
 		}
-		filteredChoices = append(filteredChoices, r)
+		ranked = append(ranked, rankedChoice{choice: r, score: score, matches: splitMatches(positions, parts)})
+	}
+	if filterKey != "" {
+		sort.SliceStable(ranked, func(i, j int) bool {
+			return ranked[i].score > ranked[j].score
+		})
+	}
+	return ranked
+}
+
+func (m *ChoicesModel) getFilteredChoices(choices []interface{}) []interface{} {
+	var filteredChoices []interface{}
+	for _, r := range m.rankChoices(choices) {
+		filteredChoices = append(filteredChoices, r.choice)
 	}
 	return filteredChoices
 }
@@ -103,49 +250,110 @@ func (m ChoicesModel) getViewportContentFromChoices(choices []interface{}, curso
 		cursor = m.cursor
 	}
 	// Display choices
-	filtered := m.getFilteredChoices(choices)
-	for i, r := range filtered {
+	for i, r := range m.rankChoices(choices) {
 		displayCursor := " "
 		if cursor == i {
 			displayCursor = ">"
 		}
-		formatted := m.choiceFormatFunc(r)
+		formatted := m.choiceFormatFunc(r.choice, r.matches)
 
-		content += fmt.Sprintf("%s %v- %s\n", displayCursor, i+1, formatted)
+		line := fmt.Sprintf("%s %v- %s", displayCursor, i+1, formatted)
+		if isRTLUI() {
+			if cursor == i {
+				displayCursor = "<"
+			}
+			line = fmt.Sprintf("%s -%v %s", formatted, i+1, displayCursor)
+		}
+		// long lines would wrap and break the scrolling
+		content += lipgloss.NewStyle().MaxWidth(m.viewport.Width).Render(line) + "\n"
 	}
 	if len(choices) == 0 {
-		content += "No matched results!!\n"
+		content += tr("No matched results!!") + "\n"
 	}
 	return content
 }
 
-func (m *ChoicesModel) fetchChoices(
-	searchfunc func() []interface{},
-	key string,
-) (tea.Model, tea.Cmd) {
-	m.searchKey = key
+// setHiddenFunc changes the hidden choices and moves the cursor back to the top
+func (m *ChoicesModel) setHiddenFunc(hidden func(interface{}) bool) {
+	m.hiddenFunc = hidden
+	m.cursor = 0
+	m.viewport.GotoTop()
+	m.refreshContent()
+}
 
-	// Show the spinner
-	m.loading = true
+// refreshContent renders the choices again, eg. after their state changed
+func (m *ChoicesModel) refreshContent() {
+	if m.resultsShown {
+		m.viewport.SetContent(m.getViewportContentFromChoices(m.choices, -1))
+	}
+}
 
-	// Return the model to show the spinner
-	newModel, _ := m.Update(newChoicesLoadingEvent())
-	m = newModel.(*ChoicesModel)
+// moveCursor moves the cursor to the listed choice i and scrolls to keep it visible
+func (m *ChoicesModel) moveCursor(i int) {
+	count := len(m.getFilteredChoices(m.choices))
+	m.cursor = max(min(i, count-1), 0)
+	if m.cursor < m.viewport.YOffset {
+		m.viewport.SetYOffset(m.cursor)
+	} else if m.cursor >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(m.cursor - m.viewport.Height + 1)
+	}
+	m.refreshContent()
+}
 
-	// Fetch data in a separate command
-	fetchDataCmd := func() tea.Msg {
-		results := searchfunc()
-		return newChoicesShownEvent(results)
+// choiceAt returns the index of the listed choice at the terminal cell x, y
+func (m *ChoicesModel) choiceAt(x, y int) (int, bool) {
+	if !m.resultsShown || x >= m.viewport.Width {
+		return 0, false
+	}
+	row := y - choicesHeaderLines
+	if row < 0 || row >= m.viewport.Height {
+		return 0, false
 	}
+	i := row + m.viewport.YOffset
+	if i >= len(m.getFilteredChoices(m.choices)) {
+		return 0, false
+	}
+	return i, true
+}
 
-	return m, tea.Sequence(
-		func() tea.Msg {
-			return newChoicesLoadingEvent()
-		},
-		tea.Cmd(func() tea.Msg {
-			return fetchDataCmd()
-		}),
-	)
+// jumps to the choice with the typed number, the digits typed quickly after
+// each other are joined so "1" then "2" goes to 12
+func (m *ChoicesModel) jumpTo(digit string) {
+	if time.Since(m.jumpAt) > jumpTimeout {
+		m.jump = ""
+	}
+	m.jump += digit
+	m.jumpAt = time.Now()
+	n, err := strconv.Atoi(m.jump)
+	if err != nil {
+		return
+	}
+	for i, c := range m.getFilteredChoices(m.choices) {
+		number := i + 1
+		if m.numberFunc != nil {
+			number = m.numberFunc(c)
+		}
+		if number == n {
+			m.moveCursor(i)
+			return
+		}
+	}
+}
+
+func (m *ChoicesModel) startFiltering() {
+	m.filtering = true
+	m.textInput.Focus()
+}
+
+func (m *ChoicesModel) stopFiltering() {
+	m.filtering = false
+	m.textInput.Blur()
+}
+
+// startLoading empties the list and shows the spinner until the choices are shown
+func (m *ChoicesModel) startLoading(searchKey string) {
+	m.searchKey = searchKey
+	m.Update(newChoicesLoadingEvent())
 }
 
 func (m *ChoicesModel) Init() tea.Cmd {
@@ -153,40 +361,53 @@ func (m *ChoicesModel) Init() tea.Cmd {
 }
 
 func (m *ChoicesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var (
-		cmd   tea.Cmd
-		vpCmd tea.Cmd
-	)
-	autoUpdateViewPort := true
+	var cmd tea.Cmd
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.viewport.Width = msg.Width
 		return m, nil
-	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyDown:
-			autoUpdateViewPort = false
-			filtered := m.getFilteredChoices(m.choices)
-			if m.cursor < len(filtered)-1 {
-				m.cursor++
-			}
-			lastVisibleItemCursor := m.firstChoiceVisibleCursor + vpHight - 1
-			if m.cursor > lastVisibleItemCursor {
-				m.viewport.LineDown(1)
-				m.firstChoiceVisibleCursor++
-			}
-		case tea.KeyUp:
-			autoUpdateViewPort = false
-			if m.cursor > 0 {
-				m.cursor--
-			}
-			if m.cursor < m.firstChoiceVisibleCursor {
-				m.viewport.LineUp(1)
-				m.firstChoiceVisibleCursor--
+	case tea.MouseMsg:
+		if msg.Action != tea.MouseActionPress {
+			return m, cmd
+		}
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			m.moveCursor(m.cursor - 1)
+		case tea.MouseButtonWheelDown:
+			m.moveCursor(m.cursor + 1)
+		case tea.MouseButtonLeft:
+			if i, ok := m.choiceAt(msg.X, msg.Y); ok {
+				m.moveCursor(i)
 			}
-		case tea.KeyEnter:
+		}
+		return m, cmd
+	case tea.KeyMsg:
+		if !m.resultsShown {
+			return m, cmd
+		}
+		if m.filtering {
+			m.updateFilter(msg)
 			return m, cmd
 		}
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			m.moveCursor(m.cursor - 1)
+		case key.Matches(msg, m.keys.Down):
+			m.moveCursor(m.cursor + 1)
+		case key.Matches(msg, m.keys.PageUp):
+			m.moveCursor(m.cursor - m.viewport.Height)
+		case key.Matches(msg, m.keys.PageDown):
+			m.moveCursor(m.cursor + m.viewport.Height)
+		case key.Matches(msg, m.keys.Home):
+			m.moveCursor(0)
+		case key.Matches(msg, m.keys.End):
+			m.moveCursor(len(m.getFilteredChoices(m.choices)) - 1)
+		case key.Matches(msg, m.keys.Filter):
+			m.startFiltering()
+		case len(msg.Runes) == 1 && msg.Runes[0] >= '0' && msg.Runes[0] <= '9':
+			m.jumpTo(string(msg.Runes))
+		}
+		return m, cmd
 
 	case spinner.TickMsg:
 		var cmd tea.Cmd
@@ -204,30 +425,34 @@ func (m *ChoicesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.loading = false
 		m.choices = msg.results
 		m.resultsShown = true
+		m.viewport.GotoTop()
 		m.viewport.SetContent(m.getViewportContentFromChoices(msg.results, 0))
 		return m, cmd
-
-	default:
-		return m, tea.Batch(cmd, vpCmd)
 	}
 
-	if autoUpdateViewPort {
-		m.viewport, vpCmd = m.viewport.Update(msg)
-	}
+	return m, cmd
+}
 
-	// only update the input when results are shown
-	if m.resultsShown {
-		keyType, ok := msg.(tea.KeyMsg)
+// while filtering the keys are typed in the filter, enter keeps the filter
+// and esc drops it
+func (m *ChoicesModel) updateFilter(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.stopFiltering()
+	case tea.KeyEsc:
+		m.textInput.Reset()
+		m.stopFiltering()
+		m.moveCursor(0)
+	case tea.KeyUp:
+		m.moveCursor(m.cursor - 1)
+	case tea.KeyDown:
+		m.moveCursor(m.cursor + 1)
+	default:
 		m.textInput, _ = m.textInput.Update(msg)
-		m.viewport.SetContent(m.getViewportContentFromChoices(m.choices, -1))
-		if keyType.Type != tea.KeyUp && keyType.Type != tea.KeyDown && ok {
-			m.cursor = 0
-			m.viewport.GotoTop()
-			m.viewport.SetContent(m.getViewportContentFromChoices(m.choices, -1))
-		}
+		// the best match is on top
+		m.viewport.GotoTop()
+		m.moveCursor(0)
 	}
-
-	return m, tea.Batch(cmd, vpCmd)
 }
 
 func (m *ChoicesModel) View() string {
@@ -235,7 +460,7 @@ func (m *ChoicesModel) View() string {
 
 	if m.loading {
 		// Show spinner while loading
-		msg += m.spinner.View() + " Loading...\n"
+		msg += joinRow(" ", m.spinner.View(), tr("Loading...")) + "\n"
 	} else {
 		msg += "\n"
 	}
@@ -243,7 +468,7 @@ func (m *ChoicesModel) View() string {
 	if m.resultsShown {
 		msg += m.textInput.View()
 		msg += "\n"
-		msg += "Showing " + strconv.Itoa(len(m.choices)) + " results for " + m.searchKey + "\n\n"
+		msg += tr("Showing %v results for %s", len(m.choices), m.searchKey) + "\n\n"
 		msg += m.viewport.View()
 	}
 
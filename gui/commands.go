@@ -0,0 +1,257 @@
+package gui
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ani/ani-ar/api"
+	"github.com/ani/ani-ar/cast"
+	"github.com/ani/ani-ar/fetcher"
+	"github.com/ani/ani-ar/history"
+	"github.com/ani/ani-ar/player"
+	"github.com/ani/ani-ar/profile"
+	"github.com/ani/ani-ar/types"
+	"github.com/ani/ani-ar/watchlist"
+)
+
+// runRequest runs fn as a command that returns no message once the request is
+// cancelled, the fetchers can't be interrupted so fn keeps running and its
+// result is dropped
+func runRequest(ctx context.Context, fn func() tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		result := make(chan tea.Msg, 1)
+		go func() {
+			result <- fn()
+		}()
+		select {
+		case msg := <-result:
+			return msg
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func toChoices[T any](list []T) []interface{} {
+	choices := make([]interface{}, len(list))
+	for i := range list {
+		choices[i] = list[i]
+	}
+	return choices
+}
+
+func searchCmd(ctx context.Context, id int, f fetcher.Fetcher, searchKey string) tea.Cmd {
+	return runRequest(ctx, func() tea.Msg {
+		results := f.Search(searchKey)
+		var err error
+		if len(results) == 0 {
+			err = errors.New(translate("no anime found for %q", searchKey))
+		}
+		return newChoicesLoadedEvent(id, stageResults, toChoices(results), err)
+	})
+}
+
+func episodesCmd(ctx context.Context, id int, f fetcher.Fetcher, anime types.AniResult) tea.Cmd {
+	return runRequest(ctx, func() tea.Msg {
+		episodes := f.GetEpisodes(anime)
+		var err error
+		if len(episodes) == 0 {
+			err = errors.New(translate("no episodes found for %s", anime.DisplayName))
+		} else {
+			api.GetJikanApi().AddEpisodesDetails(anime, episodes)
+		}
+		return newChoicesLoadedEvent(id, stageEpisodes, toChoices(episodes), err)
+	})
+}
+
+func castDevicesCmd(ctx context.Context, id int) tea.Cmd {
+	return runRequest(ctx, func() tea.Msg {
+		devices, err := player.DiscoverCastDevices()
+		if err == nil && len(devices) == 0 {
+			err = errors.New(translate("no cast devices found on the network"))
+		}
+		return newChoicesLoadedEvent(id, stageCastDevices, toChoices(devices), err)
+	})
+}
+
+func episodeTitle(ep types.AniEpisode) string {
+	return translate("%s - episode %v", ep.Anime.DisplayName, ep.Number)
+}
+
+func errNoPlayerUrl() error {
+	return errors.New(translate("couldn't get the video url of the episode"))
+}
+
+// episodeVideo returns the video of the episode in the quality of the active profile
+func episodeVideo(ep types.AniEpisode) *types.AniVideo {
+	return types.SelectVideo(ep.GetPlayersWithQuality(), profile.GetSettings(profile.Active()).Quality)
+}
+
+// playCmd starts the player with the episode, the player isn't waited for
+func playCmd(ctx context.Context, id int, ep types.AniEpisode, runVideo func(video types.AniVideo, title string) error) tea.Cmd {
+	return runRequest(ctx, func() tea.Msg {
+		video := episodeVideo(ep)
+		// the player isn't started for a cancelled request, its result would be dropped
+		if ctx.Err() != nil {
+			return nil
+		}
+		if video == nil {
+			return newEpisodeStartedEvent(id, ep, "", errNoPlayerUrl())
+		}
+		if err := runVideo(*video, episodeTitle(ep)); err != nil {
+			return newEpisodeStartedEvent(id, ep, "", err)
+		}
+		history.GetStore().MarkWatched(ep.Anime.Id, ep.Number)
+		return newEpisodeStartedEvent(id, ep, "", nil)
+	})
+}
+
+type castProxyKey struct {
+	ip      string
+	fetcher fetcher.Fetcher
+}
+
+var (
+	castProxiesMu sync.Mutex
+	// the stream proxies the devices play the videos needing headers from,
+	// by the local ip the device reaches us at and the fetcher of the episodes
+	castProxies = make(map[castProxyKey]string)
+)
+
+// castUrl returns the url the device plays the episode from, the devices can't
+// send the headers the video host expects so these videos go through a stream
+// proxy that lives as long as the tui
+func castUrl(f fetcher.Fetcher, ep types.AniEpisode, video *types.AniVideo, device *cast.Device) (string, error) {
+	if len(video.Headers) == 0 {
+		return video.Src, nil
+	}
+	ip, err := device.LocalIP()
+	if err != nil {
+		return "", err
+	}
+	castProxiesMu.Lock()
+	defer castProxiesMu.Unlock()
+	key := castProxyKey{ip, f}
+	if _, found := castProxies[key]; !found {
+		l, err := api.StartStreamProxy(net.JoinHostPort(ip, "0"), f)
+		if err != nil {
+			return "", err
+		}
+		castProxies[key] = "http://" + net.JoinHostPort(ip, strconv.Itoa(l.Addr().(*net.TCPAddr).Port))
+	}
+	return api.StreamUrl(castProxies[key], ep.Anime.Id, ep.Number), nil
+}
+
+func castCmd(ctx context.Context, id int, f fetcher.Fetcher, ep types.AniEpisode, device *cast.Device) tea.Cmd {
+	return runRequest(ctx, func() tea.Msg {
+		video := episodeVideo(ep)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if video == nil {
+			return newEpisodeStartedEvent(id, ep, device.Name, errNoPlayerUrl())
+		}
+		url, err := castUrl(f, ep, video, device)
+		if err != nil {
+			return newEpisodeStartedEvent(id, ep, device.Name, err)
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err := device.Cast(url, episodeTitle(ep)); err != nil {
+			return newEpisodeStartedEvent(id, ep, device.Name, err)
+		}
+		history.GetStore().MarkWatched(ep.Anime.Id, ep.Number)
+		return newEpisodeStartedEvent(id, ep, device.Name, nil)
+	})
+}
+
+// matchCmd looks the browsed anime up in the fetcher, watchlist entries are
+// looked up by id in the fetcher they were added from
+func matchCmd(ctx context.Context, id int, f fetcher.Fetcher, selected interface{}) tea.Cmd {
+	return runRequest(ctx, func() tea.Msg {
+		if entry, isEntry := selected.(watchlist.Entry); isEntry {
+			if source, err := fetcher.GetFetcherByName(entry.Source); err == nil {
+				f = source
+			}
+			return newBrowseMatchedEvent(id, entry.Title, f.GetAnimeResult(entry.Id), f)
+		}
+		info := selected.(*api.JikanAnimeInfo)
+		return newBrowseMatchedEvent(id, info.Title, api.FindInFetcher(f, info), f)
+	})
+}
+
+// addToWatchlistCmd adds the anime, an anime already in the watchlist keeps its status
+func addToWatchlistCmd(f fetcher.Fetcher, anime types.AniResult) tea.Cmd {
+	return func() tea.Msg {
+		store, err := watchlist.GetStore()
+		if err != nil {
+			return newWatchlistUpdatedEvent("", err)
+		}
+		entry := api.NewWatchlistEntry(f, anime)
+		if current, found := store.Get(entry.Source, entry.Id); found {
+			entry.Status = current.Status
+			entry.Favorite = current.Favorite
+		}
+		_, err = store.Add(entry)
+		return newWatchlistUpdatedEvent(translate("%s is in the watchlist", anime.DisplayName), err)
+	}
+}
+
+// cycleStatusCmd moves the entry to the status after its current one
+func cycleStatusCmd(entry watchlist.Entry) tea.Cmd {
+	return func() tea.Msg {
+		next := watchlist.Statuses[0]
+		for i, status := range watchlist.Statuses {
+			if status == entry.Status {
+				next = watchlist.Statuses[(i+1)%len(watchlist.Statuses)]
+			}
+		}
+		store, err := watchlist.GetStore()
+		if err == nil {
+			_, err = store.SetStatus(entry.Source, entry.Id, next)
+		}
+		return newWatchlistUpdatedEvent(translate("%s is now %s", entry.Title, translate(next)), err)
+	}
+}
+
+func toggleFavoriteCmd(entry watchlist.Entry) tea.Cmd {
+	return func() tea.Msg {
+		store, err := watchlist.GetStore()
+		if err == nil {
+			_, err = store.SetFavorite(entry.Source, entry.Id, !entry.Favorite)
+		}
+		message := translate("%s is a favorite", entry.Title)
+		if entry.Favorite {
+			message = translate("%s is not a favorite anymore", entry.Title)
+		}
+		return newWatchlistUpdatedEvent(message, err)
+	}
+}
+
+func removeFromWatchlistCmd(entry watchlist.Entry) tea.Cmd {
+	return func() tea.Msg {
+		store, err := watchlist.GetStore()
+		if err == nil {
+			err = store.Remove(entry.Source, entry.Id)
+		}
+		return newWatchlistUpdatedEvent(translate("%s was removed from the watchlist", entry.Title), err)
+	}
+}
+
+func profilesCmd(ctx context.Context, id int) tea.Cmd {
+	return runRequest(ctx, func() tea.Msg {
+		return newChoicesLoadedEvent(id, stageProfiles, toChoices(profile.List()), nil)
+	})
+}
+
+// runVideo starts the player without waiting for it
+func runVideo(video types.AniVideo, title string) error {
+	_, err := player.RunVideo(video, title)
+	return err
+}
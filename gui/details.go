@@ -0,0 +1,179 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ani/ani-ar/api"
+	"github.com/ani/ani-ar/fetcher"
+	"github.com/ani/ani-ar/types"
+)
+
+// wait for the cursor to rest on an item before fetching its details,
+// scrolling through the list shouldn't flood jikan with requests
+const detailsDebounce = 300 * time.Millisecond
+
+const (
+	detailsPaneWidth = 44
+	coverRows        = 14
+	synopsisLines    = 10
+)
+
+type animeDetails struct {
+	result *api.EnhancedAnimeResult
+	cover  string
+	err    error
+}
+
+// detailsModel is the pane showing the details of the highlighted anime,
+// the details are fetched lazily and cached by anime id
+type detailsModel struct {
+	fetcher       fetcher.Fetcher
+	imageProtocol int
+	cache         map[string]*animeDetails
+	loading       map[string]bool
+	current       *types.AniResult
+}
+
+func newDetailsModel(f fetcher.Fetcher) *detailsModel {
+	return &detailsModel{
+		fetcher:       f,
+		imageProtocol: detectImageProtocol(),
+		cache:         make(map[string]*animeDetails),
+		loading:       make(map[string]bool),
+	}
+}
+
+// highlight sets the shown anime and returns the command to fetch its details if needed
+func (d *detailsModel) highlight(anime *types.AniResult) tea.Cmd {
+	if anime == nil || (d.current != nil && d.current.Id == anime.Id) {
+		d.current = anime
+		return nil
+	}
+	d.current = anime
+	if _, found := d.cache[anime.Id]; found || d.loading[anime.Id] {
+		return nil
+	}
+	id := anime.Id
+	return tea.Tick(detailsDebounce, func(time.Time) tea.Msg {
+		return newDetailsRequestedEvent(id)
+	})
+}
+
+func (d *detailsModel) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case DetailsRequestedEvent:
+		// the cursor moved away while waiting
+		if d.current == nil || d.current.Id != msg.id || d.loading[msg.id] {
+			return nil
+		}
+		if _, found := d.cache[msg.id]; found {
+			return nil
+		}
+		d.loading[msg.id] = true
+		anime := *d.current
+		return func() tea.Msg {
+			return d.fetch(anime)
+		}
+	case DetailsLoadedEvent:
+		delete(d.loading, msg.id)
+		d.cache[msg.id] = msg.details
+	}
+	return nil
+}
+
+func (d *detailsModel) fetch(anime types.AniResult) DetailsLoadedEvent {
+	details := &animeDetails{}
+	details.result, details.err = api.GetAnimeEnhancedResults(anime.Id, d.fetcher)
+
+	cover := anime.DisplayCover
+	if details.result != nil && details.result.Details != nil && details.result.Details.Images.JPG.LargeImageURL != "" {
+		cover = details.result.Details.Images.JPG.LargeImageURL
+	}
+	if cover != "" {
+		if img, err := fetchImage(cover); err == nil {
+			details.cover = renderImage(img, detailsPaneWidth-2, coverRows, d.imageProtocol)
+		}
+	}
+	return newDetailsLoadedEvent(anime.Id, details)
+}
+
+func joinGenres(genres []api.Genre) string {
+	names := make([]string, len(genres))
+	for i, g := range genres {
+		names[i] = g.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func joinCompanies(companies []api.Company) string {
+	names := make([]string, len(companies))
+	for i, c := range companies {
+		names[i] = c.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func (d *detailsModel) View() string {
+	// the pane is on the left of the list in the arabic ui
+	rtl := isRTLUI()
+	style := lipgloss.NewStyle().
+		Width(detailsPaneWidth).
+		PaddingLeft(1).
+		Border(paneBorder, false, false, false, true).
+		BorderForeground(paneBorderColor)
+	if rtl {
+		style = style.PaddingLeft(0).PaddingRight(1).
+			Border(paneBorder, false, true, false, false).
+			Align(lipgloss.Right)
+	}
+	if d.current == nil {
+		return style.Render("")
+	}
+
+	text := lipgloss.NewStyle().Width(detailsPaneWidth - 1)
+	if rtl {
+		text = text.Align(lipgloss.Right)
+	}
+
+	details, found := d.cache[d.current.Id]
+	if !found {
+		return style.Render(titleStyle.Render(bidiText(d.current.DisplayName, rtl)) + "\n\n" + mutedStyle.Render(tr("loading details...")))
+	}
+
+	var lines []string
+	if details.cover != "" {
+		lines = append(lines, details.cover, "")
+	}
+	if details.err != nil || details.result == nil || details.result.Details == nil {
+		lines = append(lines, titleStyle.Render(bidiText(d.current.DisplayName, rtl)), mutedStyle.Render(tr("no MyAnimeList details found")))
+		return style.Render(strings.Join(lines, "\n"))
+	}
+
+	info := details.result.Details
+	lines = append(lines, text.Render(titleStyle.Render(info.Title)))
+	if info.TitleEnglish != "" && info.TitleEnglish != info.Title {
+		lines = append(lines, text.Render(mutedStyle.Render(info.TitleEnglish)))
+	}
+	year := info.Year
+	if year == 0 {
+		year = info.Aired.Prop.From.Year
+	}
+	lines = append(lines, fmt.Sprintf("★ %.2f · %s · %d · %s", info.Score, info.Type, year, info.Status))
+	lines = append(lines,
+		text.Render(tr("Genres: %s", joinGenres(info.Genres))),
+		text.Render(tr("Studios: %s", joinCompanies(info.Studios))),
+		"",
+	)
+
+	synopsis := strings.Split(text.Render(info.Synopsis), "\n")
+	if len(synopsis) > synopsisLines {
+		synopsis = append(synopsis[:synopsisLines], "...")
+	}
+	lines = append(lines, synopsis...)
+	return style.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,87 @@
+package gui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ani/ani-ar/download"
+	"github.com/ani/ani-ar/fetcher"
+)
+
+// only the latest jobs are shown under the episodes
+const downloadsPanelJobs = 5
+
+// downloadsModel is the panel showing the download queue, the queue runs in
+// its own goroutine and its updates are received through a channel
+type downloadsModel struct {
+	queue    *download.Queue
+	updates  chan download.Job
+	progress progress.Model
+}
+
+func newDownloadsModel(f fetcher.Fetcher) *downloadsModel {
+	d := &downloadsModel{
+		updates:  make(chan download.Job, 64),
+		progress: progress.New(progress.WithDefaultGradient(), progress.WithWidth(30)),
+	}
+	d.queue = d.newQueue(f)
+	return d
+}
+
+func (d *downloadsModel) newQueue(f fetcher.Fetcher) *download.Queue {
+	return download.NewQueue(&download.Downloader{Fetcher: f}, download.DefaultDir(), func(job download.Job) {
+		d.updates <- job
+	})
+}
+
+// switchFetcher rebuilds the queue with the fetcher of the new profile, the
+// updates keep their channel so waitForUpdate doesn't have to be returned
+// again and the downloads of the old queue still finish in the background
+func (d *downloadsModel) switchFetcher(f fetcher.Fetcher) {
+	d.queue = d.newQueue(f)
+}
+
+// waitForUpdate returns the command waiting for the next job update, it has
+// to be returned again after every DownloadUpdatedEvent
+func (d *downloadsModel) waitForUpdate() tea.Cmd {
+	return func() tea.Msg {
+		return newDownloadUpdatedEvent(<-d.updates)
+	}
+}
+
+func (d *downloadsModel) View() string {
+	jobs := d.queue.Jobs()
+	if len(jobs) == 0 {
+		return ""
+	}
+
+	done := 0
+	for _, job := range jobs {
+		if job.Status == download.JobDone {
+			done++
+		}
+	}
+	msg := downloadsTitleStyle.Render(tr("Downloads (%d/%d) to %s", done, len(jobs), download.DefaultDir())) + "\n"
+
+	if len(jobs) > downloadsPanelJobs {
+		jobs = jobs[len(jobs)-downloadsPanelJobs:]
+	}
+	for _, job := range jobs {
+		name := bidiText(fmt.Sprintf("%s #%v", job.Episode.Anime.DisplayName, job.Episode.Number), isRTLUI())
+		switch job.Status {
+		case download.JobQueued:
+			msg += "  " + joinRow(" ", name, tr("queued")) + "\n"
+		case download.JobPaused:
+			msg += "  " + joinRow(" ", name, tr("paused")) + "\n"
+		case download.JobDownloading:
+			msg += "  " + joinRow(" ", name, d.progress.ViewAs(job.Progress)) + "\n"
+		case download.JobDone:
+			msg += "  " + joinRow(" ", name, markersStyle.Render(tr("done ↓"))) + "\n"
+		case download.JobFailed:
+			msg += "  " + joinRow(" ", name, downloadFailedStyle.Render(tr("failed: %s", job.Error))) + "\n"
+		}
+	}
+	return msg
+}
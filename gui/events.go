@@ -1,5 +1,11 @@
 package gui
 
+import (
+	"github.com/ani/ani-ar/download"
+	"github.com/ani/ani-ar/fetcher"
+	"github.com/ani/ani-ar/types"
+)
+
 // events
 /////////////////////////////////////////////////////////////////
 
@@ -39,3 +45,118 @@ func newEpisodesLoadedEvent(results []interface{}) EpisodesLoadedEvent {
 		results: results,
 	}
 }
+
+// ///////////////////////////////////////////////////////////////
+type DetailsRequestedEvent struct {
+	id string
+}
+
+func newDetailsRequestedEvent(id string) DetailsRequestedEvent {
+	return DetailsRequestedEvent{
+		id: id,
+	}
+}
+
+// ///////////////////////////////////////////////////////////////
+type DetailsLoadedEvent struct {
+	id      string
+	details *animeDetails
+}
+
+func newDetailsLoadedEvent(id string, details *animeDetails) DetailsLoadedEvent {
+	return DetailsLoadedEvent{
+		id:      id,
+		details: details,
+	}
+}
+
+// ///////////////////////////////////////////////////////////////
+type DownloadUpdatedEvent struct {
+	job download.Job
+}
+
+func newDownloadUpdatedEvent(job download.Job) DownloadUpdatedEvent {
+	return DownloadUpdatedEvent{
+		job: job,
+	}
+}
+
+// ///////////////////////////////////////////////////////////////
+type BrowseLoadedEvent struct {
+	tab     int
+	results []interface{}
+	err     error
+}
+
+func newBrowseLoadedEvent(tab int, results []interface{}, err error) BrowseLoadedEvent {
+	return BrowseLoadedEvent{
+		tab:     tab,
+		results: results,
+		err:     err,
+	}
+}
+
+// ///////////////////////////////////////////////////////////////
+type BrowseMatchedEvent struct {
+	requestId int
+	title     string
+	anime     *types.AniResult
+	fetcher   fetcher.Fetcher
+}
+
+func newBrowseMatchedEvent(requestId int, title string, anime *types.AniResult, f fetcher.Fetcher) BrowseMatchedEvent {
+	return BrowseMatchedEvent{
+		requestId: requestId,
+		title:     title,
+		anime:     anime,
+		fetcher:   f,
+	}
+}
+
+// ///////////////////////////////////////////////////////////////
+type WatchlistUpdatedEvent struct {
+	message string
+	err     error
+}
+
+func newWatchlistUpdatedEvent(message string, err error) WatchlistUpdatedEvent {
+	return WatchlistUpdatedEvent{
+		message: message,
+		err:     err,
+	}
+}
+
+// ///////////////////////////////////////////////////////////////
+type ChoicesLoadedEvent struct {
+	requestId int
+	stage     stage
+	results   []interface{}
+	err       error
+}
+
+func newChoicesLoadedEvent(requestId int, s stage, results []interface{}, err error) ChoicesLoadedEvent {
+	return ChoicesLoadedEvent{
+		requestId: requestId,
+		stage:     s,
+		results:   results,
+		err:       err,
+	}
+}
+
+// ///////////////////////////////////////////////////////////////
+type EpisodeStartedEvent struct {
+	requestId int
+	episode   types.AniEpisode
+	// the name of the cast device, empty when played locally
+	device string
+	err    error
+}
+
+func newEpisodeStartedEvent(requestId int, episode types.AniEpisode, device string, err error) EpisodeStartedEvent {
+	return EpisodeStartedEvent{
+		requestId: requestId,
+		episode:   episode,
+		device:    device,
+		err:       err,
+	}
+}
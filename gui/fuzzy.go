@@ -0,0 +1,129 @@
+package gui
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// scores of the fuzzy matcher, consecutive characters and word starts are
+// preferred so "hxh" ranks "Hunter x Hunter" first
+const (
+	fuzzyMatchScore       = 1
+	fuzzyConsecutiveBonus = 5
+	fuzzyWordStartBonus   = 8
+	// maximum penalty for a match starting far from the beginning
+	fuzzyMaxLeadingPenalty = 5
+)
+
+// bold and underline, turned off without resetting the colors around
+const (
+	matchedStart = "\x1b[1;4m"
+	matchedEnd   = "\x1b[22;24m"
+)
+
+// fuzzyMatch reports whether every rune of the pattern shows up in s in order,
+// it returns the score of the best match and the rune indexes of the matched runes
+func fuzzyMatch(pattern, s string) (int, []int, bool) {
+	// lowered rune by rune, strings.ToLower can change the number of runes
+	// and the indexes wouldn't point to the runes of s anymore
+	p, r := lowerRunes(pattern), lowerRunes(s)
+	if len(p) == 0 {
+		return 0, nil, true
+	}
+
+	bestScore, found := 0, false
+	var best []int
+	// try every start of the first rune, the greedy match from there is scored
+	for start := range r {
+		if r[start] != p[0] {
+			continue
+		}
+		score, positions, ok := fuzzyMatchFrom(p, r, start)
+		if ok && (!found || score > bestScore) {
+			bestScore, best, found = score, positions, true
+		}
+	}
+	return bestScore, best, found
+}
+
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+// splitMatches returns the matched indexes of every part of the text made of
+// the parts joined by a space, counted from the start of their part
+func splitMatches(positions []int, parts []string) [][]int {
+	matches := make([][]int, len(parts))
+	start := 0
+	for i, part := range parts {
+		end := start + utf8.RuneCountInString(part)
+		for _, p := range positions {
+			if p >= start && p < end {
+				matches[i] = append(matches[i], p-start)
+			}
+		}
+		start = end + 1
+	}
+	return matches
+}
+
+func fuzzyMatchFrom(p, r []rune, start int) (int, []int, bool) {
+	positions := make([]int, 0, len(p))
+	score := -min(start, fuzzyMaxLeadingPenalty)
+	pi := 0
+	for i := start; i < len(r) && pi < len(p); i++ {
+		if r[i] != p[pi] {
+			continue
+		}
+		score += fuzzyMatchScore
+		if len(positions) > 0 && positions[len(positions)-1] == i-1 {
+			score += fuzzyConsecutiveBonus
+		}
+		if i == 0 || !unicode.IsLetter(r[i-1]) && !unicode.IsDigit(r[i-1]) {
+			score += fuzzyWordStartBonus
+		}
+		positions = append(positions, i)
+		pi++
+	}
+	return score, positions, pi == len(p)
+}
+
+// highlightMatches underlines the runes at the given indexes of the visible
+// text, the ansi sequences already in s are kept as they are
+func highlightMatches(s string, positions []int) string {
+	if len(positions) == 0 {
+		return s
+	}
+	matched := make(map[int]bool, len(positions))
+	for _, i := range positions {
+		matched[i] = true
+	}
+
+	var b strings.Builder
+	runes := []rune(s)
+	visible := 0
+	for i := 0; i < len(runes); i++ {
+		// copy the escape sequences without counting them
+		if runes[i] == '\x1b' && i+1 < len(runes) && runes[i+1] == '[' {
+			j := i + 2
+			for j < len(runes) && (runes[j] < 0x40 || runes[j] > 0x7e) {
+				j++
+			}
+			b.WriteString(string(runes[i:min(j+1, len(runes))]))
+			i = j
+			continue
+		}
+		if matched[visible] {
+			b.WriteString(matchedStart + string(runes[i]) + matchedEnd)
+		} else {
+			b.WriteRune(runes[i])
+		}
+		visible++
+	}
+	return b.String()
+}
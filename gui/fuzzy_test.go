@@ -0,0 +1,58 @@
+package gui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ani/ani-ar/types"
+)
+
+func TestFuzzyMatchIndexesTheRunesOfTheText(t *testing.T) {
+	// strings.ToLower makes two runes of İ, the indexes would be off by one
+	_, positions, ok := fuzzyMatch("bul", "İstanbul")
+	if !ok || !slices.Equal(positions, []int{5, 6, 7}) {
+		t.Fatalf("got %v %v, want the indexes of bul in İstanbul", positions, ok)
+	}
+	if got := highlightMatches("İstanbul", positions); got != "İstan"+matchedStart+"b"+matchedEnd+matchedStart+"u"+matchedEnd+matchedStart+"l"+matchedEnd {
+		t.Fatalf("the matched runes should be highlighted, got %q", got)
+	}
+}
+
+func TestFilterMatchesTheLogicalTitle(t *testing.T) {
+	mode := bidiMode
+	defer func() { bidiMode = mode }()
+
+	anime := types.AniResult{Id: "naruto", DisplayName: "ناروتو شيبودن", Episodes: 500}
+	for _, bidiMode = range []int{bidiIsolate, bidiReorder} {
+		m := initialChoicesModelForAnimeTitles(defaultKeyMap())
+		// typed in logical order, the reordered title has the runes backwards
+		m.textInput.SetValue("نارو")
+		ranked := m.rankChoices([]interface{}{anime})
+		if len(ranked) != 1 || !slices.Equal(ranked[0].matches[0], []int{0, 1, 2, 3}) {
+			t.Fatalf("mode %d: the title should match, got %+v", bidiMode, ranked)
+		}
+
+		formatted := m.choiceFormatFunc(anime, ranked[0].matches)
+		for _, r := range "نارو" {
+			if !strings.Contains(formatted, matchedStart+string(r)+matchedEnd) {
+				t.Fatalf("mode %d: %q should be highlighted in %q", bidiMode, r, formatted)
+			}
+		}
+		if strings.Contains(formatted, matchedStart+"ت"+matchedEnd) {
+			t.Fatalf("mode %d: only the matched runes should be highlighted in %q", bidiMode, formatted)
+		}
+	}
+}
+
+func TestVisualOrderKeepsTheHighlights(t *testing.T) {
+	// the runes are shown from the right, the highlights follow them
+	got := highlightedVisualOrder("ab جد", false, []int{0, 4})
+	want := matchedStart + "a" + matchedEnd + "b " + matchedStart + "د" + matchedEnd + "ج"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if visualOrder("ab جد", false) != "ab دج" {
+		t.Fatalf("got %q", visualOrder("ab جد", false))
+	}
+}
@@ -0,0 +1,171 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// the languages of the tui, picked with --ui-lang
+const (
+	LangEnglish = "en"
+	LangArabic  = "ar"
+)
+
+var ErrUnknownLanguage = errors.New("unknown ui language, it should be ar or en")
+
+var uiLang = LangEnglish
+
+// SetLanguage changes the language of the tui, the arabic ui is laid out
+// right to left
+func SetLanguage(lang string) error {
+	switch strings.ToLower(lang) {
+	case LangEnglish, "":
+		uiLang = LangEnglish
+	case LangArabic:
+		uiLang = LangArabic
+	default:
+		return ErrUnknownLanguage
+	}
+	return nil
+}
+
+func isRTLUI() bool {
+	return uiLang == LangArabic
+}
+
+// translate returns the english string in the ui language formatted with the args
+func translate(s string, args ...interface{}) string {
+	if uiLang == LangArabic {
+		if translated, ok := arabicStrings[s]; ok {
+			s = translated
+		}
+	}
+	if len(args) == 0 {
+		return s
+	}
+	return fmt.Sprintf(s, args...)
+}
+
+// tr translates the string and prepares it to be written in a row, see bidiText.
+// The result is final, the strings nested in it should use translate
+func tr(s string, args ...interface{}) string {
+	return bidiText(translate(s, args...), isRTLUI())
+}
+
+// joinRow joins the parts of a row, they are laid out right to left in the arabic ui
+func joinRow(sep string, parts ...string) string {
+	var kept []string
+	for _, part := range parts {
+		if part != "" {
+			kept = append(kept, part)
+		}
+	}
+	if isRTLUI() {
+		for i, j := 0, len(kept)-1; i < j; i, j = i+1, j-1 {
+			kept[i], kept[j] = kept[j], kept[i]
+		}
+	}
+	return strings.Join(kept, sep)
+}
+
+var arabicStrings = map[string]string{
+	// search and lists
+	"Search anime":              "ابحث عن أنمي",
+	"press / to filter":         "اضغط / للتصفية",
+	"Loading...":                "جار التحميل...",
+	"No matched results!!":      "لا توجد نتائج مطابقة!!",
+	"Showing %v results for %s": "عرض %v نتيجة لـ %s",
+	"%v episode":                "%v حلقة",
+	"%v episodes":               "%v حلقات",
+	"episode #%v":               "الحلقة #%v",
+	"%s - episode %v":           "%s - الحلقة %v",
+	"%s episodes":               "حلقات %s",
+	"[filler]":                  "[حشو]",
+	"[recap]":                   "[ملخص]",
+	"✓ watched • ↓ downloaded":  "✓ تمت المشاهدة • ↓ تم التنزيل",
+	"cast devices":              "أجهزة البث",
+	"Keybindings":               "الاختصارات",
+	"press any key to close":    "اضغط أي زر للإغلاق",
+
+	// browse and watchlist
+	"This season":   "هذا الموسم",
+	"Upcoming":      "القادم",
+	"Top airing":    "الأعلى تقييما حاليا",
+	"Most popular":  "الأكثر شعبية",
+	"Watchlist":     "قائمة المشاهدة",
+	"plan-to-watch": "للمشاهدة لاحقا",
+	"watching":      "أشاهده",
+	"completed":     "مكتمل",
+	"dropped":       "متروك",
+
+	// profiles
+	"profiles":                   "الملفات الشخصية",
+	"profile: %s":                "الملف الشخصي: %s",
+	"(active)":                   "(الحالي)",
+	"switched to the %s profile": "تم التبديل إلى الملف الشخصي %s",
+
+	// details
+	"loading details...":           "جار تحميل التفاصيل...",
+	"no MyAnimeList details found": "لا توجد تفاصيل على MyAnimeList",
+	"Genres: %s":                   "التصنيفات: %s",
+	"Studios: %s":                  "الاستوديوهات: %s",
+
+	// downloads
+	"Downloads (%d/%d) to %s": "التنزيلات (%d/%d) إلى %s",
+	"queued":                  "في الانتظار",
+	"paused":                  "متوقف مؤقتًا",
+	"done ↓":                  "اكتمل ↓",
+	"failed: %s":              "فشل: %s",
+
+	// status bar
+	"%s, reason: %s":                                      "%s، السبب: %s",
+	"something went wrong":                                "حدث خطأ ما",
+	"couldn't load %s":                                    "تعذر تحميل %s",
+	"couldn't play %s":                                    "تعذر تشغيل %s",
+	"couldn't open %s":                                    "تعذر فتح %s",
+	"couldn't update the watchlist":                       "تعذر تحديث قائمة المشاهدة",
+	"couldn't queue the downloads":                        "تعذرت إضافة التنزيلات إلى القائمة",
+	"playing %s":                                          "جار تشغيل %s",
+	"starting %s...":                                      "جار بدء %s...",
+	"casting %s to %s":                                    "جار بث %s إلى %s",
+	"casting %s to %s...":                                 "جار بث %s إلى %s...",
+	"looking %s up in the source...":                      "جار البحث عن %s في المصدر...",
+	"adding %s to the watchlist...":                       "جار إضافة %s إلى قائمة المشاهدة...",
+	"%s is in the watchlist":                              "%s في قائمة المشاهدة",
+	"%s is now %s":                                        "%s الآن %s",
+	"%s is a favorite":                                    "%s في المفضلة",
+	"%s is not a favorite anymore":                        "%s لم يعد في المفضلة",
+	"%s was removed from the watchlist":                   "تمت إزالة %s من قائمة المشاهدة",
+	"it wasn't found in the source, try searching for it": "لم يتم العثور عليه في المصدر، جرب البحث عنه",
+	"no anime found for %q":                               "لم يتم العثور على أنمي لـ %q",
+	"no episodes found for %s":                            "لم يتم العثور على حلقات لـ %s",
+	"no cast devices found on the network":                "لم يتم العثور على أجهزة بث في الشبكة",
+	"couldn't get the video url of the episode":           "تعذر الحصول على رابط فيديو الحلقة",
+
+	// key bindings
+	"up":                            "أعلى",
+	"down":                          "أسفل",
+	"page up":                       "الصفحة السابقة",
+	"page down":                     "الصفحة التالية",
+	"first":                         "الأول",
+	"last":                          "الأخير",
+	"select":                        "اختيار",
+	"back":                          "رجوع",
+	"filter":                        "تصفية",
+	"help":                          "مساعدة",
+	"quit":                          "خروج",
+	"cast to a device":              "البث إلى جهاز",
+	"hide/show filler":              "إخفاء/إظهار الحشو",
+	"download":                      "تنزيل",
+	"download all":                  "تنزيل الكل",
+	"select a range":                "تحديد مجموعة",
+	"browse seasonal and top anime": "تصفح أنمي الموسم والأعلى تقييما",
+	"next tab":                      "التبويب التالي",
+	"previous tab":                  "التبويب السابق",
+	"add to the watchlist":          "إضافة إلى قائمة المشاهدة",
+	"change the status":             "تغيير الحالة",
+	"favorite":                      "مفضلة",
+	"remove from the watchlist":     "إزالة من قائمة المشاهدة",
+	"switch profile":                "تبديل الملف الشخصي",
+}
@@ -0,0 +1,233 @@
+package gui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"net/http"
+	"os"
+	"strings"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	imageHalfBlocks = iota
+	imageKitty
+	imageSixel
+)
+
+// rough size of a terminal cell in pixels, used to size the graphics
+const (
+	cellPixelWidth  = 10
+	cellPixelHeight = 20
+)
+
+// detects the best graphics protocol from the environment, terminals can't be
+// queried while bubbletea owns the input so `ANI_AR_IMAGE_PROTOCOL` (kitty,
+// sixel or halfblocks) can be used to force one
+func detectImageProtocol() int {
+	switch strings.ToLower(os.Getenv("ANI_AR_IMAGE_PROTOCOL")) {
+	case "kitty":
+		return imageKitty
+	case "sixel":
+		return imageSixel
+	case "halfblocks":
+		return imageHalfBlocks
+	}
+
+	term := os.Getenv("TERM")
+	termProgram := os.Getenv("TERM_PROGRAM")
+	if os.Getenv("KITTY_WINDOW_ID") != "" || strings.Contains(term, "kitty") ||
+		termProgram == "WezTerm" || termProgram == "ghostty" {
+		return imageKitty
+	}
+	if strings.Contains(term, "foot") || strings.Contains(term, "mlterm") ||
+		termProgram == "iTerm.app" {
+		return imageSixel
+	}
+	return imageHalfBlocks
+}
+
+func fetchImage(url string) (image.Image, error) {
+	res, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get the image, status %d", res.StatusCode)
+	}
+	img, _, err := image.Decode(res.Body)
+	return img, err
+}
+
+func resizeImage(img image.Image, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.ApproxBiLinear.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Src, nil)
+	return dst
+}
+
+// fits the image in the cells box keeping its aspect ratio
+func fitInCells(img image.Image, cols, rows int) (int, int) {
+	b := img.Bounds()
+	if b.Dx() == 0 || b.Dy() == 0 {
+		return cols, rows
+	}
+	ratio := float64(b.Dy()) / float64(b.Dx()) * cellPixelWidth / cellPixelHeight
+	h := int(float64(cols) * ratio)
+	if h > rows {
+		return int(float64(rows) / ratio), rows
+	}
+	return cols, h
+}
+
+// renderImage renders the image in a box of cols x rows cells, the result
+// always takes the whole box so it can be laid out like text
+func renderImage(img image.Image, cols, rows int, protocol int) string {
+	cols, rows = fitInCells(img, cols, rows)
+	if cols <= 0 || rows <= 0 {
+		return ""
+	}
+	switch protocol {
+	case imageKitty:
+		return renderKitty(img, cols, rows)
+	case imageSixel:
+		return renderSixel(img, cols, rows)
+	}
+	return renderHalfBlocks(img, cols, rows)
+}
+
+// every cell shows two pixels, the top one as the foreground of "▀" and the
+// bottom one as the background
+func renderHalfBlocks(img image.Image, cols, rows int) string {
+	small := resizeImage(img, cols, rows*2)
+	var b strings.Builder
+	for y := 0; y < rows*2; y += 2 {
+		for x := 0; x < cols; x++ {
+			top := small.RGBAAt(x, y)
+			bottom := small.RGBAAt(x, y+1)
+			fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%dm\x1b[48;2;%d;%d;%dm▀", top.R, top.G, top.B, bottom.R, bottom.G, bottom.B)
+		}
+		b.WriteString("\x1b[0m")
+		if y+2 < rows*2 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// the graphic is drawn from the first cell, the rest of the box is reserved with spaces
+func reserveCells(graphic string, cols, rows int) string {
+	blank := strings.Repeat(" ", cols)
+	lines := make([]string, rows)
+	for i := range lines {
+		lines[i] = blank
+	}
+	lines[0] = graphic + blank
+	return strings.Join(lines, "\n")
+}
+
+func renderKitty(img image.Image, cols, rows int) string {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, resizeImage(img, cols*cellPixelWidth, rows*cellPixelHeight)); err != nil {
+		return renderHalfBlocks(img, cols, rows)
+	}
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	// the payload is sent in chunks of 4096 bytes, the cursor is kept in
+	// place (C=1) and the same image id replaces the previous cover
+	var b strings.Builder
+	for i := 0; i < len(data); i += 4096 {
+		end := min(i+4096, len(data))
+		more := 0
+		if end < len(data) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,i=31,q=2,C=1,c=%d,r=%d,m=%d;%s\x1b\\", cols, rows, more, data[i:end])
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, data[i:end])
+		}
+	}
+	return reserveCells(b.String(), cols, rows)
+}
+
+// sixel colors are quantized to a 6x6x6 palette which is good enough for a cover
+func renderSixel(img image.Image, cols, rows int) string {
+	width, height := cols*cellPixelWidth, rows*cellPixelHeight
+	small := resizeImage(img, width, height)
+
+	paletteIdx := func(c color.RGBA) int {
+		return int(c.R)*6/256*36 + int(c.G)*6/256*6 + int(c.B)*6/256
+	}
+
+	var b strings.Builder
+	b.WriteString("\x1bPq")
+	fmt.Fprintf(&b, "\"1;1;%d;%d", width, height)
+	for i := 0; i < 216; i++ {
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i, i/36*100/5, i/6%6*100/5, i%6*100/5)
+	}
+
+	pixels := make([]int, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			pixels[y*width+x] = paletteIdx(small.RGBAAt(x, y))
+		}
+	}
+
+	for band := 0; band < height; band += 6 {
+		used := make(map[int]bool)
+		for y := band; y < min(band+6, height); y++ {
+			for x := 0; x < width; x++ {
+				used[pixels[y*width+x]] = true
+			}
+		}
+		first := true
+		for c := 0; c < 216; c++ {
+			if !used[c] {
+				continue
+			}
+			if !first {
+				b.WriteString("$")
+			}
+			first = false
+			fmt.Fprintf(&b, "#%d", c)
+
+			// run length encoded sixels of this color in the band
+			last, count := byte(0), 0
+			flush := func() {
+				if count > 3 {
+					fmt.Fprintf(&b, "!%d%c", count, last)
+				} else {
+					b.WriteString(strings.Repeat(string(last), count))
+				}
+			}
+			for x := 0; x < width; x++ {
+				bits := 0
+				for dy := 0; dy < 6 && band+dy < height; dy++ {
+					if pixels[(band+dy)*width+x] == c {
+						bits |= 1 << dy
+					}
+				}
+				ch := byte(63 + bits)
+				if ch == last {
+					count++
+					continue
+				}
+				flush()
+				last, count = ch, 1
+			}
+			flush()
+		}
+		b.WriteString("-")
+	}
+	b.WriteString("\x1b\\")
+	return reserveCells(b.String(), cols, rows)
+}
@@ -0,0 +1,177 @@
+package gui
+
+import (
+	"errors"
+	"log"
+	"os"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/goccy/go-json"
+
+	"github.com/ani/ani-ar/config"
+)
+
+// keyMap holds every binding of the tui, the keys can be remapped in
+// `keys.json` in the config folder, eg. {"down": ["j", "down"], "quit": ["ctrl+c"]}
+type keyMap struct {
+	Up           key.Binding
+	Down         key.Binding
+	PageUp       key.Binding
+	PageDown     key.Binding
+	Home         key.Binding
+	End          key.Binding
+	Select       key.Binding
+	Back         key.Binding
+	Filter       key.Binding
+	Help         key.Binding
+	Quit         key.Binding
+	Cast         key.Binding
+	ToggleFiller key.Binding
+	Download     key.Binding
+	DownloadAll  key.Binding
+	SelectRange  key.Binding
+	Browse       key.Binding
+	NextTab      key.Binding
+	PrevTab      key.Binding
+	Watchlist    key.Binding
+	CycleStatus  key.Binding
+	Favorite     key.Binding
+	Remove       key.Binding
+	Profiles     key.Binding
+}
+
+func defaultKeyMap() *keyMap {
+	return &keyMap{
+		Up:           key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", tr("up"))),
+		Down:         key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", tr("down"))),
+		PageUp:       key.NewBinding(key.WithKeys("pgup", "ctrl+u"), key.WithHelp("pgup", tr("page up"))),
+		PageDown:     key.NewBinding(key.WithKeys("pgdown", "ctrl+d"), key.WithHelp("pgdn", tr("page down"))),
+		Home:         key.NewBinding(key.WithKeys("home", "g"), key.WithHelp("home/g", tr("first"))),
+		End:          key.NewBinding(key.WithKeys("end", "G"), key.WithHelp("end/G", tr("last"))),
+		Select:       key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", tr("select"))),
+		Back:         key.NewBinding(key.WithKeys("ctrl+b"), key.WithHelp("ctrl+b", tr("back"))),
+		Filter:       key.NewBinding(key.WithKeys("/"), key.WithHelp("/", tr("filter"))),
+		Help:         key.NewBinding(key.WithKeys("?"), key.WithHelp("?", tr("help"))),
+		Quit:         key.NewBinding(key.WithKeys("ctrl+c", "esc"), key.WithHelp("esc", tr("quit"))),
+		Cast:         key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("ctrl+t", tr("cast to a device"))),
+		ToggleFiller: key.NewBinding(key.WithKeys("ctrl+f"), key.WithHelp("ctrl+f", tr("hide/show filler"))),
+		Download:     key.NewBinding(key.WithKeys("d"), key.WithHelp("d", tr("download"))),
+		DownloadAll:  key.NewBinding(key.WithKeys("D"), key.WithHelp("D", tr("download all"))),
+		SelectRange:  key.NewBinding(key.WithKeys(" "), key.WithHelp("space", tr("select a range"))),
+		Browse:       key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", tr("browse seasonal and top anime"))),
+		NextTab:      key.NewBinding(key.WithKeys("tab", "right", "l"), key.WithHelp("tab/→", tr("next tab"))),
+		PrevTab:      key.NewBinding(key.WithKeys("shift+tab", "left", "h"), key.WithHelp("shift+tab/←", tr("previous tab"))),
+		Watchlist:    key.NewBinding(key.WithKeys("w"), key.WithHelp("w", tr("add to the watchlist"))),
+		CycleStatus:  key.NewBinding(key.WithKeys("s"), key.WithHelp("s", tr("change the status"))),
+		Favorite:     key.NewBinding(key.WithKeys("f"), key.WithHelp("f", tr("favorite"))),
+		Remove:       key.NewBinding(key.WithKeys("x"), key.WithHelp("x", tr("remove from the watchlist"))),
+		Profiles:     key.NewBinding(key.WithKeys("ctrl+p"), key.WithHelp("ctrl+p", tr("switch profile"))),
+	}
+}
+
+// the names used for the bindings in keys.json
+func (k *keyMap) byName() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":           &k.Up,
+		"down":         &k.Down,
+		"pageUp":       &k.PageUp,
+		"pageDown":     &k.PageDown,
+		"home":         &k.Home,
+		"end":          &k.End,
+		"select":       &k.Select,
+		"back":         &k.Back,
+		"filter":       &k.Filter,
+		"help":         &k.Help,
+		"quit":         &k.Quit,
+		"cast":         &k.Cast,
+		"toggleFiller": &k.ToggleFiller,
+		"download":     &k.Download,
+		"downloadAll":  &k.DownloadAll,
+		"selectRange":  &k.SelectRange,
+		"browse":       &k.Browse,
+		"nextTab":      &k.NextTab,
+		"prevTab":      &k.PrevTab,
+		"watchlist":    &k.Watchlist,
+		"cycleStatus":  &k.CycleStatus,
+		"favorite":     &k.Favorite,
+		"remove":       &k.Remove,
+		"profiles":     &k.Profiles,
+	}
+}
+
+// loadKeyMap returns the default bindings with the ones from keys.json applied,
+// a broken file is logged and ignored
+func loadKeyMap() *keyMap {
+	keys := defaultKeyMap()
+	b, err := os.ReadFile(config.Path("keys.json"))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Println("couldn't read keys.json, reason: " + err.Error())
+		}
+		return keys
+	}
+	var remapped map[string][]string
+	if err := json.Unmarshal(b, &remapped); err != nil {
+		log.Println("couldn't parse keys.json, reason: " + err.Error())
+		return keys
+	}
+
+	bindings := keys.byName()
+	for name, keysList := range remapped {
+		binding, ok := bindings[name]
+		if !ok || len(keysList) == 0 {
+			log.Printf("unknown key binding %q in keys.json\n", name)
+			continue
+		}
+		binding.SetKeys(keysList...)
+		binding.SetHelp(keysList[0], binding.Help().Desc)
+	}
+	return keys
+}
+
+// stageHelp is the help.KeyMap of a stage, short is shown under the list and
+// full in the `?` overlay
+type stageHelp struct {
+	short []key.Binding
+	full  [][]key.Binding
+}
+
+func (h stageHelp) ShortHelp() []key.Binding {
+	return h.short
+}
+
+func (h stageHelp) FullHelp() [][]key.Binding {
+	return h.full
+}
+
+func (k *keyMap) stageHelp(s stage) stageHelp {
+	navigation := []key.Binding{k.Up, k.Down, k.PageUp, k.PageDown, k.Home, k.End}
+	general := []key.Binding{k.Select, k.Filter, k.Back, k.Help, k.Quit}
+	switch s {
+	case stageSearch:
+		return stageHelp{
+			short: []key.Binding{k.Select, k.Browse, k.Profiles, k.Quit},
+			full:  [][]key.Binding{{k.Select, k.Browse, k.Profiles, k.Quit}},
+		}
+	case stageBrowse:
+		return stageHelp{
+			short: []key.Binding{k.Select, k.NextTab, k.PrevTab, k.Filter, k.Back, k.Help},
+			full:  [][]key.Binding{navigation, general, {k.NextTab, k.PrevTab}, {k.CycleStatus, k.Favorite, k.Remove}},
+		}
+	case stageEpisodes:
+		episodes := []key.Binding{k.Download, k.DownloadAll, k.SelectRange, k.Cast, k.ToggleFiller, k.Watchlist}
+		return stageHelp{
+			short: []key.Binding{k.Select, k.Download, k.SelectRange, k.Filter, k.Help},
+			full:  [][]key.Binding{navigation, general, episodes},
+		}
+	case stageResults:
+		return stageHelp{
+			short: []key.Binding{k.Select, k.Watchlist, k.Filter, k.Back, k.Help},
+			full:  [][]key.Binding{navigation, general, {k.Watchlist}},
+		}
+	}
+	return stageHelp{
+		short: []key.Binding{k.Select, k.Filter, k.Back, k.Help},
+		full:  [][]key.Binding{navigation, general},
+	}
+}
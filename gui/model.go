@@ -1,177 +1,607 @@
 package gui
 
 import (
-	"fmt"
-	"time"
+	"errors"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/ani/ani-ar/api"
+	"github.com/ani/ani-ar/cast"
+	"github.com/ani/ani-ar/download"
 	"github.com/ani/ani-ar/fetcher"
-	"github.com/ani/ani-ar/player"
+	"github.com/ani/ani-ar/profile"
 	"github.com/ani/ani-ar/types"
+	"github.com/ani/ani-ar/watchlist"
 )
 
 type AniModel struct {
 	textInput                textinput.Model
 	choicesModelAnimeList    *ChoicesModel
 	choicesModelAnimeEpisode *ChoicesModel
-	err                      error
-	// stage 0 is search anime ,
-	// stage 1 is selecting the anime from the list
-	// stage 2 is selecting an episode
-	stage   int
-	fetcher fetcher.Fetcher
-	info    string
+	choicesModelCastDevice   *ChoicesModel
+	choicesModelProfile      *ChoicesModel
+	details                  *detailsModel
+	browse                   *browseModel
+	downloads                *downloadsModel
+	keys                     *keyMap
+	help                     help.Model
+	showHelp                 bool
+	stage                    stage
+	// the stage going back from the episodes returns to
+	episodesFrom stage
+	// the search, episodes, devices, playback or lookup being waited for,
+	// going back cancels it
+	request *request
+	// the anime of the listed episodes and the fetcher they come from
+	episodesAnime   *types.AniResult
+	episodesFetcher fetcher.Fetcher
+	// the episode that will be sent to the selected cast device
+	castEpisode *types.AniEpisode
+	hideFiller  bool
+	// episode numbers selected with space, and the start of the range being selected
+	selectedEpisodes map[int]bool
+	selectionAnchor  int
+	fetcher          fetcher.Fetcher
+	// the terminal width, the arabic ui is aligned to its right edge
+	width int
+	// starts the player, replaced to drive the model without a player
+	runVideo func(video types.AniVideo, title string) error
+	// the status bar shows the last message or error
+	status    string
+	statusErr bool
 }
 
 func InitialModel() tea.Model {
+	applyTheme(loadTheme())
+	return newAniModel(fetcher.GetDefaultFetcher(), loadKeyMap())
+}
+
+// newAniModel returns the tui searching and listing the episodes with the fetcher
+func newAniModel(f fetcher.Fetcher, keys *keyMap) *AniModel {
 	ti := textinput.New()
 	ti.Placeholder = "Death note"
 	ti.Focus()
 	ti.Width = 50
 
+	selected := make(map[int]bool)
 	return &AniModel{
-		textInput:                ti,
-		err:                      nil,
-		choicesModelAnimeList:    initialChoicesModelForAnimeTitles(),
-		choicesModelAnimeEpisode: initialChoicesModelForAnimeEpisode(),
-		fetcher:                  fetcher.GetDefaultFetcher(),
-		stage:                    0,
+		textInput:             ti,
+		choicesModelAnimeList: initialChoicesModelForAnimeTitles(keys),
+		choicesModelAnimeEpisode: initialChoicesModelForAnimeEpisode(keys, func(number int) bool {
+			return selected[number]
+		}),
+		choicesModelCastDevice: initialChoicesModelForCastDevices(keys),
+		choicesModelProfile:    initialChoicesModelForProfiles(keys),
+		details:                newDetailsModel(f),
+		browse:                 newBrowseModel(keys),
+		downloads:              newDownloadsModel(f),
+		keys:                   keys,
+		help:                   help.New(),
+		request:                &request{},
+		selectedEpisodes:       selected,
+		fetcher:                f,
+		runVideo:               runVideo,
+		stage:                  stageSearch,
 	}
 }
 
 func (m AniModel) Init() tea.Cmd {
-	return tea.Batch(m.choicesModelAnimeList.Init(), m.choicesModelAnimeEpisode.Init())
+	return tea.Batch(
+		m.choicesModelAnimeList.Init(),
+		m.choicesModelAnimeEpisode.Init(),
+		m.choicesModelCastDevice.Init(),
+		m.choicesModelProfile.Init(),
+		m.browse.Init(),
+		m.downloads.waitForUpdate(),
+	)
+}
+
+func (m *AniModel) setStatus(message string) {
+	m.status, m.statusErr = message, false
+}
+
+func (m *AniModel) setError(message string, err error) {
+	m.status, m.statusErr = translate("%s, reason: %s", message, err.Error()), true
 }
 
 func (m AniModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	// the arabic ui is mirrored, the columns are counted from the right edge
+	if mouse, ok := msg.(tea.MouseMsg); ok && isRTLUI() {
+		mouse.X = m.width - 1 - mouse.X
+		msg = mouse
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyCtrlC, tea.KeyEsc:
+		if msg.Type == tea.KeyCtrlC {
+			m.request.stop()
 			return m, tea.Quit
-
-		case tea.KeyCtrlB:
-			if m.stage == 1 {
-				m.stage = 0
-			} else if m.stage == 2 {
-				m.stage = 1
-			}
-			updatedModel, _ := m.Update(nil)
-			m = updatedModel.(AniModel)
+		}
+		// any key closes the help
+		if m.showHelp {
+			m.showHelp = false
 			return m, cmd
-		case tea.KeyEnter:
-			if m.stage == 0 {
-				searchKey := m.textInput.Value()
-				// update stage
-				m.stage = 1
-				m.info = "fetching data..."
-				updatedModel, _ := m.Update(nil)
-				m = updatedModel.(AniModel)
-
-				choicesModel, c := m.choicesModelAnimeList.fetchChoices(func() []interface{} {
-					results := m.fetcher.Search(searchKey)
-					b := make([]interface{}, len(results))
-					for i := range results {
-						b[i] = results[i]
-					}
-					return b
-				}, searchKey)
-				m.info = ""
-				m.choicesModelAnimeList = choicesModel.(*ChoicesModel)
+		}
+		// while filtering the keys are typed in the filter input
+		if choices := m.activeChoices(); choices != nil && choices.filtering {
+			break
+		}
+		// the search input takes every other key
+		if m.stage == stageSearch && !key.Matches(msg, m.keys.Quit, m.keys.Select, m.keys.Browse, m.keys.Profiles) {
+			break
+		}
+		if m.stage == stageEpisodes {
+			if handled, c := m.handleDownloadKeys(msg); handled {
 				return m, c
 			}
-			if m.stage == 1 {
-				m.stage = 2
-				updatedModel, _ := m.Update(nil)
-				m = updatedModel.(AniModel)
-
-				// anime is selected let's fetch it's episodes
-				selectedAnime := m.choicesModelAnimeList.getSelectedChoice()
-				anime := selectedAnime.(types.AniResult)
-				newEpisodeModal, c := m.choicesModelAnimeEpisode.fetchChoices(func() []interface{} {
-					episodes := m.fetcher.GetEpisodes(anime)
-					b := make([]interface{}, len(episodes))
-					for i := range episodes {
-						b[i] = episodes[i]
-					}
-					return b
-				}, anime.DisplayName+" episodes")
-
-				m.choicesModelAnimeEpisode = newEpisodeModal.(*ChoicesModel)
+		}
+		if handled, c := m.handleWatchlistKeys(msg); handled {
+			return m, c
+		}
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			m.request.stop()
+			return m, tea.Quit
 
-				return m, c
+		case key.Matches(msg, m.keys.Help):
+			m.showHelp = true
+			return m, cmd
+
+		case m.stage == stageSearch && key.Matches(msg, m.keys.Profiles):
+			m.stage = stageProfiles
+			m.setStatus("")
+			m.choicesModelProfile.startLoading(translate("profiles"))
+			ctx, id := m.request.start()
+			return m, profilesCmd(ctx, id)
+		case m.stage == stageSearch && key.Matches(msg, m.keys.Browse):
+			m.stage = stageBrowse
+			return m, m.browse.show(m.browse.active)
+		case m.stage == stageBrowse && key.Matches(msg, m.keys.NextTab):
+			return m, m.browse.show(m.browse.active + 1)
+		case m.stage == stageBrowse && key.Matches(msg, m.keys.PrevTab):
+			return m, m.browse.show(m.browse.active - 1)
+
+		case key.Matches(msg, m.keys.Back):
+			m.back()
+			return m, cmd
+		case m.stage == stageEpisodes && key.Matches(msg, m.keys.Cast):
+			// pick a device to cast the highlighted episode to
+			selectedEpisode, ok := m.choicesModelAnimeEpisode.getHighlightedChoice()
+			if !ok {
+				break
 			}
-			if m.stage == 2 {
-				// play the episode
-				selectedEpisode := m.choicesModelAnimeEpisode.getSelectedChoice()
-				ep := selectedEpisode.(types.AniEpisode)
+			ep := selectedEpisode.(types.AniEpisode)
+			m.castEpisode = &ep
+			m.stage = stageCastDevices
+			m.setStatus("")
+			m.choicesModelCastDevice.startLoading(translate("cast devices"))
+			ctx, id := m.request.start()
+			return m, castDevicesCmd(ctx, id)
+		case m.stage == stageEpisodes && key.Matches(msg, m.keys.ToggleFiller):
+			m.hideFiller = !m.hideFiller
+			if m.hideFiller {
+				m.choicesModelAnimeEpisode.setHiddenFunc(isFillerEpisode)
+			} else {
+				m.choicesModelAnimeEpisode.setHiddenFunc(nil)
+			}
+			return m, cmd
+		case key.Matches(msg, m.keys.Select):
+			return m.selectChoice()
+		}
+
+	case tea.MouseMsg:
+		// clicking the highlighted choice selects it
+		choices := m.activeChoices()
+		if choices == nil || choices.filtering || msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+			break
+		}
+		choiceAt := choices.choiceAt
+		if m.stage == stageBrowse {
+			choiceAt = m.browse.choiceAt
+		}
+		if i, ok := choiceAt(msg.X, msg.Y); ok && i == choices.cursor {
+			return m.selectChoice()
+		}
+
+	case spinner.TickMsg:
+		// every list keeps its spinner running
+		_, c1 := m.choicesModelAnimeList.Update(msg)
+		_, c2 := m.choicesModelAnimeEpisode.Update(msg)
+		_, c3 := m.choicesModelCastDevice.Update(msg)
+		_, c4 := m.choicesModelProfile.Update(msg)
+		return m, tea.Batch(c1, c2, c3, c4, m.browse.Update(msg))
+	case tea.WindowSizeMsg:
+		// the anime list shares the width with the details pane
+		listSize := msg
+		listSize.Width = max(msg.Width-detailsPaneWidth-2, 20)
+		m.help.Width = msg.Width
+		m.width = msg.Width
+		m.choicesModelAnimeList.Update(listSize)
+		m.choicesModelAnimeEpisode.Update(msg)
+		m.choicesModelCastDevice.Update(msg)
+		m.choicesModelProfile.Update(msg)
+		m.browse.Update(msg)
+		return m, nil
+	case DetailsRequestedEvent, DetailsLoadedEvent:
+		return m, m.details.Update(msg)
+	case BrowseLoadedEvent:
+		if msg.err != nil {
+			m.setError(translate("couldn't load %s", m.browse.tabs[msg.tab].title), msg.err)
+		}
+		return m, m.browse.Update(msg)
+	case ChoicesLoadedEvent:
+		return m.choicesLoaded(msg)
+	case EpisodeStartedEvent:
+		if !m.request.isCurrent(msg.requestId) {
+			return m, nil
+		}
+		m.request.done()
+		m.choicesModelAnimeEpisode.loading = false
+		title := episodeTitle(msg.episode)
+		if msg.err != nil {
+			m.setError(translate("couldn't play %s", title), msg.err)
+			return m, nil
+		}
+		if msg.device != "" {
+			m.setStatus(translate("casting %s to %s", title, msg.device))
+		} else {
+			m.setStatus(translate("playing %s", title))
+		}
+		m.choicesModelAnimeEpisode.refreshContent()
+		return m, nil
+	case BrowseMatchedEvent:
+		if !m.request.isCurrent(msg.requestId) {
+			return m, nil
+		}
+		m.request.done()
+		if msg.anime == nil {
+			m.setError(translate("couldn't open %s", msg.title), errors.New(translate("it wasn't found in the source, try searching for it")))
+			return m, nil
+		}
+		m.setStatus("")
+		return m.showEpisodes(*msg.anime, msg.fetcher, stageBrowse)
+	case WatchlistUpdatedEvent:
+		if msg.err != nil {
+			m.setError(translate("couldn't update the watchlist"), msg.err)
+		} else {
+			m.setStatus(msg.message)
+		}
+		if m.stage == stageBrowse && m.browse.isWatchlistShown() {
+			// the tab is loaded again with the change
+			return m, m.browse.show(m.browse.active)
+		}
+		return m, nil
+	case DownloadUpdatedEvent:
+		// the downloaded marker shows up once the job is done
+		if msg.job.Status == download.JobDone {
+			m.choicesModelAnimeEpisode.refreshContent()
+		}
+		return m, m.downloads.waitForUpdate()
+	case error:
+		m.setError(translate("something went wrong"), msg)
+		return m, nil
+	}
 
-				epUrl := ep.GetPlayerUrl()
+	switch m.stage {
+	case stageSearch:
+		m.textInput, cmd = m.textInput.Update(msg)
+	case stageResults:
+		m.choicesModelAnimeList.Update(msg)
+		cmd = m.highlightDetails()
+	case stageEpisodes:
+		_, cmd = m.choicesModelAnimeEpisode.Update(msg)
+	case stageCastDevices:
+		_, cmd = m.choicesModelCastDevice.Update(msg)
+	case stageProfiles:
+		_, cmd = m.choicesModelProfile.Update(msg)
+	case stageBrowse:
+		cmd = m.browse.Update(msg)
+	}
+	return m, cmd
+}
 
-				title := fmt.Sprintf("%s - episode %v", ep.Anime.DisplayName, ep.Number)
-				_, err := player.RunVideo(epUrl, title)
-				m.choicesModelAnimeEpisode.loading = true
-				m.choicesModelAnimeEpisode.Update(msg)
+// back cancels the running request and returns to the previous stage
+func (m *AniModel) back() {
+	m.request.stop()
+	m.setStatus("")
+	m.choicesModelAnimeEpisode.loading = false
+	if m.stage == stageCastDevices {
+		m.castEpisode = nil
+	}
+	m.stage = m.stage.previous(m.episodesFrom)
+}
 
-				go func() {
-					time.Sleep(time.Second * 1)
-					m.choicesModelAnimeEpisode.loading = false
-					m.choicesModelAnimeEpisode.Update(msg)
-				}()
+// highlightDetails follows the cursor of the search results with the details pane
+func (m *AniModel) highlightDetails() tea.Cmd {
+	var highlighted *types.AniResult
+	if choice, ok := m.choicesModelAnimeList.getHighlightedChoice(); ok {
+		anime := choice.(types.AniResult)
+		highlighted = &anime
+	}
+	return m.details.highlight(highlighted)
+}
 
-				if err != nil {
-					fmt.Printf("error playing the episode %s", err)
-				}
+// choicesLoaded shows the results of the search, the episodes or the cast
+// devices in their list, the results of a cancelled request are dropped
+func (m AniModel) choicesLoaded(msg ChoicesLoadedEvent) (tea.Model, tea.Cmd) {
+	if !m.request.isCurrent(msg.requestId) {
+		return m, nil
+	}
+	m.request.done()
+
+	var choices *ChoicesModel
+	switch msg.stage {
+	case stageResults:
+		choices = m.choicesModelAnimeList
+	case stageEpisodes:
+		choices = m.choicesModelAnimeEpisode
+	case stageCastDevices:
+		choices = m.choicesModelCastDevice
+	case stageProfiles:
+		choices = m.choicesModelProfile
+	default:
+		return m, nil
+	}
+	choices.Update(newChoicesShownEvent(msg.results))
+	if msg.err != nil {
+		m.setError(translate("couldn't load %s", choices.searchKey), msg.err)
+	}
+	if msg.stage == stageResults {
+		return m, m.highlightDetails()
+	}
+	return m, nil
+}
 
-			}
+// selectChoice acts on the highlighted choice of the stage, it searches on the
+// search stage, lists the episodes of an anime, plays an episode or casts it
+func (m AniModel) selectChoice() (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch m.stage {
+	case stageSearch:
+		searchKey := m.textInput.Value()
+		if searchKey == "" {
+			return m, cmd
+		}
+		m.stage = stageResults
+		m.setStatus("")
+		m.choicesModelAnimeList.startLoading(searchKey)
+		ctx, id := m.request.start()
+		return m, searchCmd(ctx, id, m.fetcher, searchKey)
+
+	case stageResults:
+		// anime is selected let's fetch it's episodes
+		selectedAnime, ok := m.choicesModelAnimeList.getHighlightedChoice()
+		if !ok {
 			return m, cmd
 		}
+		return m.showEpisodes(selectedAnime.(types.AniResult), m.fetcher, stageResults)
 
-	case spinner.TickMsg:
-		// send the tick message to the two choice lists
-		m1, c1 := m.choicesModelAnimeList.Update(msg)
-		m.choicesModelAnimeList = m1.(*ChoicesModel)
-		m2, c2 := m.choicesModelAnimeEpisode.Update(msg)
-		m.choicesModelAnimeEpisode = m2.(*ChoicesModel)
-		return m, tea.Batch(c1, c2)
-	case error:
-		m.err = msg
+	case stageBrowse:
+		// the anime is looked up in the fetcher before listing its episodes
+		selected, ok := m.browse.list().getHighlightedChoice()
+		if !ok {
+			return m, cmd
+		}
+		title := ""
+		switch selected := selected.(type) {
+		case watchlist.Entry:
+			title = selected.Title
+		case *api.JikanAnimeInfo:
+			title = selected.Title
+		}
+		m.setStatus(translate("looking %s up in the source...", title))
+		ctx, id := m.request.start()
+		return m, matchCmd(ctx, id, m.fetcher, selected)
+
+	case stageEpisodes:
+		selectedEpisode, ok := m.choicesModelAnimeEpisode.getHighlightedChoice()
+		if !ok {
+			return m, cmd
+		}
+		ep := selectedEpisode.(types.AniEpisode)
+		m.setStatus(translate("starting %s...", episodeTitle(ep)))
+		m.choicesModelAnimeEpisode.loading = true
+		ctx, id := m.request.start()
+		return m, playCmd(ctx, id, ep, m.runVideo)
+
+	case stageCastDevices:
+		choice, ok := m.choicesModelCastDevice.getHighlightedChoice()
+		if !ok || m.castEpisode == nil {
+			return m, cmd
+		}
+		device := choice.(*cast.Device)
+		ep := *m.castEpisode
+		m.castEpisode = nil
+		m.stage = stageEpisodes
+		m.setStatus(translate("casting %s to %s...", episodeTitle(ep), device.Name))
+		m.choicesModelAnimeEpisode.loading = true
+		ctx, id := m.request.start()
+		return m, castCmd(ctx, id, m.episodesFetcher, ep, device)
+
+	case stageProfiles:
+		choice, ok := m.choicesModelProfile.getHighlightedChoice()
+		if !ok {
+			return m, cmd
+		}
+		return m.switchProfile(choice.(string))
+	}
+	return m, cmd
+}
+
+// switchProfile makes the profile active, its settings pick the fetcher and
+// the history and watchlist are read from it
+func (m AniModel) switchProfile(name string) (tea.Model, tea.Cmd) {
+	if err := profile.SetActive(name); err != nil {
+		m.setError(translate("couldn't load %s", name), err)
 		return m, nil
 	}
+	m.fetcher = fetcher.GetDefaultFetcher()
+	m.details = newDetailsModel(m.fetcher)
+	m.downloads.switchFetcher(m.fetcher)
+	m.stage = stageSearch
+	m.setStatus(translate("switched to the %s profile", name))
+	return m, nil
+}
+
+// showEpisodes moves to the episodes stage and fetches the episodes of the
+// anime, from is the stage going back returns to
+func (m AniModel) showEpisodes(anime types.AniResult, f fetcher.Fetcher, from stage) (tea.Model, tea.Cmd) {
+	m.stage = stageEpisodes
+	m.episodesFrom = from
+	m.episodesAnime = &anime
+	m.episodesFetcher = f
+	m.clearSelection()
+	m.choicesModelAnimeEpisode.startLoading(translate("%s episodes", anime.DisplayName))
+	ctx, id := m.request.start()
+	return m, episodesCmd(ctx, id, f, anime)
+}
 
-	// only recieve updates for the main modal input when stage is 0 (searchin anime)
-	if m.stage == 0 {
-		m.textInput, _ = m.textInput.Update(msg)
+// handles the watchlist keys, the highlighted anime of the search results or
+// the anime of the episodes is added, the watchlist tab can change its entries
+func (m *AniModel) handleWatchlistKeys(msg tea.KeyMsg) (bool, tea.Cmd) {
+	if key.Matches(msg, m.keys.Watchlist) && (m.stage == stageResults || m.stage == stageEpisodes) {
+		var anime types.AniResult
+		f := m.fetcher
+		if m.stage == stageResults {
+			choice, ok := m.choicesModelAnimeList.getHighlightedChoice()
+			if !ok {
+				return true, nil
+			}
+			anime = choice.(types.AniResult)
+		} else {
+			if m.episodesAnime == nil {
+				return true, nil
+			}
+			anime, f = *m.episodesAnime, m.episodesFetcher
+		}
+		m.setStatus(translate("adding %s to the watchlist...", anime.DisplayName))
+		return true, addToWatchlistCmd(f, anime)
 	}
 
-	// only recieve updates for choices modal for anime titles when stage is 1 (selecting the anime from the list)
-	if m.stage == 1 {
-		newChoicesModel, _ := m.choicesModelAnimeList.Update(msg)
-		m.choicesModelAnimeList = newChoicesModel.(*ChoicesModel)
+	if m.stage != stageBrowse || !m.browse.isWatchlistShown() {
+		return false, nil
+	}
+	choice, ok := m.browse.list().getHighlightedChoice()
+	if !ok {
+		return false, nil
+	}
+	entry := choice.(watchlist.Entry)
+	switch {
+	case key.Matches(msg, m.keys.CycleStatus):
+		return true, cycleStatusCmd(entry)
+	case key.Matches(msg, m.keys.Favorite):
+		return true, toggleFavoriteCmd(entry)
+	case key.Matches(msg, m.keys.Remove):
+		return true, removeFromWatchlistCmd(entry)
 	}
+	return false, nil
+}
 
-	// only recieve updates for choices modal for anime episodes when stage is 2 (selecting an episode)
-	if m.stage == 2 {
-		newChoicesModel, _ := m.choicesModelAnimeEpisode.Update(msg)
-		m.choicesModelAnimeEpisode = newChoicesModel.(*ChoicesModel)
+// returns the choices list of the current stage, nil on the search stage
+func (m AniModel) activeChoices() *ChoicesModel {
+	switch m.stage {
+	case stageResults:
+		return m.choicesModelAnimeList
+	case stageEpisodes:
+		return m.choicesModelAnimeEpisode
+	case stageCastDevices:
+		return m.choicesModelCastDevice
+	case stageProfiles:
+		return m.choicesModelProfile
+	case stageBrowse:
+		return m.browse.list()
 	}
+	return nil
+}
 
-	return m, cmd
+// handles the download keys of the episodes stage, download gets the selected
+// episodes or the highlighted one, download all every listed episode
+func (m *AniModel) handleDownloadKeys(msg tea.KeyMsg) (bool, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Download):
+		var episodes []types.AniEpisode
+		for _, choice := range m.choicesModelAnimeEpisode.getFilteredChoices(m.choicesModelAnimeEpisode.choices) {
+			ep := choice.(types.AniEpisode)
+			if m.selectedEpisodes[ep.Number] {
+				episodes = append(episodes, ep)
+			}
+		}
+		if len(episodes) == 0 {
+			choice, ok := m.choicesModelAnimeEpisode.getHighlightedChoice()
+			if !ok {
+				return true, nil
+			}
+			episodes = append(episodes, choice.(types.AniEpisode))
+		}
+		if err := m.downloads.queue.Add(episodes...); err != nil {
+			m.setError(translate("couldn't queue the downloads"), err)
+		}
+		m.clearSelection()
+		m.choicesModelAnimeEpisode.refreshContent()
+		return true, nil
+	case key.Matches(msg, m.keys.DownloadAll):
+		var episodes []types.AniEpisode
+		for _, choice := range m.choicesModelAnimeEpisode.getFilteredChoices(m.choicesModelAnimeEpisode.choices) {
+			episodes = append(episodes, choice.(types.AniEpisode))
+		}
+		if err := m.downloads.queue.Add(episodes...); err != nil {
+			m.setError(translate("couldn't queue the downloads"), err)
+		}
+		m.clearSelection()
+		m.choicesModelAnimeEpisode.refreshContent()
+		return true, nil
+	case key.Matches(msg, m.keys.SelectRange):
+		choice, ok := m.choicesModelAnimeEpisode.getHighlightedChoice()
+		if !ok {
+			return true, nil
+		}
+		m.selectRange(choice.(types.AniEpisode).Number)
+		m.choicesModelAnimeEpisode.refreshContent()
+		return true, nil
+	}
+	return false, nil
 }
 
-func renderANewLine(msg string, highlight bool) string {
-	highlightText := lipgloss.NewStyle().TabWidth(-1).Foreground(lipgloss.Color("#2c70b0"))
-	normalText := lipgloss.NewStyle().TabWidth(-1).Foreground(lipgloss.Color("#f5f3f2"))
+// the first space starts a range on the highlighted episode, the second one
+// selects every listed episode between the two, a selected episode is unselected
+func (m *AniModel) selectRange(number int) {
+	if m.selectionAnchor == 0 {
+		if m.selectedEpisodes[number] {
+			delete(m.selectedEpisodes, number)
+			return
+		}
+		m.selectedEpisodes[number] = true
+		m.selectionAnchor = number
+		return
+	}
+	from, to := min(m.selectionAnchor, number), max(m.selectionAnchor, number)
+	for _, choice := range m.choicesModelAnimeEpisode.getFilteredChoices(m.choicesModelAnimeEpisode.choices) {
+		ep := choice.(types.AniEpisode)
+		if ep.Number >= from && ep.Number <= to {
+			m.selectedEpisodes[ep.Number] = true
+		}
+	}
+	m.selectionAnchor = 0
+}
 
-	styledText := normalText.Render(msg)
+func (m *AniModel) clearSelection() {
+	for number := range m.selectedEpisodes {
+		delete(m.selectedEpisodes, number)
+	}
+	m.selectionAnchor = 0
+}
+
+func renderANewLine(msg string, highlight bool) string {
+	styledText := normalTextStyle.Render(msg)
 	if highlight {
-		styledText = highlightText.Render(msg)
+		styledText = highlightTextStyle.Render(msg)
 	}
 
 	// Align text if needed
@@ -179,22 +609,58 @@ func renderANewLine(msg string, highlight bool) string {
 }
 
 func (m AniModel) View() string {
-	msg := ""
-	// msg += m.info
-	// msg += "\n"
+	if m.showHelp {
+		return m.mirror(renderANewLine(tr("Keybindings"), true) + "\n\n" +
+			m.help.FullHelpView(m.keys.stageHelp(m.stage).FullHelp()) + "\n\n" +
+			renderANewLine(tr("press any key to close"), false))
+	}
 
-	if m.stage == 0 {
-		msg += renderANewLine("Search anime ", true)
-		msg += m.textInput.View()
+	msg := ""
+	switch m.stage {
+	case stageSearch:
+		msg += joinRow(" ", renderANewLine(tr("Search anime"), true), m.textInput.View())
+		msg += "\n" + mutedStyle.Render(tr("profile: %s", profile.Active()))
+	case stageResults:
+		// the details pane is on the side the rows end
+		panes := []string{m.choicesModelAnimeList.View(), m.details.View()}
+		if isRTLUI() {
+			panes[0], panes[1] = panes[1], panes[0]
+		}
+		msg += lipgloss.JoinHorizontal(lipgloss.Top, panes...)
+	case stageEpisodes:
+		msg += m.choicesModelAnimeEpisode.View()
+		msg += "\n" + renderANewLine(tr("✓ watched • ↓ downloaded"), false)
+	case stageCastDevices:
+		msg += m.choicesModelCastDevice.View()
+	case stageProfiles:
+		msg += m.choicesModelProfile.View()
+	case stageBrowse:
+		msg += m.browse.View()
 	}
 
-	if m.stage == 1 {
-		msg += m.choicesModelAnimeList.View()
+	if m.status != "" {
+		status := bidiText(m.status, isRTLUI())
+		if m.statusErr {
+			msg += "\n" + statusErrorStyle.Render(status)
+		} else {
+			msg += "\n" + statusStyle.Render(status)
+		}
 	}
+	msg += "\n" + m.help.View(m.keys.stageHelp(m.stage))
 
-	if m.stage == 2 {
-		msg += m.choicesModelAnimeEpisode.View()
+	if m.stage != stageSearch {
+		if downloads := m.downloads.View(); downloads != "" {
+			msg += "\n\n" + downloads
+		}
 	}
 
-	return msg
+	return m.mirror(msg)
+}
+
+// mirror aligns the view to the right edge of the terminal in the arabic ui
+func (m AniModel) mirror(view string) string {
+	if !isRTLUI() || m.width == 0 {
+		return view
+	}
+	return lipgloss.NewStyle().Width(m.width).Align(lipgloss.Right).Render(view)
 }
@@ -0,0 +1,273 @@
+package gui
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ani/ani-ar/history"
+	"github.com/ani/ani-ar/profile"
+	"github.com/ani/ani-ar/types"
+)
+
+// fakeFetcher returns the same anime for every search
+type fakeFetcher struct {
+	searched []string
+	results  []types.AniResult
+}
+
+func (f *fakeFetcher) Search(q string) []types.AniResult {
+	f.searched = append(f.searched, q)
+	return f.results
+}
+
+func (f *fakeFetcher) GetAnimeResult(id string) *types.AniResult {
+	for i := range f.results {
+		if f.results[i].Id == id {
+			return &f.results[i]
+		}
+	}
+	return nil
+}
+
+func (f *fakeFetcher) GetEpisodes(anime types.AniResult) []types.AniEpisode {
+	return nil
+}
+
+// playedVideo records the videos runVideo was called with
+type playedVideo struct {
+	video types.AniVideo
+	title string
+}
+
+func newTestModel(t *testing.T, f *fakeFetcher, played *[]playedVideo, playErr error) AniModel {
+	t.Helper()
+	t.Setenv("ANI_AR_CONFIG_DIR", t.TempDir())
+	m := newAniModel(f, defaultKeyMap())
+	m.runVideo = func(video types.AniVideo, title string) error {
+		*played = append(*played, playedVideo{video, title})
+		return playErr
+	}
+	return *m
+}
+
+func update(t *testing.T, m AniModel, msg tea.Msg) (AniModel, tea.Cmd) {
+	t.Helper()
+	next, cmd := m.Update(msg)
+	return next.(AniModel), cmd
+}
+
+func typeText(t *testing.T, m AniModel, text string) AniModel {
+	t.Helper()
+	for _, r := range text {
+		m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return m
+}
+
+func testEpisodes(anime types.AniResult) []interface{} {
+	var episodes []interface{}
+	for number := 1; number <= 3; number++ {
+		episodes = append(episodes, types.AniEpisode{
+			Anime:  anime,
+			Number: number,
+			GetPlayersWithQuality: func() []types.AniVideo {
+				return []types.AniVideo{{Src: "http://video/" + strconv.Itoa(number), Res: "1080", Headers: map[string]string{"Referer": "http://host"}}}
+			},
+		})
+	}
+	return episodes
+}
+
+// showTestEpisodes lists the test episodes of anime as if they were loaded
+func showTestEpisodes(t *testing.T, m AniModel, anime types.AniResult) AniModel {
+	t.Helper()
+	next, _ := m.showEpisodes(anime, m.fetcher, stageResults)
+	m = next.(AniModel)
+	m, _ = update(t, m, newChoicesLoadedEvent(m.request.id, stageEpisodes, testEpisodes(anime), nil))
+	return m
+}
+
+func TestSearchAndPlay(t *testing.T) {
+	anime := types.AniResult{Id: "death-note", DisplayName: "Death Note"}
+	f := &fakeFetcher{results: []types.AniResult{anime}}
+	var played []playedVideo
+	m := newTestModel(t, f, &played, nil)
+
+	m, cmd := update(t, m, tea.WindowSizeMsg{Width: 120, Height: 40})
+	if cmd != nil || m.width != 120 {
+		t.Fatalf("the window size should be applied, width %d", m.width)
+	}
+
+	m = typeText(t, m, "death")
+	if m.textInput.Value() != "death" {
+		t.Fatalf("the search input has %q", m.textInput.Value())
+	}
+	m, cmd = update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.stage != stageResults || cmd == nil {
+		t.Fatalf("enter should search, stage %v", m.stage)
+	}
+	loaded, ok := cmd().(ChoicesLoadedEvent)
+	if !ok || len(f.searched) != 1 || f.searched[0] != "death" {
+		t.Fatalf("the search command should search the fetcher, searched %v", f.searched)
+	}
+
+	m, cmd = update(t, m, loaded)
+	if choice, ok := m.choicesModelAnimeList.getHighlightedChoice(); !ok || choice.(types.AniResult) != anime {
+		t.Fatalf("the result should be highlighted, got %v", choice)
+	}
+	if cmd == nil {
+		t.Fatal("the details of the highlighted anime should be requested")
+	}
+
+	m, cmd = update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.stage != stageEpisodes || m.episodesAnime == nil || m.episodesAnime.Id != anime.Id || cmd == nil {
+		t.Fatalf("enter should list the episodes, stage %v", m.stage)
+	}
+	// the episodes are loaded by hand, the command asks jikan for their details
+	m, _ = update(t, m, newChoicesLoadedEvent(m.request.id, stageEpisodes, testEpisodes(anime), nil))
+	m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	if choice, _ := m.choicesModelAnimeEpisode.getHighlightedChoice(); choice.(types.AniEpisode).Number != 2 {
+		t.Fatalf("down should highlight the second episode, got %v", choice)
+	}
+
+	m, cmd = update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.choicesModelAnimeEpisode.loading || cmd == nil {
+		t.Fatal("enter should start the player")
+	}
+	started, ok := cmd().(EpisodeStartedEvent)
+	if !ok || started.err != nil {
+		t.Fatalf("the episode should start, got %+v", started)
+	}
+	if len(played) != 1 || played[0].video.Src != "http://video/2" || played[0].video.Headers["Referer"] != "http://host" {
+		t.Fatalf("the player should get the video with its headers, got %+v", played)
+	}
+	m, _ = update(t, m, started)
+	if m.choicesModelAnimeEpisode.loading || m.statusErr || m.status != translate("playing %s", episodeTitle(started.episode)) {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+
+	m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyCtrlB})
+	if m.stage != stageResults {
+		t.Fatalf("back should return to the results, stage %v", m.stage)
+	}
+	_, cmd = update(t, m, tea.KeyMsg{Type: tea.KeyCtrlC})
+	if cmd == nil {
+		t.Fatal("ctrl+c should quit")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Fatal("ctrl+c should quit")
+	}
+}
+
+func TestPlayerError(t *testing.T) {
+	anime := types.AniResult{Id: "death-note", DisplayName: "Death Note"}
+	var played []playedVideo
+	m := newTestModel(t, &fakeFetcher{}, &played, errors.New("mpv not found"))
+	m = showTestEpisodes(t, m, anime)
+
+	m, cmd := update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	started := cmd().(EpisodeStartedEvent)
+	m, _ = update(t, m, started)
+	if !m.statusErr || len(played) != 1 {
+		t.Fatalf("the player error should be shown, status %q", m.status)
+	}
+}
+
+func TestCancelledEpisodeIsNotPlayed(t *testing.T) {
+	anime := types.AniResult{Id: "death-note", DisplayName: "Death Note"}
+	var played []playedVideo
+	m := newTestModel(t, &fakeFetcher{}, &played, nil)
+	started := make(chan types.AniVideo, 1)
+	m.runVideo = func(video types.AniVideo, title string) error {
+		started <- video
+		return nil
+	}
+	// the video of the episode loads until the test releases it
+	release := make(chan struct{})
+	episode := types.AniEpisode{
+		Anime:  anime,
+		Number: 1,
+		GetPlayersWithQuality: func() []types.AniVideo {
+			<-release
+			return []types.AniVideo{{Src: "http://video/1", Res: "1080"}}
+		},
+	}
+	next, _ := m.showEpisodes(anime, m.fetcher, stageResults)
+	m = next.(AniModel)
+	m, _ = update(t, m, newChoicesLoadedEvent(m.request.id, stageEpisodes, []interface{}{episode}, nil))
+
+	m, cmd := update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter should start the player")
+	}
+	msg := make(chan tea.Msg)
+	go func() { msg <- cmd() }()
+	// going back while the video is loading cancels the player
+	m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyCtrlB})
+	if got := <-msg; got != nil {
+		t.Fatalf("the cancelled request shouldn't return a message, got %+v", got)
+	}
+	close(release)
+	select {
+	case video := <-started:
+		t.Fatalf("the player shouldn't start after back, got %+v", video)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if history.GetStore().IsWatched(anime.Id, 1) {
+		t.Fatal("the cancelled episode shouldn't be marked watched")
+	}
+}
+
+func TestStaleResultsAreDropped(t *testing.T) {
+	f := &fakeFetcher{results: []types.AniResult{{Id: "bleach", DisplayName: "Bleach"}}}
+	var played []playedVideo
+	m := newTestModel(t, f, &played, nil)
+
+	m = typeText(t, m, "bleach")
+	m, cmd := update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	loaded := cmd().(ChoicesLoadedEvent)
+	// going back cancels the search
+	m, _ = update(t, m, tea.KeyMsg{Type: tea.KeyCtrlB})
+	if m.stage != stageSearch {
+		t.Fatalf("back should return to the search, stage %v", m.stage)
+	}
+	m, cmd = update(t, m, loaded)
+	if cmd != nil || len(m.choicesModelAnimeList.choices) != 0 {
+		t.Fatal("the results of the cancelled search should be dropped")
+	}
+}
+
+func TestSearchWithoutResults(t *testing.T) {
+	var played []playedVideo
+	m := newTestModel(t, &fakeFetcher{}, &played, nil)
+
+	m = typeText(t, m, "nothing")
+	m, cmd := update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = update(t, m, cmd())
+	if !m.statusErr || m.choicesModelAnimeList.loading {
+		t.Fatalf("the empty search should be an error, status %q", m.status)
+	}
+}
+
+func TestSwitchProfileRebuildsTheDownloads(t *testing.T) {
+	var played []playedVideo
+	m := newTestModel(t, &fakeFetcher{}, &played, nil)
+	if err := profile.Create("kids"); err != nil {
+		t.Fatal(err)
+	}
+	defer profile.SetActive(profile.Default)
+	queue, updates := m.downloads.queue, m.downloads.updates
+
+	next, _ := m.switchProfile("kids")
+	m = next.(AniModel)
+	if m.downloads.queue == queue {
+		t.Fatal("the download queue should be rebuilt with the fetcher of the profile")
+	}
+	if m.downloads.updates != updates {
+		t.Fatal("the download updates should keep their channel")
+	}
+}
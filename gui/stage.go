@@ -0,0 +1,70 @@
+package gui
+
+import "context"
+
+// stage is a state of the tui, every stage shows one view and the keys act on it
+type stage int
+
+const (
+	// typing the anime to search for
+	stageSearch stage = iota
+	// selecting the anime from the search results
+	stageResults
+	// selecting an episode of the anime
+	stageEpisodes
+	// selecting a device to cast the episode to
+	stageCastDevices
+	// browsing the seasonal and top anime and the watchlist
+	stageBrowse
+	// picking the profile
+	stageProfiles
+)
+
+// previous returns the stage going back from s leads to, from is the stage
+// the episodes were opened from
+func (s stage) previous(from stage) stage {
+	switch s {
+	case stageEpisodes:
+		return from
+	case stageCastDevices:
+		return stageEpisodes
+	}
+	return stageSearch
+}
+
+// request tracks the running I/O started by a stage, every new request gets a
+// new id and the results of any other id are stale and dropped
+type request struct {
+	id     int
+	cancel context.CancelFunc
+}
+
+// start cancels the running request and returns the context of a new one
+func (r *request) start() (context.Context, int) {
+	r.stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	return ctx, r.id
+}
+
+// stop cancels the running request, its result won't be applied anymore
+func (r *request) stop() {
+	if r.cancel != nil {
+		r.cancel()
+		r.cancel = nil
+	}
+	r.id++
+}
+
+// isCurrent reports whether the result of the request id should still be applied
+func (r *request) isCurrent(id int) bool {
+	return r.cancel != nil && r.id == id
+}
+
+// done marks the request as finished once its result is applied
+func (r *request) done() {
+	if r.cancel != nil {
+		r.cancel()
+		r.cancel = nil
+	}
+}
@@ -0,0 +1,125 @@
+package gui
+
+import (
+	"errors"
+	"log"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/goccy/go-json"
+
+	"github.com/ani/ani-ar/config"
+)
+
+// theme holds the colors and the border of the tui, it can be changed in
+// `theme.json` in the config folder, eg. {"primary": "#ff5f87", "border": "rounded"},
+// the missing fields keep their default
+type theme struct {
+	// titles, the active tab and highlighted lines
+	Primary string `json:"primary"`
+	Text    string `json:"text"`
+	// hints, the status bar and the inactive tabs
+	Muted    string `json:"muted"`
+	Error    string `json:"error"`
+	Markers  string `json:"markers"`
+	Selected string `json:"selected"`
+	Filler   string `json:"filler"`
+	Recap    string `json:"recap"`
+	Spinner  string `json:"spinner"`
+	// the border of the details pane: normal, rounded, thick, double or hidden
+	Border string `json:"border"`
+}
+
+func defaultTheme() theme {
+	return theme{
+		Primary:  "#2c70b0",
+		Text:     "#f5f3f2",
+		Muted:    "#626262",
+		Error:    "#d75f5f",
+		Markers:  "#5faf5f",
+		Selected: "#d7af00",
+		Filler:   "#d7875f",
+		Recap:    "#af87d7",
+		Spinner:  "205",
+		Border:   "normal",
+	}
+}
+
+var borders = map[string]lipgloss.Border{
+	"normal":  lipgloss.NormalBorder(),
+	"rounded": lipgloss.RoundedBorder(),
+	"thick":   lipgloss.ThickBorder(),
+	"double":  lipgloss.DoubleBorder(),
+	"hidden":  lipgloss.HiddenBorder(),
+}
+
+// the styles of the tui, set from the theme by applyTheme
+var (
+	highlightTextStyle  lipgloss.Style
+	normalTextStyle     lipgloss.Style
+	titleStyle          lipgloss.Style
+	mutedStyle          lipgloss.Style
+	spinnerStyle        lipgloss.Style
+	fillerStyle         lipgloss.Style
+	recapStyle          lipgloss.Style
+	markersStyle        lipgloss.Style
+	selectedStyle       lipgloss.Style
+	activeTabStyle      lipgloss.Style
+	inactiveTabStyle    lipgloss.Style
+	downloadsTitleStyle lipgloss.Style
+	downloadFailedStyle lipgloss.Style
+	statusStyle         lipgloss.Style
+	statusErrorStyle    lipgloss.Style
+	paneBorder          lipgloss.Border
+	paneBorderColor     lipgloss.Color
+)
+
+func init() {
+	applyTheme(defaultTheme())
+}
+
+func applyTheme(t theme) {
+	color := func(c string) lipgloss.Style {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(c))
+	}
+	highlightTextStyle = color(t.Primary).TabWidth(-1)
+	normalTextStyle = color(t.Text).TabWidth(-1)
+	titleStyle = color(t.Primary).Bold(true)
+	mutedStyle = color(t.Muted)
+	spinnerStyle = color(t.Spinner)
+	fillerStyle = color(t.Filler)
+	recapStyle = color(t.Recap)
+	markersStyle = color(t.Markers)
+	selectedStyle = color(t.Selected)
+	activeTabStyle = color(t.Primary).Bold(true).Underline(true)
+	inactiveTabStyle = color(t.Muted)
+	downloadsTitleStyle = color(t.Primary).Bold(true)
+	downloadFailedStyle = color(t.Error)
+	statusStyle = color(t.Muted)
+	statusErrorStyle = color(t.Error)
+	paneBorder = borders[t.Border]
+	paneBorderColor = lipgloss.Color(t.Muted)
+}
+
+// loadTheme returns the default theme with the fields of theme.json applied,
+// a broken file is logged and ignored
+func loadTheme() theme {
+	t := defaultTheme()
+	b, err := os.ReadFile(config.Path("theme.json"))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Println("couldn't read theme.json, reason: " + err.Error())
+		}
+		return t
+	}
+	custom := defaultTheme()
+	if err := json.Unmarshal(b, &custom); err != nil {
+		log.Println("couldn't parse theme.json, reason: " + err.Error())
+		return t
+	}
+	if _, ok := borders[custom.Border]; !ok {
+		log.Printf("unknown border %q in theme.json\n", custom.Border)
+		custom.Border = t.Border
+	}
+	return custom
+}
@@ -0,0 +1,142 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ani/ani-ar/profile"
+	"github.com/goccy/go-json"
+)
+
+type WatchedEpisode struct {
+	WatchedAt time.Time `json:"watchedAt"`
+}
+
+type DownloadedEpisode struct {
+	Path         string    `json:"path"`
+	DownloadedAt time.Time `json:"downloadedAt"`
+}
+
+// Store keeps the watched and downloaded episodes by fetcher anime id
+type Store struct {
+	path string
+
+	mu         sync.RWMutex
+	Watched    map[string]map[string]WatchedEpisode    `json:"watched"`
+	Downloaded map[string]map[string]DownloadedEpisode `json:"downloaded"`
+}
+
+var (
+	storesMu sync.Mutex
+	stores   = make(map[string]*Store)
+)
+
+// GetStore returns the history of the active profile
+func GetStore() *Store {
+	return GetProfileStore(profile.Active())
+}
+
+// GetProfileStore returns the history of the profile, an unreadable history
+// file is replaced by an empty one so playback never fails because of it
+func GetProfileStore(name string) *Store {
+	storesMu.Lock()
+	defer storesMu.Unlock()
+	if s, found := stores[name]; found {
+		return s
+	}
+	path := profile.Path(name, "history.json")
+	s, err := Load(path)
+	if err != nil {
+		s = newStore(path)
+	}
+	stores[name] = s
+	return s
+}
+
+func newStore(path string) *Store {
+	return &Store{
+		path:       path,
+		Watched:    make(map[string]map[string]WatchedEpisode),
+		Downloaded: make(map[string]map[string]DownloadedEpisode),
+	}
+}
+
+func Load(path string) (*Store, error) {
+	s := newStore(path)
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, errors.New("couldn't parse the history file, reason: " + err.Error())
+	}
+	return s, nil
+}
+
+// must be called with the lock held
+func (s *Store) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), os.ModePerm); err != nil {
+		return err
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, b, 0644)
+}
+
+func (s *Store) MarkWatched(animeId string, episode int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Watched[animeId] == nil {
+		s.Watched[animeId] = make(map[string]WatchedEpisode)
+	}
+	s.Watched[animeId][strconv.Itoa(episode)] = WatchedEpisode{WatchedAt: time.Now()}
+	return s.save()
+}
+
+func (s *Store) MarkDownloaded(animeId string, episode int, path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Downloaded[animeId] == nil {
+		s.Downloaded[animeId] = make(map[string]DownloadedEpisode)
+	}
+	s.Downloaded[animeId][strconv.Itoa(episode)] = DownloadedEpisode{Path: path, DownloadedAt: time.Now()}
+	return s.save()
+}
+
+func (s *Store) IsWatched(animeId string, episode int) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, found := s.Watched[animeId][strconv.Itoa(episode)]
+	return found
+}
+
+func (s *Store) IsDownloaded(animeId string, episode int) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, found := s.Downloaded[animeId][strconv.Itoa(episode)]
+	return found
+}
+
+// WatchedAnime returns the ids of the anime with watched episodes
+func (s *Store) WatchedAnime() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ids := make([]string, 0, len(s.Watched))
+	for id, episodes := range s.Watched {
+		if len(episodes) > 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
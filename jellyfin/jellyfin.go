@@ -6,6 +6,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -14,27 +15,58 @@ import (
 	"time"
 
 	"github.com/ani/ani-ar/api"
+	"github.com/ani/ani-ar/events"
 	"github.com/ani/ani-ar/fetcher"
+	"github.com/ani/ani-ar/filelock"
 	"github.com/ani/ani-ar/types"
 	"github.com/goccy/go-json"
 	"github.com/kirsle/configdir"
 )
 
+// mu and the lock file of the revision keep one change of the library at a
+// time, see lockLibrary
 var mu sync.Mutex
 
 var remoteRevisionUrl string
 var animeShowsPath string
 var animeMoviesPath string
 
+// optional ani-ar server url (eg. `http://192.168.1.10:8000`), when set the
+// .strm files point to its stream proxy instead of the expiring video urls
+var streamProxyUrl string
+
+// the api key of the stream proxy when its server requires keys, it needs the stream scope
+var streamProxyApiKey string
+
+// the links are refreshed every 2 hours, their tokens outlive a few failed refreshes
+const strmTokenTTL = 24 * time.Hour
+
 func init() {
 	remoteRevisionUrl = os.Getenv("ANI_AR_REMOTE_REVISION_RAW_URL")
 	animeShowsPath = os.Getenv("ANI_AR_ANIME_SHOWS_FOLDER_PATH")
 	animeMoviesPath = os.Getenv("ANI_AR_ANIME_MOVIES_FOLDER_PATH")
+	streamProxyUrl = os.Getenv("ANI_AR_STREAM_PROXY_URL")
+	streamProxyApiKey = os.Getenv("ANI_AR_STREAM_PROXY_API_KEY")
 }
 
 var aniArConfigFolderPath = filepath.Join(configdir.LocalConfig(), "ani-ar")
 var revisionFilePath = filepath.Join(aniArConfigFolderPath, "rev.cfg")
 
+// lockLibrary takes mu and the lock file of the revision, the api of `serve`
+// and the loop of `jelly` change the same library from their own processes
+func lockLibrary() (unlock func(), err error) {
+	mu.Lock()
+	f, err := filelock.Lock(revisionFilePath + ".lock")
+	if err != nil {
+		mu.Unlock()
+		return nil, err
+	}
+	return func() {
+		f.Close()
+		mu.Unlock()
+	}, nil
+}
+
 type JellyfinRevisionItem struct {
 	// anime id or title (for anime3rb fetcher)
 	ID string `json:"id"`
@@ -46,6 +78,8 @@ type JellyfinRevisionItem struct {
 	Season int `json:"season"`
 
 	CanBeEnhanced bool `json:"canBeEnhanced"`
+	// added with the api, the remote revision doesn't remove it
+	Local bool `json:"local,omitempty"`
 }
 
 type JellyfinRevision struct {
@@ -76,7 +110,7 @@ func createFirstLocalRevisionFile() (*JellyfinRevision, error) {
 	if err != nil {
 		return nil, errors.New("couldn't create ani-ar config folder, reason :" + err.Error())
 	}
-	err = os.WriteFile(revisionFilePath, []byte(""), 0755)
+	err = os.WriteFile(revisionFilePath, []byte(""), 0600)
 	if err != nil {
 		return nil, errors.New("couldn't create inital revision file, reason :" + err.Error())
 	}
@@ -194,6 +228,10 @@ func DiffRevisions(old, new *JellyfinRevision) []*JellyfinRevisionDiff {
 				}
 			}
 		}
+		if !found && localRevItem.Local {
+			// added with the api, it isn't managed by the remote revision
+			continue
+		}
 		if !found {
 			// old rev was not found in the new, delete it
 			log.Printf("[diif] revision item %s deleted\n", localRevItem.ID)
@@ -226,6 +264,55 @@ func DiffRevisions(old, new *JellyfinRevision) []*JellyfinRevisionDiff {
 	return diffs
 }
 
+// RevisionDiffEvent is the data of the revision diff event, the updated items have their new values
+type RevisionDiffEvent struct {
+	RevisionId string                 `json:"revisionId"`
+	Added      []JellyfinRevisionItem `json:"added"`
+	Updated    []JellyfinRevisionItem `json:"updated"`
+	Removed    []JellyfinRevisionItem `json:"removed"`
+}
+
+// newRevisionDiffEvent lists the items of the diffs, it should be called
+// before processing them since the processing changes the old items
+func newRevisionDiffEvent(diffs []*JellyfinRevisionDiff, old, new *JellyfinRevision) RevisionDiffEvent {
+	e := RevisionDiffEvent{
+		RevisionId: new.RevisionId,
+		Added:      []JellyfinRevisionItem{},
+		Updated:    []JellyfinRevisionItem{},
+		Removed:    []JellyfinRevisionItem{},
+	}
+	for _, diff := range diffs {
+		mode, idxStr, _ := strings.Cut(diff.Mode, ":")
+		idx, _ := strconv.Atoi(idxStr)
+		switch mode {
+		case "ADD":
+			var item JellyfinRevisionItem
+			if json.Unmarshal([]byte(diff.New), &item) == nil {
+				e.Added = append(e.Added, item)
+			}
+		case "DEL":
+			e.Removed = append(e.Removed, old.Items[idx])
+		case "UPDATE":
+			if i := findItem(new, old.Items[idx].ID); i != -1 {
+				e.Updated = append(e.Updated, new.Items[i])
+			}
+		}
+	}
+	return e
+}
+
+func (e RevisionDiffEvent) publishItems() {
+	for _, item := range e.Added {
+		events.Publish(events.JellyfinItemAdded, item)
+	}
+	for _, item := range e.Updated {
+		events.Publish(events.JellyfinItemUpdated, item)
+	}
+	for _, item := range e.Removed {
+		events.Publish(events.JellyfinItemRemoved, item)
+	}
+}
+
 func printDiffInfo(diffs []*JellyfinRevisionDiff) {
 	addDiffsCount := 0
 	updateDiffsCount := 0
@@ -248,6 +335,7 @@ func ProcessDiff(diffs []*JellyfinRevisionDiff, old *JellyfinRevision, newRevId
 	printDiffInfo(diffs)
 
 	var newRev = *old
+	removed := make(map[int]bool)
 	for _, diff := range diffs {
 		if strings.HasPrefix(diff.Mode, "DEL") {
 			modeParts := strings.Split(diff.Mode, ":")
@@ -258,8 +346,8 @@ func ProcessDiff(diffs []*JellyfinRevisionDiff, old *JellyfinRevision, newRevId
 			if err != nil {
 				return nil, err
 			}
-
-			newRev.Items = append(old.Items[:oldRevItemIdx], old.Items[oldRevItemIdx+1:]...)
+			// removed once every diff is processed so the indexes stay valid
+			removed[oldRevItemIdx] = true
 		} else if strings.HasPrefix(diff.Mode, "UPDATE") {
 			modeParts := strings.Split(diff.Mode, ":")
 			idxStr := modeParts[1]
@@ -292,33 +380,37 @@ func ProcessDiff(diffs []*JellyfinRevisionDiff, old *JellyfinRevision, newRevId
 		}
 	}
 
+	if len(removed) > 0 {
+		var items []JellyfinRevisionItem
+		for i, item := range newRev.Items {
+			if !removed[i] {
+				items = append(items, item)
+			}
+		}
+		newRev.Items = items
+	}
 	newRev.RevisionId = newRevId
 
 	return &newRev, nil
 }
 
+// the revision is written to a temporary file and moved over the old one so
+// the api can read it while it's being written
 func writeNewRevLocally(newRev *JellyfinRevision) error {
-	file, err := os.OpenFile(revisionFilePath, os.O_APPEND|os.O_WRONLY, os.ModeAppend)
-
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-	err = file.Truncate(0)
+	revBytes, err := json.Marshal(newRev)
 	if err != nil {
 		return err
 	}
-	revBytes, err := json.Marshal(newRev)
+	err = os.MkdirAll(aniArConfigFolderPath, os.ModePerm)
 	if err != nil {
 		return err
 	}
-
-	_, err = file.Write(revBytes)
+	tmpPath := revisionFilePath + ".tmp"
+	err = os.WriteFile(tmpPath, revBytes, 0600)
 	if err != nil {
 		return err
 	}
-
-	return nil
+	return os.Rename(tmpPath, revisionFilePath)
 }
 
 func PerformRevision() error {
@@ -337,10 +429,13 @@ func PerformRevision() error {
 	}
 
 	diffs := DiffRevisions(localRev, remoteRev)
-	if len(diffs) == 0 {
+	claimed := claimLocalItems(localRev, remoteRev)
+	if len(diffs) == 0 && !claimed {
 		log.Println("No diffs to to perform, all good")
 		return nil
 	}
+	diffEvent := newRevisionDiffEvent(diffs, localRev, remoteRev)
+	events.Publish(events.JellyfinRevisionDiff, diffEvent)
 
 	updatedRev, err := ProcessDiff(diffs, localRev, remoteRev.RevisionId)
 	if err != nil {
@@ -351,11 +446,25 @@ func PerformRevision() error {
 	if err != nil {
 		return errors.New("error while writing the new rev config file, reason: " + err.Error())
 	}
+	diffEvent.publishItems()
 	b, _ := json.Marshal(updatedRev)
 	println("revision done, content : " + string(b))
 	return nil
 }
 
+// claimLocalItems hands the items added with the api that the remote revision
+// lists over to it, it returns whether there were any
+func claimLocalItems(local, remote *JellyfinRevision) bool {
+	claimed := false
+	for i := range local.Items {
+		if local.Items[i].Local && findItem(remote, local.Items[i].ID) != -1 {
+			local.Items[i].Local = false
+			claimed = true
+		}
+	}
+	return claimed
+}
+
 func getFormattedAnimeMovieName(r *api.EnhancedAnimeResult) string {
 	return fmt.Sprintf("%s (%v).strm", r.Details.Title, r.Details.Aired.Prop.From.Year)
 }
@@ -377,7 +486,20 @@ func downloadEpisode(aniEpisode *types.AniEpisode, filePath string, res string)
 		log.Printf("looks like there is no available links for %s episode %v, skipping", aniEpisode.Anime.DisplayName, aniEpisode.Number)
 		return nil
 	}
-	err := os.WriteFile(filePath, []byte(medias[0].Src), 0755)
+	src := medias[0].Src
+	if streamProxyUrl != "" {
+		src = api.StreamUrl(streamProxyUrl, aniEpisode.Anime.Id, aniEpisode.Number) + "?res=" + url.QueryEscape(res)
+		if streamProxyApiKey != "" {
+			// the files are readable by the jellyfin users, they only get a
+			// token of the episode that the refresh loop renews
+			token, err := api.NewStreamToken(streamProxyApiKey, aniEpisode.Anime.Id, aniEpisode.Number, strmTokenTTL)
+			if err != nil {
+				return fmt.Errorf("invalid ANI_AR_STREAM_PROXY_API_KEY: %w", err)
+			}
+			src += "&" + api.StreamTokenQuery + "=" + url.QueryEscape(token)
+		}
+	}
+	err := os.WriteFile(filePath, []byte(src), 0644)
 	if err != nil {
 		return err
 	}
@@ -392,10 +514,16 @@ func getEnhancedResultForJellyfin(revItem *JellyfinRevisionItem) (*api.EnhancedA
 		if err != nil {
 			return nil, err
 		}
+		if r.Details == nil {
+			return nil, ErrNoDetails
+		}
 		enhancedAnimeResult = r
 	} else {
 		// some revitem ids can't be enhanced because it has random string as id, instead of reaadable title or mal id
 		anime := fetcher.GetAnimeResult(revItem.ID)
+		if anime == nil {
+			return nil, ErrAnimeNotFound
+		}
 
 		enhancedAnimeResult = &api.EnhancedAnimeResult{
 			Data: anime,
@@ -481,7 +609,9 @@ func AddJellyfinMedia(revItem *JellyfinRevisionItem) error {
 		if isMovie {
 			episodePath = filepath.Join(animeMoviesPath, getFormattedAnimeMovieName(enhancedAnimeResult))
 		}
-		downloadEpisode(&fetcherEpisode, episodePath, revItem.Res)
+		if err := downloadEpisode(&fetcherEpisode, episodePath, revItem.Res); err != nil {
+			log.Printf("couldn't write episode [%v] of [%s], reason: %v", episodeIdx+1, enhancedAnimeResult.Details.Title, err)
+		}
 	}
 
 	return nil
@@ -489,8 +619,11 @@ func AddJellyfinMedia(revItem *JellyfinRevisionItem) error {
 }
 
 func RefreshLocalMediaItems() error {
-	mu.Lock()
-	defer mu.Unlock()
+	unlock, err := lockLibrary()
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	localRevision, err := GetAndParseLocalRevision()
 	if err != nil {
@@ -521,6 +654,7 @@ func InfiniteLoop() error {
 	log.Println("ANI_AR_REMOTE_REVISION_RAW_URL: " + remoteRevisionUrl)
 	log.Println("ANI_AR_ANIME_SHOWS_FOLDER_PATH: " + animeShowsPath)
 	log.Println("ANI_AR_ANIME_MOVIES_FOLDER_PATH: " + animeMoviesPath)
+	log.Println("ANI_AR_STREAM_PROXY_URL: " + streamProxyUrl)
 	println(`
 	
 		 /$$$$$$            /$$                             
@@ -542,9 +676,8 @@ func InfiniteLoop() error {
 			select {
 			case <-ticker.C:
 				log.Println("Refreshing links for local media items")
-				err := RefreshLocalMediaItems()
-				if err != nil {
-					log.Printf("Error refreshing media items: %v", err)
+				if err := Refresh(); errors.Is(err, ErrBusy) {
+					log.Println("a revision is running, the links are refreshed on the next tick")
 				}
 			}
 		}
@@ -553,9 +686,22 @@ func InfiniteLoop() error {
 	// Revision loop runs every 5 minutes
 	interval := time.Minute * 5
 	for {
-		mu.Lock()
-		err := PerformRevision()
-		mu.Unlock()
+		if remoteRevisionUrl == "" {
+			// the library is managed with the api only
+			time.Sleep(interval)
+			continue
+		}
+		if !startOperation("revision") {
+			// a refresh is running
+			time.Sleep(time.Minute)
+			continue
+		}
+		unlock, err := lockLibrary()
+		if err == nil {
+			err = PerformRevision()
+			unlock()
+		}
+		recordRevision(err)
 		if err != nil {
 			log.Printf("Error during PerformRevision: %v", err)
 			time.Sleep(time.Minute)
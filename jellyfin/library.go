@@ -0,0 +1,319 @@
+package jellyfin
+
+import (
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ani/ani-ar/api"
+	"github.com/ani/ani-ar/events"
+	"github.com/goccy/go-json"
+)
+
+const (
+	TypeShow  = "TV"
+	TypeMovie = "Movie"
+)
+
+var (
+	ErrMissingId     = errors.New("the anime id is required")
+	ErrItemNotFound  = errors.New("the anime isn't in the jellyfin library")
+	ErrItemExists    = errors.New("the anime is already in the jellyfin library")
+	ErrInvalidType   = errors.New("invalid type, it should be TV or Movie")
+	ErrInvalidSeason = errors.New("invalid season, it should be 1 or more")
+	ErrAnimeNotFound = api.ErrAnimeNotFound
+	ErrNoDetails     = errors.New("the anime wasn't found on MyAnimeList, add it with canBeEnhanced set to false")
+	ErrBusy          = errors.New("the library is busy, check the status")
+)
+
+// Status tells what the library went through last, the revisions are the
+// checks of the remote revision and the refreshes rewrite the expiring links
+type Status struct {
+	RevisionId        string     `json:"revisionId"`
+	Items             int        `json:"items"`
+	RemoteRevisionUrl string     `json:"remoteRevisionUrl"`
+	Running           string     `json:"running,omitempty"`
+	LastRevisionAt    *time.Time `json:"lastRevisionAt,omitempty"`
+	LastRevisionError string     `json:"lastRevisionError,omitempty"`
+	LastRefreshAt     *time.Time `json:"lastRefreshAt,omitempty"`
+	LastRefreshError  string     `json:"lastRefreshError,omitempty"`
+}
+
+// the status has its own lock so it can be read while mu is held by a long refresh
+var (
+	statusMu sync.Mutex
+	status   Status
+)
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// OperationEvent is the data of the revision and refresh events
+type OperationEvent struct {
+	RevisionId string `json:"revisionId,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// startOperation marks the revision or the refresh as running, it returns
+// false when one of them is already running
+func startOperation(operation string) bool {
+	statusMu.Lock()
+	if status.Running != "" {
+		statusMu.Unlock()
+		return false
+	}
+	status.Running = operation
+	statusMu.Unlock()
+	if operation == "revision" {
+		events.Publish(events.JellyfinRevisionStarted, OperationEvent{})
+	} else {
+		events.Publish(events.JellyfinRefreshStarted, OperationEvent{})
+	}
+	return true
+}
+
+func recordRevision(err error) {
+	statusMu.Lock()
+	status.Running = ""
+	now := time.Now()
+	status.LastRevisionAt = &now
+	status.LastRevisionError = errorString(err)
+	statusMu.Unlock()
+	publishOperation(events.JellyfinRevisionDone, events.JellyfinRevisionFailed, err)
+}
+
+func recordRefresh(err error) {
+	statusMu.Lock()
+	status.Running = ""
+	now := time.Now()
+	status.LastRefreshAt = &now
+	status.LastRefreshError = errorString(err)
+	statusMu.Unlock()
+	publishOperation(events.JellyfinRefreshDone, events.JellyfinRefreshFailed, err)
+}
+
+func publishOperation(doneType, failedType string, err error) {
+	if err != nil {
+		events.Publish(failedType, OperationEvent{Error: err.Error()})
+		return
+	}
+	e := OperationEvent{}
+	if rev, err := readLocalRevision(); err == nil {
+		e.RevisionId = rev.RevisionId
+	}
+	events.Publish(doneType, e)
+}
+
+// GetStatus returns the last revision and refresh with the local revision details
+func GetStatus() Status {
+	statusMu.Lock()
+	s := status
+	statusMu.Unlock()
+	s.RemoteRevisionUrl = remoteRevisionUrl
+	if rev, err := readLocalRevision(); err == nil {
+		s.RevisionId = rev.RevisionId
+		s.Items = len(rev.Items)
+	}
+	return s
+}
+
+// readLocalRevision reads the local revision without creating it, the file is
+// replaced atomically so it can be read without holding mu
+func readLocalRevision() (*JellyfinRevision, error) {
+	b, err := os.ReadFile(revisionFilePath)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && len(b) == 0) {
+		return &JellyfinRevision{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rev JellyfinRevision
+	if err := json.Unmarshal(b, &rev); err != nil {
+		return nil, errors.New("couldn't parse the local revision file, reason :" + err.Error())
+	}
+	return &rev, nil
+}
+
+func findItem(rev *JellyfinRevision, id string) int {
+	for i := range rev.Items {
+		if rev.Items[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// validateItem checks the item and fills its defaults
+func validateItem(item *JellyfinRevisionItem) error {
+	item.ID = strings.TrimSpace(item.ID)
+	if item.ID == "" {
+		return ErrMissingId
+	}
+	if item.Type == "" {
+		item.Type = TypeShow
+	}
+	if item.Type != TypeShow && item.Type != TypeMovie {
+		return ErrInvalidType
+	}
+	if item.Season == 0 {
+		item.Season = 1
+	}
+	if item.Season < 0 {
+		return ErrInvalidSeason
+	}
+	return nil
+}
+
+// ListItems returns the anime of the local revision
+func ListItems() ([]JellyfinRevisionItem, error) {
+	rev, err := readLocalRevision()
+	if err != nil {
+		return nil, err
+	}
+	items := rev.Items
+	if items == nil {
+		items = []JellyfinRevisionItem{}
+	}
+	return items, nil
+}
+
+func GetItem(id string) (JellyfinRevisionItem, error) {
+	rev, err := readLocalRevision()
+	if err != nil {
+		return JellyfinRevisionItem{}, err
+	}
+	i := findItem(rev, id)
+	if i == -1 {
+		return JellyfinRevisionItem{}, ErrItemNotFound
+	}
+	return rev.Items[i], nil
+}
+
+// AddItem writes the .strm files of the anime and adds it to the local revision.
+// The item is kept by the remote revision checks unless the remote lists it too
+func AddItem(item JellyfinRevisionItem) (JellyfinRevisionItem, error) {
+	if err := validateItem(&item); err != nil {
+		return item, err
+	}
+	item.Local = true
+	unlock, err := lockLibrary()
+	if err != nil {
+		return item, err
+	}
+	defer unlock()
+
+	rev, err := GetAndParseLocalRevision()
+	if err != nil {
+		return item, err
+	}
+	if findItem(rev, item.ID) != -1 {
+		return item, ErrItemExists
+	}
+	if err := AddJellyfinMedia(&item); err != nil {
+		return item, err
+	}
+	rev.Items = append(rev.Items, item)
+	if err := writeNewRevLocally(rev); err != nil {
+		return item, err
+	}
+	events.Publish(events.JellyfinItemAdded, item)
+	return item, nil
+}
+
+// UpdateItem changes the item with the change func and rewrites its files
+func UpdateItem(id string, change func(*JellyfinRevisionItem)) (JellyfinRevisionItem, error) {
+	unlock, err := lockLibrary()
+	if err != nil {
+		return JellyfinRevisionItem{}, err
+	}
+	defer unlock()
+
+	rev, err := GetAndParseLocalRevision()
+	if err != nil {
+		return JellyfinRevisionItem{}, err
+	}
+	i := findItem(rev, id)
+	if i == -1 {
+		return JellyfinRevisionItem{}, ErrItemNotFound
+	}
+	updated := rev.Items[i]
+	change(&updated)
+	updated.ID = rev.Items[i].ID
+	if err := validateItem(&updated); err != nil {
+		return updated, err
+	}
+	if err := RemoveJellyfinMedia(&rev.Items[i]); err != nil {
+		return updated, err
+	}
+	if err := AddJellyfinMedia(&updated); err != nil {
+		return updated, err
+	}
+	rev.Items[i] = updated
+	if err := writeNewRevLocally(rev); err != nil {
+		return updated, err
+	}
+	events.Publish(events.JellyfinItemUpdated, updated)
+	return updated, nil
+}
+
+// RemoveItem deletes the files of the anime and removes it from the local revision
+func RemoveItem(id string) error {
+	unlock, err := lockLibrary()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	rev, err := GetAndParseLocalRevision()
+	if err != nil {
+		return err
+	}
+	i := findItem(rev, id)
+	if i == -1 {
+		return ErrItemNotFound
+	}
+	if err := RemoveJellyfinMedia(&rev.Items[i]); err != nil {
+		return err
+	}
+	removed := rev.Items[i]
+	rev.Items = append(rev.Items[:i], rev.Items[i+1:]...)
+	if err := writeNewRevLocally(rev); err != nil {
+		return err
+	}
+	events.Publish(events.JellyfinItemRemoved, removed)
+	return nil
+}
+
+// Refresh rewrites the links of every anime in the library and records the
+// result, it fails with ErrBusy while a revision or a refresh is running
+func Refresh() error {
+	if !startOperation("refresh") {
+		return ErrBusy
+	}
+	return refresh()
+}
+
+// StartRefresh runs Refresh in the background
+func StartRefresh() error {
+	if !startOperation("refresh") {
+		return ErrBusy
+	}
+	go refresh()
+	return nil
+}
+
+func refresh() error {
+	err := RefreshLocalMediaItems()
+	recordRefresh(err)
+	if err != nil {
+		log.Printf("Error refreshing media items: %v", err)
+	}
+	return err
+}
@@ -0,0 +1,70 @@
+package jellyfin
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestApiItemsAreKeptByTheRemoteRevision(t *testing.T) {
+	local := &JellyfinRevision{Items: []JellyfinRevisionItem{
+		{ID: "naruto", Type: TypeShow, Season: 1},
+		{ID: "bleach", Type: TypeShow, Season: 1, Local: true},
+		{ID: "one-piece", Type: TypeShow, Season: 1, Local: true},
+	}}
+	remote := &JellyfinRevision{Items: []JellyfinRevisionItem{
+		{ID: "one-piece", Type: TypeShow, Res: "1080", Season: 1},
+	}}
+
+	diffs := DiffRevisions(local, remote)
+	// naruto is removed, bleach is kept and one-piece gets the remote res
+	if len(diffs) != 2 || diffs[0].Mode != "DEL:0" || diffs[1].Mode != "UPDATE:2" || diffs[1].New != "1080" {
+		for _, d := range diffs {
+			t.Logf("%+v", *d)
+		}
+		t.Fatal("unexpected diffs")
+	}
+
+	if !claimLocalItems(local, remote) {
+		t.Fatal("one-piece should be handed over to the remote revision")
+	}
+	if !local.Items[1].Local || local.Items[2].Local {
+		t.Fatalf("only the listed item should be claimed, %+v", local.Items)
+	}
+	if claimLocalItems(local, remote) {
+		t.Fatal("the items are claimed once")
+	}
+}
+
+func TestOnlyOneOperationRuns(t *testing.T) {
+	t.Cleanup(func() { status.Running = "" })
+
+	var wg sync.WaitGroup
+	started := make(chan string, 20)
+	for i := 0; i < 10; i++ {
+		for _, operation := range []string{"refresh", "revision"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if startOperation(operation) {
+					started <- operation
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	close(started)
+	if n := len(started); n != 1 {
+		t.Fatalf("%d operations started, want 1", n)
+	}
+	if GetStatus().Running != <-started {
+		t.Fatal("the status should tell the running operation")
+	}
+	if startOperation("refresh") {
+		t.Fatal("a refresh shouldn't start while the operation runs")
+	}
+
+	recordRefresh(nil)
+	if !startOperation("revision") {
+		t.Fatal("the revision should start once the operation is done")
+	}
+}
@@ -0,0 +1,128 @@
+package jellyfin
+
+import (
+	"errors"
+
+	"github.com/ani/ani-ar/api"
+	"github.com/gofiber/fiber/v2"
+)
+
+// jellyfinItemBody is the body of the add and update requests, the missing
+// fields keep their value (or the default when adding)
+type jellyfinItemBody struct {
+	ID            string  `json:"id"`
+	Type          *string `json:"type" query:"type"`
+	Res           *string `json:"res" query:"res"`
+	Season        *int    `json:"season" query:"season"`
+	CanBeEnhanced *bool   `json:"canBeEnhanced" query:"canBeEnhanced"`
+}
+
+func (b *jellyfinItemBody) apply(item *JellyfinRevisionItem) {
+	if b.Type != nil {
+		item.Type = *b.Type
+	}
+	if b.Res != nil {
+		item.Res = *b.Res
+	}
+	if b.Season != nil {
+		item.Season = *b.Season
+	}
+	if b.CanBeEnhanced != nil {
+		item.CanBeEnhanced = *b.CanBeEnhanced
+	}
+}
+
+// InitiateRoutes registers the library routes, the changes wait for the
+// running revision or refresh to finish
+func InitiateRoutes(app *fiber.App) {
+	app.Get(api.JellyfinItemsUrl, func(c *fiber.Ctx) error {
+		items, err := ListItems()
+		if err != nil {
+			return err
+		}
+		return c.JSON(items)
+	})
+
+	addItem := func(c *fiber.Ctx, body jellyfinItemBody) error {
+		// the ids of the default fetcher are readable titles that can be looked up on MyAnimeList
+		item := JellyfinRevisionItem{ID: body.ID, CanBeEnhanced: true}
+		body.apply(&item)
+		added, err := AddItem(item)
+		if err != nil {
+			return jellyfinError(c, err)
+		}
+		return c.Status(201).JSON(added)
+	}
+
+	app.Post(api.JellyfinItemsUrl, func(c *fiber.Ctx) error {
+		var body jellyfinItemBody
+		if err := c.BodyParser(&body); err != nil || body.ID == "" {
+			return jellyfinError(c, ErrMissingId)
+		}
+		return addItem(c, body)
+	})
+
+	// the add shortcut takes the item fields as query parameters, eg. /api/add/x?type=Movie&res=720
+	app.Post(api.AddJellyfinAnime, func(c *fiber.Ctx) error {
+		var body jellyfinItemBody
+		if err := c.QueryParser(&body); err != nil {
+			return c.Status(400).JSON(map[string]string{"message": "invalid query, reason: " + err.Error()})
+		}
+		body.ID = c.Params("animeId")
+		return addItem(c, body)
+	})
+
+	app.Get(api.JellyfinItemUrl, func(c *fiber.Ctx) error {
+		item, err := GetItem(c.Params("animeId"))
+		if err != nil {
+			return jellyfinError(c, err)
+		}
+		return c.JSON(item)
+	})
+
+	app.Patch(api.JellyfinItemUrl, func(c *fiber.Ctx) error {
+		var body jellyfinItemBody
+		if err := c.BodyParser(&body); err != nil {
+			return c.Status(400).JSON(map[string]string{"message": "invalid body, reason: " + err.Error()})
+		}
+		item, err := UpdateItem(c.Params("animeId"), body.apply)
+		if err != nil {
+			return jellyfinError(c, err)
+		}
+		return c.JSON(item)
+	})
+
+	app.Delete(api.JellyfinItemUrl, func(c *fiber.Ctx) error {
+		if err := RemoveItem(c.Params("animeId")); err != nil {
+			return jellyfinError(c, err)
+		}
+		return c.SendStatus(204)
+	})
+
+	// the refresh takes a while, it runs in the background and the status tells when it's done
+	app.Post(api.JellyfinRefreshUrl, func(c *fiber.Ctx) error {
+		if err := StartRefresh(); err != nil {
+			return jellyfinError(c, err)
+		}
+		return c.Status(202).JSON(GetStatus())
+	})
+
+	app.Get(api.JellyfinStatusUrl, func(c *fiber.Ctx) error {
+		return c.JSON(GetStatus())
+	})
+}
+
+func jellyfinError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, ErrItemNotFound), errors.Is(err, ErrAnimeNotFound):
+		return c.Status(404).JSON(map[string]string{"message": err.Error()})
+	case errors.Is(err, ErrItemExists), errors.Is(err, ErrBusy):
+		return c.Status(409).JSON(map[string]string{"message": err.Error()})
+	case errors.Is(err, ErrMissingId),
+		errors.Is(err, ErrNoDetails),
+		errors.Is(err, ErrInvalidType),
+		errors.Is(err, ErrInvalidSeason):
+		return c.Status(400).JSON(map[string]string{"message": err.Error()})
+	}
+	return err
+}
@@ -0,0 +1,38 @@
+package jellyfin
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ani/ani-ar/api"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestSpecDescribesTheRoutes(t *testing.T) {
+	b, err := os.ReadFile("../api/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(b, &spec); err != nil {
+		t.Fatal(err)
+	}
+	app := fiber.New()
+	InitiateRoutes(app)
+
+	for _, route := range app.GetRoutes(true) {
+		// the legacy add route isn't under the shared prefix
+		if route.Method == "HEAD" || !strings.HasPrefix(route.Path, api.JellyfinBaseUrl+"/") {
+			continue
+		}
+		path := regexp.MustCompile(`:(\w+)`).ReplaceAllString(strings.TrimPrefix(route.Path, "/api"), "{$1}")
+		if _, found := spec.Paths[path][strings.ToLower(route.Method)]; !found {
+			t.Errorf("%s %s isn't in the api description", route.Method, path)
+		}
+	}
+}
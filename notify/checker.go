@@ -0,0 +1,199 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ani/ani-ar/events"
+	"github.com/ani/ani-ar/fetcher"
+	"github.com/ani/ani-ar/filelock"
+	"github.com/ani/ani-ar/history"
+	"github.com/ani/ani-ar/profile"
+	"github.com/ani/ani-ar/watchlist"
+	"github.com/goccy/go-json"
+)
+
+// followed is an anime the checks poll
+type followed struct {
+	source  string
+	id      string
+	fetcher fetcher.Fetcher
+}
+
+func (f followed) key() string {
+	return f.source + "/" + f.id
+}
+
+// followedAnime returns the anime of the profile that are followed: the
+// watchlist entries being watched or planned, the favorites and the anime with
+// watched episodes in the history. The history doesn't record the source so
+// its anime are polled from the fetcher of the profile. The anime of the
+// history are returned with the error of an unreadable watchlist
+func followedAnime(name string) ([]followed, error) {
+	profileFetcher := fetcher.GetProfileFetcher(name)
+	profileSource := fetcher.GetFetcherName(profileFetcher)
+	fetcherOf := func(source string) fetcher.Fetcher {
+		// the profile fetcher can be the dubbed one of the source
+		if source == profileSource {
+			return profileFetcher
+		}
+		f, err := fetcher.GetFetcherByName(source)
+		if err != nil {
+			return nil
+		}
+		return f
+	}
+
+	var anime []followed
+	seen := make(map[string]bool)
+	var entries []watchlist.Entry
+	store, err := watchlist.GetProfileStore(name)
+	if err == nil {
+		entries = store.List("", false)
+	}
+	for _, e := range entries {
+		a := followed{source: e.Source, id: e.Id, fetcher: fetcherOf(e.Source)}
+		seen[a.key()] = true
+		finished := e.Status == watchlist.StatusCompleted || e.Status == watchlist.StatusDropped
+		if a.fetcher == nil || (finished && !e.Favorite) {
+			continue
+		}
+		anime = append(anime, a)
+	}
+	for _, id := range history.GetProfileStore(name).WatchedAnime() {
+		a := followed{source: profileSource, id: id, fetcher: profileFetcher}
+		if seen[a.key()] {
+			continue
+		}
+		seen[a.key()] = true
+		anime = append(anime, a)
+	}
+	return anime, err
+}
+
+// state keeps the last episodes count of every followed anime by source/id
+type state struct {
+	path     string
+	Episodes map[string]int `json:"episodes"`
+}
+
+func loadState(name string) (*state, error) {
+	s := &state{path: profile.Path(name, "episodes.json"), Episodes: make(map[string]int)}
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, errors.New("couldn't parse the episodes file, reason: " + err.Error())
+	}
+	if s.Episodes == nil {
+		s.Episodes = make(map[string]int)
+	}
+	return s, nil
+}
+
+func (s *state) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), os.ModePerm); err != nil {
+		return err
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, b, 0644)
+}
+
+// Checker polls the followed anime of a profile and notifies the backends
+// when their episodes count grows
+type Checker struct {
+	Profile  string
+	Backends []Backend
+}
+
+func NewChecker(profileName string, backends []Backend) *Checker {
+	return &Checker{Profile: profileName, Backends: backends}
+}
+
+// Check polls every followed anime once and returns the sent notifications.
+// An anime seen for the first time is only recorded, and the count of an anime
+// whose notification failed is kept so the next check sends it again
+func (c *Checker) Check(ctx context.Context) ([]Notification, error) {
+	// `serve --notify` and `ani-ar notify` can check the profile at the same
+	// time, the second one waits for the episodes the first one records
+	lock, err := filelock.Lock(profile.Path(c.Profile, "episodes.json.lock"))
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Close()
+
+	s, err := loadState(c.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	anime, err := followedAnime(c.Profile)
+	sent, checkErr := c.check(ctx, s, anime)
+	return sent, errors.Join(err, checkErr)
+}
+
+// check polls the anime and records their episodes count in the state, the
+// cached anime are dropped first since their count is what changes
+func (c *Checker) check(ctx context.Context, s *state, anime []followed) ([]Notification, error) {
+	var sent []Notification
+	var errs []error
+	for _, a := range anime {
+		if ctx.Err() != nil {
+			break
+		}
+		if f, ok := a.fetcher.(fetcher.Forgetter); ok {
+			f.ForgetAnimeResult(a.id)
+		}
+		result := a.fetcher.GetAnimeResult(a.id)
+		if result == nil || result.Episodes == 0 {
+			continue
+		}
+		previous, known := s.Episodes[a.key()]
+		if known && result.Episodes > previous {
+			n := newNotification(c.Profile, a.source, a.id, result.DisplayName, previous, result.Episodes)
+			if err := Send(ctx, c.Backends, n); err != nil {
+				errs = append(errs, errors.New(result.DisplayName+": "+err.Error()))
+				continue
+			}
+			// published once the backends got it, the failed ones are detected again
+			events.Publish(events.EpisodesNew, n)
+			sent = append(sent, n)
+		}
+		s.Episodes[a.key()] = result.Episodes
+	}
+	if err := s.save(); err != nil {
+		errs = append(errs, err)
+	}
+	return sent, errors.Join(errs...)
+}
+
+// Run checks the followed anime every interval until the context is done
+func (c *Checker) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		sent, err := c.Check(ctx)
+		for _, n := range sent {
+			log.Printf("%s: %s\n", n.Title, n.Message)
+		}
+		if err != nil {
+			log.Println("couldn't check the followed anime, reason: " + err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ani/ani-ar/cache"
+	"github.com/ani/ani-ar/filelock"
+	"github.com/ani/ani-ar/profile"
+	"github.com/ani/ani-ar/types"
+)
+
+// fakeFetcher caches its anime like the real fetchers, episodes is the count
+// the source has now
+type fakeFetcher struct {
+	episodes int
+	fetches  int
+}
+
+func (f *fakeFetcher) Search(q string) []types.AniResult { return nil }
+
+func (f *fakeFetcher) GetAnimeResult(id string) *types.AniResult {
+	r, _ := cache.Fetch(cache.Anime, "fake:"+id, func() (*types.AniResult, error) {
+		f.fetches++
+		return &types.AniResult{Id: id, DisplayName: "One Piece", Episodes: f.episodes}, nil
+	})
+	return r
+}
+
+func (f *fakeFetcher) GetEpisodes(types.AniResult) []types.AniEpisode { return nil }
+
+func (f *fakeFetcher) ForgetAnimeResult(id string) {
+	cache.Default().Delete(cache.Anime, "fake:"+id)
+}
+
+type fakeBackend struct {
+	err  error
+	sent []Notification
+}
+
+func (b *fakeBackend) Name() string { return "fake" }
+
+func (b *fakeBackend) Notify(ctx context.Context, n Notification) error {
+	if b.err != nil {
+		return b.err
+	}
+	b.sent = append(b.sent, n)
+	return nil
+}
+
+func TestCheckSeesTheNewEpisodesOfCachedAnime(t *testing.T) {
+	t.Setenv("ANI_AR_CONFIG_DIR", t.TempDir())
+	f := &fakeFetcher{episodes: 1100}
+	backend := &fakeBackend{}
+	c := NewChecker("default", []Backend{backend})
+	anime := []followed{{source: "fake", id: "one-piece", fetcher: f}}
+	s, err := loadState(c.Profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the anime is seen for the first time, eg. while browsing
+	f.GetAnimeResult("one-piece")
+	if sent, err := c.check(context.Background(), s, anime); err != nil || len(sent) != 0 {
+		t.Fatalf("the first check should only record the count, sent %v, err %v", sent, err)
+	}
+
+	f.episodes = 1102
+	// the backend fails so the episodes are sent on the next check
+	backend.err = errors.New("offline")
+	if _, err := c.check(context.Background(), s, anime); err == nil {
+		t.Fatal("the failed notification should be an error")
+	}
+	backend.err = nil
+	sent, err := c.check(context.Background(), s, anime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 || sent[0].PreviousEpisodes != 1100 || sent[0].Episodes != 1102 || len(backend.sent) != 1 {
+		t.Fatalf("the new episodes should be notified once, sent %+v", sent)
+	}
+	if f.fetches != 4 {
+		t.Fatalf("every check should ask the source, %d fetches", f.fetches)
+	}
+
+	// the state is saved between the checks
+	saved, err := loadState(c.Profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Episodes["fake/one-piece"] != 1102 {
+		t.Fatalf("saved state %v", saved.Episodes)
+	}
+}
+
+func TestCheckWaitsForTheOtherProcess(t *testing.T) {
+	t.Setenv("ANI_AR_CONFIG_DIR", t.TempDir())
+	c := NewChecker("default", []Backend{&fakeBackend{}})
+
+	// the lock of another process checking the profile, eg. serve --notify
+	lock, err := filelock.Lock(profile.Path(c.Profile, "episodes.json.lock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.Check(context.Background())
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		t.Fatalf("the check should wait for the lock, got %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	lock.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the check should run once the lock is released")
+	}
+}
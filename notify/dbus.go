@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+var ErrNoSessionBus = errors.New("no d-bus session bus, DBUS_SESSION_BUS_ADDRESS isn't set")
+
+// sessionBusAddress returns the address of the session bus, the bus of the
+// runtime folder is used when the variable isn't set. The bus isn't launched
+// when there's none
+func sessionBusAddress() (string, error) {
+	if address := os.Getenv("DBUS_SESSION_BUS_ADDRESS"); address != "" {
+		return address, nil
+	}
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		path := runtimeDir + "/bus"
+		if _, err := os.Stat(path); err == nil {
+			return "unix:path=" + path, nil
+		}
+	}
+	return "", ErrNoSessionBus
+}
+
+// dialSessionBus connects to the session bus, the connection should be closed
+func dialSessionBus(ctx context.Context) (*dbus.Conn, error) {
+	address, err := sessionBusAddress()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := dbus.Dial(address, dbus.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Auth(nil); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := conn.Hello(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// Desktop shows the notifications with the notification daemon of the
+// desktop through org.freedesktop.Notifications on the session bus
+type Desktop struct {
+	// how long the notification stays, 0 lets the daemon decide
+	Timeout time.Duration
+}
+
+func (d *Desktop) Name() string {
+	return BackendDesktop
+}
+
+func (d *Desktop) Notify(ctx context.Context, n Notification) error {
+	conn, err := dialSessionBus(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	expire := int32(-1)
+	if d.Timeout > 0 {
+		expire = int32(d.Timeout.Milliseconds())
+	}
+	// Notify(app_name, replaces_id, app_icon, summary, body, actions, hints, expire_timeout)
+	var id uint32
+	return conn.Object("org.freedesktop.Notifications", "/org/freedesktop/Notifications").CallWithContext(
+		ctx,
+		"org.freedesktop.Notifications.Notify",
+		0,
+		"ani-ar",
+		uint32(0),
+		"",
+		n.Title,
+		n.Message,
+		[]string{},
+		map[string]dbus.Variant{},
+		expire,
+	).Store(&id)
+}
@@ -0,0 +1,115 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestSessionBusAddress(t *testing.T) {
+	runtimeDir := t.TempDir()
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", "")
+	t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+	if _, err := sessionBusAddress(); err != ErrNoSessionBus {
+		t.Fatalf("got %v, want ErrNoSessionBus without a bus", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(runtimeDir, "bus"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if address, err := sessionBusAddress(); err != nil || address != "unix:path="+runtimeDir+"/bus" {
+		t.Fatalf("got %q, %v", address, err)
+	}
+
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", "unix:abstract=/tmp/dbus-test")
+	if address, _ := sessionBusAddress(); address != "unix:abstract=/tmp/dbus-test" {
+		t.Fatalf("the address of the variable should be used, got %q", address)
+	}
+}
+
+// notificationDaemon records the notifications like org.freedesktop.Notifications
+type notificationDaemon struct {
+	calls chan []interface{}
+}
+
+func (d *notificationDaemon) Notify(appName string, replacesId uint32, appIcon, summary, body string, actions []string, hints map[string]dbus.Variant, expire int32) (uint32, *dbus.Error) {
+	d.calls <- []interface{}{appName, summary, body, expire}
+	return 1, nil
+}
+
+// startSessionBus runs a private dbus-daemon and points the session bus variable to it
+func startSessionBus(t *testing.T) string {
+	t.Helper()
+	daemon, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon isn't installed")
+	}
+	cmd := exec.Command(daemon, "--session", "--nofork", "--print-address", "--address=unix:path="+filepath.Join(t.TempDir(), "bus"))
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	address = strings.TrimSpace(address)
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", address)
+	return address
+}
+
+func TestDesktopNotify(t *testing.T) {
+	startSessionBus(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := dialSessionBus(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	daemon := &notificationDaemon{calls: make(chan []interface{}, 1)}
+	if err := conn.Export(daemon, "/org/freedesktop/Notifications", "org.freedesktop.Notifications"); err != nil {
+		t.Fatal(err)
+	}
+	if reply, err := conn.RequestName("org.freedesktop.Notifications", dbus.NameFlagDoNotQueue); err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("couldn't own the notifications name, reply %v, err %v", reply, err)
+	}
+
+	n := newNotification("default", "anime3rb", "one-piece", "One Piece", 1100, 1101)
+	if err := (&Desktop{Timeout: 5 * time.Second}).Notify(ctx, n); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case call := <-daemon.calls:
+		if call[0] != "ani-ar" || call[1] != "One Piece" || call[2] != "episode 1101 is out" || call[3] != int32(5000) {
+			t.Fatalf("unexpected notification %v", call)
+		}
+	case <-ctx.Done():
+		t.Fatal("the notification never arrived")
+	}
+}
+
+func TestDesktopNotifyWithoutDaemon(t *testing.T) {
+	startSessionBus(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// nothing owns org.freedesktop.Notifications
+	if err := (&Desktop{}).Notify(ctx, Notification{Title: "One Piece"}); err == nil {
+		t.Fatal("the notification should fail without a notification daemon")
+	}
+}
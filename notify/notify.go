@@ -0,0 +1,161 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ani/ani-ar/config"
+	"github.com/goccy/go-json"
+)
+
+// Notification tells about new episodes of a followed anime
+type Notification struct {
+	Profile string `json:"profile"`
+	Source  string `json:"source"`
+	AnimeId string `json:"animeId"`
+	Anime   string `json:"anime"`
+	// the episodes count before and after the check
+	PreviousEpisodes int    `json:"previousEpisodes"`
+	Episodes         int    `json:"episodes"`
+	Title            string `json:"title"`
+	Message          string `json:"message"`
+}
+
+func newNotification(profileName, source, animeId, anime string, previous, episodes int) Notification {
+	message := fmt.Sprintf("episode %d is out", episodes)
+	if episodes-previous > 1 {
+		message = fmt.Sprintf("episodes %d to %d are out", previous+1, episodes)
+	}
+	return Notification{
+		Profile:          profileName,
+		Source:           source,
+		AnimeId:          animeId,
+		Anime:            anime,
+		PreviousEpisodes: previous,
+		Episodes:         episodes,
+		Title:            anime,
+		Message:          message,
+	}
+}
+
+// Backend delivers the notifications somewhere, eg. the desktop or a chat
+type Backend interface {
+	Name() string
+	Notify(ctx context.Context, n Notification) error
+}
+
+const (
+	BackendDesktop  = "desktop"
+	BackendWebhook  = "webhook"
+	BackendDiscord  = "discord"
+	BackendTelegram = "telegram"
+	BackendNtfy     = "ntfy"
+)
+
+var (
+	ErrNoBackends     = errors.New("no notification backends, add them to notify.json in the config folder")
+	ErrUnknownBackend = errors.New("unknown notification backend, it should be desktop, webhook, discord, telegram or ntfy")
+	ErrMissingUrl     = errors.New("the notification backend needs a url")
+)
+
+// BackendConfig is a backend in notify.json, the fields a backend doesn't use are ignored
+type BackendConfig struct {
+	Type string `json:"type"`
+	// the url the notifications are posted to, for telegram it's the
+	// sendMessage url of the bot and for ntfy the url of the topic
+	Url string `json:"url,omitempty"`
+	// the telegram chat the messages are sent to
+	ChatId string `json:"chatId,omitempty"`
+	// the ntfy access token
+	Token string `json:"token,omitempty"`
+}
+
+// Config is notify.json in the config folder
+type Config struct {
+	// how often the followed anime are checked, eg. 30m
+	Interval string          `json:"interval,omitempty"`
+	Backends []BackendConfig `json:"backends"`
+}
+
+const defaultInterval = time.Hour
+
+// LoadConfig reads notify.json, a missing file gives an empty config
+func LoadConfig() (*Config, error) {
+	c := &Config{}
+	b, err := os.ReadFile(config.Path("notify.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, errors.New("couldn't parse notify.json, reason: " + err.Error())
+	}
+	return c, nil
+}
+
+// GetInterval returns the checks interval, an hour when it's missing
+func (c *Config) GetInterval() (time.Duration, error) {
+	if c.Interval == "" {
+		return defaultInterval, nil
+	}
+	interval, err := time.ParseDuration(c.Interval)
+	if err != nil || interval < time.Minute {
+		return 0, errors.New("invalid interval in notify.json, it should be a duration of a minute or more, eg. 30m")
+	}
+	return interval, nil
+}
+
+// GetBackends builds the configured backends
+func (c *Config) GetBackends() ([]Backend, error) {
+	if len(c.Backends) == 0 {
+		return nil, ErrNoBackends
+	}
+	backends := make([]Backend, 0, len(c.Backends))
+	for _, bc := range c.Backends {
+		backend, err := NewBackend(bc)
+		if err != nil {
+			return nil, err
+		}
+		backends = append(backends, backend)
+	}
+	return backends, nil
+}
+
+func NewBackend(c BackendConfig) (Backend, error) {
+	if c.Type != BackendDesktop && c.Url == "" {
+		return nil, fmt.Errorf("%w: %s", ErrMissingUrl, c.Type)
+	}
+	switch c.Type {
+	case BackendDesktop:
+		return &Desktop{}, nil
+	case BackendWebhook:
+		return &Webhook{Url: c.Url}, nil
+	case BackendDiscord:
+		return &Discord{Url: c.Url}, nil
+	case BackendTelegram:
+		if c.ChatId == "" {
+			return nil, errors.New("the telegram backend needs a chatId")
+		}
+		return &Telegram{Url: c.Url, ChatId: c.ChatId}, nil
+	case BackendNtfy:
+		return &Ntfy{Url: c.Url, Token: c.Token}, nil
+	}
+	return nil, ErrUnknownBackend
+}
+
+// Send delivers the notification with every backend, the failures don't stop
+// the other backends and are returned together
+func Send(ctx context.Context, backends []Backend, n Notification) error {
+	var errs []error
+	for _, b := range backends {
+		if err := b.Notify(ctx, n); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", b.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,122 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
+func post(ctx context.Context, url, contentType string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "ani-ar")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		reason, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("status %d %s", res.StatusCode, strings.TrimSpace(string(reason)))
+	}
+	return nil
+}
+
+func postJSON(ctx context.Context, url string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return post(ctx, url, "application/json", body, nil)
+}
+
+// Webhook posts the notification as json to any url
+type Webhook struct {
+	Url string
+}
+
+func (w *Webhook) Name() string {
+	return BackendWebhook
+}
+
+func (w *Webhook) Notify(ctx context.Context, n Notification) error {
+	return postJSON(ctx, w.Url, struct {
+		Event string `json:"event"`
+		Notification
+	}{"new-episodes", n})
+}
+
+// Discord posts the notification as a message to a discord (or compatible,
+// eg. slack and mattermost) incoming webhook
+type Discord struct {
+	Url string
+}
+
+func (d *Discord) Name() string {
+	return BackendDiscord
+}
+
+func (d *Discord) Notify(ctx context.Context, n Notification) error {
+	return postJSON(ctx, d.Url, map[string]string{
+		"content": fmt.Sprintf("**%s**\n%s", n.Title, n.Message),
+		// slack and mattermost read the text field
+		"text": fmt.Sprintf("*%s*\n%s", n.Title, n.Message),
+	})
+}
+
+// Telegram sends the notification with the sendMessage method of a bot,
+// the url is https://api.telegram.org/bot<token>/sendMessage
+type Telegram struct {
+	Url    string
+	ChatId string
+}
+
+func (t *Telegram) Name() string {
+	return BackendTelegram
+}
+
+func (t *Telegram) Notify(ctx context.Context, n Notification) error {
+	return postJSON(ctx, t.Url, map[string]string{
+		"chat_id": t.ChatId,
+		"text":    n.Title + "\n" + n.Message,
+	})
+}
+
+// Ntfy publishes the notification to an ntfy topic, the url is the topic url
+// eg. https://ntfy.sh/my-anime
+type Ntfy struct {
+	Url string
+	// access token of a protected topic
+	Token string
+}
+
+func (t *Ntfy) Name() string {
+	return BackendNtfy
+}
+
+func (t *Ntfy) Notify(ctx context.Context, n Notification) error {
+	headers := map[string]string{
+		// the titles can be arabic, ntfy decodes the rfc 2047 encoded headers
+		"Title": mime.BEncoding.Encode("UTF-8", n.Title),
+		"Tags":  "tv",
+	}
+	if t.Token != "" {
+		headers["Authorization"] = "Bearer " + t.Token
+	}
+	return post(ctx, t.Url, "text/plain; charset=utf-8", []byte(n.Message), headers)
+}
@@ -0,0 +1,120 @@
+package notify
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+// received is a request the test server got
+type received struct {
+	method, path, contentType string
+	header                    http.Header
+	body                      []byte
+}
+
+func newTestServer(t *testing.T) (*httptest.Server, <-chan received) {
+	t.Helper()
+	requests := make(chan received, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- received{r.Method, r.URL.Path, r.Header.Get("Content-Type"), r.Header, body}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, requests
+}
+
+func testNotification() Notification {
+	return newNotification("default", "anime3rb", "one-piece", "ون بيس", 1100, 1102)
+}
+
+func notify(t *testing.T, b Backend) {
+	t.Helper()
+	if err := b.Notify(context.Background(), testNotification()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWebhookPostsTheNotification(t *testing.T) {
+	srv, requests := newTestServer(t)
+	notify(t, &Webhook{Url: srv.URL + "/hooks/anime"})
+
+	r := <-requests
+	if r.method != http.MethodPost || r.path != "/hooks/anime" || r.contentType != "application/json" {
+		t.Fatalf("got %s %s %s", r.method, r.path, r.contentType)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(r.body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload["event"] != "new-episodes" || payload["animeId"] != "one-piece" || payload["previousEpisodes"] != 1100.0 ||
+		payload["episodes"] != 1102.0 || payload["message"] != "episodes 1101 to 1102 are out" {
+		t.Fatalf("unexpected payload %s", r.body)
+	}
+}
+
+func TestDiscordPostsAMessage(t *testing.T) {
+	srv, requests := newTestServer(t)
+	notify(t, &Discord{Url: srv.URL + "/api/webhooks/1/token"})
+
+	r := <-requests
+	if r.method != http.MethodPost || r.path != "/api/webhooks/1/token" || r.contentType != "application/json" {
+		t.Fatalf("got %s %s %s", r.method, r.path, r.contentType)
+	}
+	var payload map[string]string
+	if err := json.Unmarshal(r.body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload["content"] != "**ون بيس**\nepisodes 1101 to 1102 are out" || payload["text"] != "*ون بيس*\nepisodes 1101 to 1102 are out" {
+		t.Fatalf("unexpected payload %s", r.body)
+	}
+}
+
+func TestTelegramSendsAMessageToTheChat(t *testing.T) {
+	srv, requests := newTestServer(t)
+	notify(t, &Telegram{Url: srv.URL + "/bot123:abc/sendMessage", ChatId: "-100200"})
+
+	r := <-requests
+	if r.method != http.MethodPost || r.path != "/bot123:abc/sendMessage" || r.contentType != "application/json" {
+		t.Fatalf("got %s %s %s", r.method, r.path, r.contentType)
+	}
+	var payload map[string]string
+	if err := json.Unmarshal(r.body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload["chat_id"] != "-100200" || payload["text"] != "ون بيس\nepisodes 1101 to 1102 are out" {
+		t.Fatalf("unexpected payload %s", r.body)
+	}
+}
+
+func TestNtfyPublishesToTheTopic(t *testing.T) {
+	srv, requests := newTestServer(t)
+	notify(t, &Ntfy{Url: srv.URL + "/my-anime", Token: "tk_secret"})
+
+	r := <-requests
+	if r.method != http.MethodPost || r.path != "/my-anime" || r.contentType != "text/plain; charset=utf-8" {
+		t.Fatalf("got %s %s %s", r.method, r.path, r.contentType)
+	}
+	if string(r.body) != "episodes 1101 to 1102 are out" {
+		t.Fatalf("unexpected body %q", r.body)
+	}
+	// the arabic title is rfc 2047 encoded
+	if r.header.Get("Title") != "=?UTF-8?b?2YjZhiDYqNmK2LM=?=" || r.header.Get("Tags") != "tv" || r.header.Get("Authorization") != "Bearer tk_secret" {
+		t.Fatalf("unexpected headers %v", r.header)
+	}
+}
+
+func TestBackendErrorHasTheStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "chat not found", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+	err := (&Telegram{Url: srv.URL, ChatId: "1"}).Notify(context.Background(), testNotification())
+	if err == nil || err.Error() != "status 400 chat not found" {
+		t.Fatalf("got %v", err)
+	}
+}
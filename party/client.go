@@ -0,0 +1,118 @@
+package party
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Path is the websocket endpoint of the party on the host server
+const Path = "/party/ws"
+
+// Join connects to the host at addr (eg. `192.168.1.10:8000`), starts the local
+// session for the episode the host is watching and keeps it in sync until
+// the session or the connection is closed. The api key is needed when the
+// host requires keys, it needs the stream scope
+func Join(addr, apiKey string, startSession func(Source) (Session, error)) error {
+	u := url.URL{Scheme: "ws", Host: addr, Path: Path}
+	header := http.Header{}
+	if apiKey != "" {
+		header.Set("Authorization", "Bearer "+apiKey)
+	}
+	ws, _, err := websocket.DefaultDialer.Dial(u.String(), header)
+	if err != nil {
+		return fmt.Errorf("couldn't join the party at %s, reason: %v", addr, err)
+	}
+	defer ws.Close()
+
+	var hello Message
+	if err := ws.ReadJSON(&hello); err != nil {
+		return err
+	}
+	if hello.Type != MessageHello || hello.Source == nil {
+		return errors.New("unexpected message from the party host")
+	}
+	log.Printf("[party] joined, watching %s episode %d\n", hello.Source.AnimeId, hello.Source.Episode)
+
+	session, err := startSession(*hello.Source)
+	if err != nil {
+		return err
+	}
+	t := newTracker(session)
+
+	remote := make(chan Message)
+	readErr := make(chan error, 1)
+	go func() {
+		for {
+			var m Message
+			if err := ws.ReadJSON(&m); err != nil {
+				readErr <- err
+				return
+			}
+			remote <- m
+		}
+	}()
+
+	// report sends the local user action to the host, it returns whether there was one
+	report := func() (bool, error) {
+		m, err := t.poll()
+		if err != nil || m == nil {
+			return false, err
+		}
+		return true, ws.WriteJSON(m)
+	}
+
+	// the player needs a moment to load the video before seeking
+	pending := &hello
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+
+	for {
+		select {
+		case m := <-remote:
+			if pending != nil {
+				pending = &m
+				continue
+			}
+			if m.Type == MessageSync {
+				// a user action since the last poll wins over the sync that
+				// would undo it, the host follows it instead
+				reported, err := report()
+				if err != nil {
+					return err
+				}
+				if reported {
+					continue
+				}
+			}
+			if err := t.apply(m, m.Type == MessageState); err != nil {
+				log.Printf("[party] couldn't apply the host state: %v\n", err)
+			}
+		case err := <-readErr:
+			return fmt.Errorf("lost the connection to the party host: %v", err)
+		case <-poll.C:
+			if pending != nil {
+				if _, err := session.Paused(); err != nil {
+					return err
+				}
+				if _, err := session.Position(); err != nil {
+					// not loaded yet
+					continue
+				}
+				if err := t.apply(*pending, true); err != nil {
+					return err
+				}
+				pending = nil
+				continue
+			}
+			if _, err := report(); err != nil {
+				return err
+			}
+		}
+	}
+}
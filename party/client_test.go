@@ -0,0 +1,146 @@
+package party
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// join runs Join against the server with the session and returns its error channel
+func join(t *testing.T, srv *httptest.Server, session *fakeSession) (<-chan error, <-chan Source) {
+	t.Helper()
+	joined := make(chan Source, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- Join(strings.TrimPrefix(srv.URL, "http://"), "", func(source Source) (Session, error) {
+			joined <- source
+			return session, nil
+		})
+	}()
+	return done, joined
+}
+
+func TestJoinFollowsTheHost(t *testing.T) {
+	host := newFakeSession(false, 120)
+	hub := NewHub(Source{AnimeId: "naruto", Episode: 3}, host)
+	srv := httptest.NewServer(hub)
+	defer srv.Close()
+	go hub.Run()
+	defer host.close()
+
+	peer := newFakeSession(true, 0)
+	done, joined := join(t, srv, peer)
+	select {
+	case source := <-joined:
+		if source != hub.Source {
+			t.Fatalf("the session should start the episode of the host, got %+v", source)
+		}
+	case err := <-done:
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out joining the party")
+	}
+
+	// the loaded session catches up with the host
+	waitFor(t, "the peer to catch up", func() bool {
+		seeks, pauses := peer.recorded()
+		return len(seeks) == 1 && len(pauses) == 1 && !pauses[0]
+	})
+	if seeks, _ := peer.recorded(); seeks[0] < 120 || seeks[0] > 122 {
+		t.Fatalf("the peer should seek to the host position, got %v", seeks)
+	}
+
+	// the host pauses
+	host.user(true, 200)
+	waitFor(t, "the peer to pause", func() bool {
+		seeks, pauses := peer.recorded()
+		return len(seeks) == 2 && len(pauses) == 2
+	})
+	seeks, pauses := peer.recorded()
+	if seeks[1] != 200 || !pauses[1] {
+		t.Fatalf("the peer should pause at 200, got seeks %v pauses %v", seeks, pauses)
+	}
+	if paused, _ := peer.Paused(); !paused {
+		t.Fatal("the peer session should be paused")
+	}
+
+	// the peer resumes, the host follows it
+	time.Sleep(settleDuration)
+	peer.user(false, 180)
+	waitFor(t, "the host to resume", func() bool {
+		seeks, pauses := host.recorded()
+		return len(seeks) == 1 && len(pauses) == 1
+	})
+	// the peer plays on until it's polled
+	if seeks, pauses := host.recorded(); seeks[0] < 180 || seeks[0] > 181 || pauses[0] {
+		t.Fatalf("the host should resume at 180, got seeks %v pauses %v", seeks, pauses)
+	}
+
+	// the user quits the player
+	peer.close()
+	select {
+	case err := <-done:
+		if err != errSessionClosed {
+			t.Fatalf("got %v, want the closed session", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Join should return once the session is closed")
+	}
+}
+
+func TestJoinCorrectsTheDrift(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	messages := make(chan Message)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+		ws.WriteJSON(Message{Type: MessageHello, Source: &Source{AnimeId: "naruto", Episode: 1}, Position: 10})
+		for m := range messages {
+			if err := ws.WriteJSON(m); err != nil {
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	peer := newFakeSession(true, 0)
+	done, _ := join(t, srv, peer)
+	waitFor(t, "the peer to load", func() bool {
+		seeks, _ := peer.recorded()
+		return len(seeks) == 1
+	})
+
+	// a small drift is left alone, the players don't play at the exact same pace
+	position, _ := peer.Position()
+	messages <- Message{Type: MessageSync, Position: position + maxDrift/2}
+	// a big one is seeked back in sync
+	messages <- Message{Type: MessageSync, Position: position + 60}
+	waitFor(t, "the drift to be corrected", func() bool {
+		seeks, _ := peer.recorded()
+		return len(seeks) >= 2
+	})
+	seeks, pauses := peer.recorded()
+	if len(seeks) != 2 || seeks[1] != position+60 {
+		t.Fatalf("only the big drift should be corrected, got seeks %v", seeks)
+	}
+	if len(pauses) != 1 {
+		t.Fatalf("the syncs shouldn't pause or resume the peer, got %v", pauses)
+	}
+
+	close(messages)
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("Join should return the lost connection")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Join should return once the host is gone")
+	}
+}
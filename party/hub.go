@@ -0,0 +1,128 @@
+package party
+
+import (
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var upgrader = websocket.Upgrader{
+	// peers are ani-ar instances, not browsers
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+type peer struct {
+	ws   *websocket.Conn
+	send chan Message
+}
+
+func (p *peer) writeLoop() {
+	for m := range p.send {
+		if err := p.ws.WriteJSON(m); err != nil {
+			p.ws.Close()
+			return
+		}
+	}
+}
+
+// Hub is the host side of a watch party, it's served as a websocket
+// endpoint and keeps the peers in sync with the host session
+type Hub struct {
+	Source  Source
+	tracker *tracker
+
+	mu    sync.Mutex
+	peers map[*peer]bool
+}
+
+func NewHub(source Source, session Session) *Hub {
+	return &Hub{
+		Source:  source,
+		tracker: newTracker(session),
+		peers:   make(map[*peer]bool),
+	}
+}
+
+func (h *Hub) broadcast(m Message, except *peer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for p := range h.peers {
+		if p == except {
+			continue
+		}
+		select {
+		case p.send <- m:
+		default:
+			// slow peer, it will catch up with the next sync
+		}
+	}
+}
+
+// ServeHTTP upgrades the request and handles the peer until it leaves
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	p := &peer{ws: ws, send: make(chan Message, 16)}
+	go p.writeLoop()
+
+	hello := h.tracker.state(MessageHello)
+	hello.Source = &h.Source
+	p.send <- hello
+
+	h.mu.Lock()
+	h.peers[p] = true
+	count := len(h.peers)
+	h.mu.Unlock()
+	log.Printf("[party] %s joined, %d watching\n", r.RemoteAddr, count)
+
+	defer func() {
+		h.mu.Lock()
+		delete(h.peers, p)
+		h.mu.Unlock()
+		close(p.send)
+		ws.Close()
+		log.Printf("[party] %s left\n", r.RemoteAddr)
+	}()
+
+	for {
+		var m Message
+		if err := ws.ReadJSON(&m); err != nil {
+			return
+		}
+		if m.Type != MessageState {
+			continue
+		}
+		if err := h.tracker.apply(m, true); err != nil {
+			log.Printf("[party] couldn't apply the peer state: %v\n", err)
+		}
+		h.broadcast(m, p)
+	}
+}
+
+// Run follows the host session, it returns when the session is closed
+func (h *Hub) Run() error {
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+	syncTick := time.NewTicker(syncInterval)
+	defer syncTick.Stop()
+
+	for {
+		select {
+		case <-poll.C:
+			m, err := h.tracker.poll()
+			if err != nil {
+				return err
+			}
+			if m != nil {
+				h.broadcast(*m, nil)
+			}
+		case <-syncTick.C:
+			h.broadcast(h.tracker.state(MessageSync), nil)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package party
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// fakeSession is a player playing in real time, it records the remote
+// changes applied to it
+type fakeSession struct {
+	mu       sync.Mutex
+	paused   bool
+	position float64
+	at       time.Time
+	closed   bool
+	seeks    []float64
+	pauses   []bool
+}
+
+func newFakeSession(paused bool, position float64) *fakeSession {
+	return &fakeSession{paused: paused, position: position, at: time.Now()}
+}
+
+var errSessionClosed = errors.New("the session is closed")
+
+// must be called with the lock held
+func (s *fakeSession) current() float64 {
+	if s.paused {
+		return s.position
+	}
+	return s.position + time.Since(s.at).Seconds()
+}
+
+func (s *fakeSession) Paused() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false, errSessionClosed
+	}
+	return s.paused, nil
+}
+
+func (s *fakeSession) SetPause(paused bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pauses = append(s.pauses, paused)
+	s.position, s.at, s.paused = s.current(), time.Now(), paused
+	return nil
+}
+
+func (s *fakeSession) Position() (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return 0, errSessionClosed
+	}
+	return s.current(), nil
+}
+
+func (s *fakeSession) Seek(position float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seeks = append(s.seeks, position)
+	s.position, s.at = position, time.Now()
+	return nil
+}
+
+// user changes the session like its user does, the change isn't recorded
+func (s *fakeSession) user(paused bool, position float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paused, s.position, s.at = paused, position, time.Now()
+}
+
+func (s *fakeSession) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+}
+
+func (s *fakeSession) recorded() ([]float64, []bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]float64(nil), s.seeks...), append([]bool(nil), s.pauses...)
+}
+
+func waitFor(t *testing.T, what string, done func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !done() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func dialHub(t *testing.T, srv *httptest.Server) *websocket.Conn {
+	t.Helper()
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+Path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ws.Close() })
+	return ws
+}
+
+func readMessage(t *testing.T, ws *websocket.Conn) Message {
+	t.Helper()
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var m Message
+	if err := ws.ReadJSON(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestHubSendsTheStatesToThePeers(t *testing.T) {
+	host := newFakeSession(false, 30)
+	hub := NewHub(Source{AnimeId: "naruto", Episode: 3}, host)
+	srv := httptest.NewServer(hub)
+	defer srv.Close()
+
+	first, second := dialHub(t, srv), dialHub(t, srv)
+	for _, ws := range []*websocket.Conn{first, second} {
+		hello := readMessage(t, ws)
+		if hello.Type != MessageHello || hello.Source == nil || *hello.Source != hub.Source {
+			t.Fatalf("the peer should be greeted with the episode, got %+v", hello)
+		}
+		if hello.Paused || hello.Position < 30 || hello.Position > 31 {
+			t.Fatalf("the hello should have the host state, got %+v", hello)
+		}
+	}
+	waitFor(t, "the peers to join", func() bool {
+		hub.mu.Lock()
+		defer hub.mu.Unlock()
+		return len(hub.peers) == 2
+	})
+
+	// the first peer pauses at 42, the host and the second peer follow it
+	if err := first.WriteJSON(Message{Type: MessageState, Paused: true, Position: 42}); err != nil {
+		t.Fatal(err)
+	}
+	state := readMessage(t, second)
+	if state.Type != MessageState || !state.Paused || state.Position != 42 {
+		t.Fatalf("the state should be sent to the other peers, got %+v", state)
+	}
+	seeks, pauses := host.recorded()
+	if len(seeks) != 1 || seeks[0] != 42 || len(pauses) != 1 || !pauses[0] {
+		t.Fatalf("the host should pause at 42, got seeks %v pauses %v", seeks, pauses)
+	}
+
+	// the state isn't sent back to its peer
+	first.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	var m Message
+	if err := first.ReadJSON(&m); err == nil {
+		t.Fatalf("the peer shouldn't get its own state, got %+v", m)
+	}
+}
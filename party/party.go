@@ -0,0 +1,149 @@
+package party
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Session is the local player being synced, it's implemented by player.MpvSession
+type Session interface {
+	Paused() (bool, error)
+	SetPause(paused bool) error
+	// Position returns the playback position in seconds
+	Position() (float64, error)
+	Seek(position float64) error
+}
+
+// Source is the episode being watched, peers resolve it with their own fetcher
+type Source struct {
+	AnimeId string `json:"animeId"`
+	Episode int    `json:"episode"`
+}
+
+const (
+	// sent by the host to a peer right after it joins
+	MessageHello = "hello"
+	// a peer paused, resumed or seeked, everyone applies it
+	MessageState = "state"
+	// periodic host position used to correct the drift
+	MessageSync = "sync"
+)
+
+type Message struct {
+	Type     string  `json:"type"`
+	Source   *Source `json:"source,omitempty"`
+	Paused   bool    `json:"paused"`
+	Position float64 `json:"position"`
+}
+
+const (
+	pollInterval = 500 * time.Millisecond
+	syncInterval = 2 * time.Second
+	// a position jump bigger than this between two polls is a user seek
+	seekThreshold = 1.5
+	// peers further than this from the host are seeked back in sync
+	maxDrift = 2.0
+	// ignore local changes for a while after applying a remote state,
+	// players take some time to apply a seek
+	settleDuration = time.Second
+)
+
+// tracker follows the local session to detect the user actions
+// and applies the remote ones without reporting them back
+type tracker struct {
+	session Session
+
+	mu          sync.Mutex
+	initialized bool
+	paused      bool
+	position    float64
+	at          time.Time
+	settleUntil time.Time
+}
+
+func newTracker(s Session) *tracker {
+	return &tracker{session: s}
+}
+
+func (t *tracker) expectedPosition(now time.Time) float64 {
+	if t.paused {
+		return t.position
+	}
+	return t.position + now.Sub(t.at).Seconds()
+}
+
+// poll reads the local session and returns a state message when the local user changed something
+func (t *tracker) poll() (*Message, error) {
+	paused, err := t.session.Paused()
+	if err != nil {
+		return nil, err
+	}
+	position, err := t.session.Position()
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	changed := t.initialized &&
+		now.After(t.settleUntil) &&
+		(paused != t.paused || math.Abs(position-t.expectedPosition(now)) > seekThreshold)
+
+	t.initialized = true
+	t.paused, t.position, t.at = paused, position, now
+	if !changed {
+		return nil, nil
+	}
+	return &Message{Type: MessageState, Paused: paused, Position: position}, nil
+}
+
+// state returns the current local state
+func (t *tracker) state(messageType string) Message {
+	t.mu.Lock()
+	initialized := t.initialized
+	t.mu.Unlock()
+	if !initialized {
+		// nothing polled yet, ask the session directly
+		paused, _ := t.session.Paused()
+		position, _ := t.session.Position()
+		return Message{Type: messageType, Paused: paused, Position: position}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return Message{Type: messageType, Paused: t.paused, Position: t.expectedPosition(time.Now())}
+}
+
+// apply sets the local session to the remote state, the position is only
+// changed when forced (user action) or when the drift is too big
+func (t *tracker) apply(m Message, force bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+
+	changed := false
+	if !t.initialized || m.Paused != t.paused {
+		if err := t.session.SetPause(m.Paused); err != nil {
+			return err
+		}
+		changed = true
+	}
+	position := t.expectedPosition(now)
+	if force || !t.initialized || math.Abs(position-m.Position) > maxDrift {
+		if err := t.session.Seek(m.Position); err != nil {
+			return err
+		}
+		position = m.Position
+		changed = true
+	}
+
+	t.initialized = true
+	t.paused, t.position, t.at = m.Paused, position, now
+	// a sync that changed nothing doesn't hide the user actions
+	if changed {
+		t.settleUntil = now.Add(settleDuration)
+	}
+	return nil
+}
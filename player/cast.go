@@ -0,0 +1,20 @@
+package player
+
+import (
+	"time"
+
+	"github.com/ani/ani-ar/cast"
+)
+
+// how long to wait for the devices to answer the SSDP and mDNS searches
+const castDiscoveryTimeout = 3 * time.Second
+
+// FindCastDevice looks up the Chromecast or DLNA/UPnP renderer matching the device name
+func FindCastDevice(deviceName string) (*cast.Device, error) {
+	return cast.FindDevice(deviceName, castDiscoveryTimeout)
+}
+
+// DiscoverCastDevices lists the cast devices found on the local network
+func DiscoverCastDevices() ([]*cast.Device, error) {
+	return cast.Discover(castDiscoveryTimeout)
+}
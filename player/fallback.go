@@ -0,0 +1,64 @@
+package player
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/ani/ani-ar/types"
+)
+
+// what to do when there are no players installed (eg. on a server or over ssh)
+const (
+	// print the url and a copyable player command
+	FallbackPrint = "print"
+	// open the url in the system browser
+	FallbackBrowser = "browser"
+	// serve the video through the local stream proxy and print its link
+	FallbackServe = "serve"
+)
+
+var Fallbacks = []string{FallbackPrint, FallbackBrowser, FallbackServe}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// PrintCommand writes the video url and the mpv command playing it with the needed headers
+func PrintCommand(w io.Writer, video types.AniVideo, title string) {
+	fmt.Fprintf(w, "url: %s\n", video.Src)
+
+	keys := make([]string, 0, len(video.Headers))
+	for k := range video.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "header: %s: %s\n", k, video.Headers[k])
+	}
+
+	args := []string{"mpv", shellQuote("--force-media-title=" + title)}
+	// the same header flags the player gets, the values can have commas
+	for _, arg := range mpvHeaderArgs(video.Headers) {
+		args = append(args, shellQuote(arg))
+	}
+	args = append(args, shellQuote(video.Src))
+	fmt.Fprintf(w, "\n%s\n", strings.Join(args, " "))
+}
+
+// OpenInBrowser opens the url with the system default handler
+func OpenInBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
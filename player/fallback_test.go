@@ -0,0 +1,32 @@
+package player
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ani/ani-ar/types"
+)
+
+func TestPrintCommandSendsEveryHeader(t *testing.T) {
+	var b strings.Builder
+	PrintCommand(&b, types.AniVideo{
+		Src: "https://host/video.m3u8",
+		Headers: map[string]string{
+			"Referer":    "https://allanime.to",
+			"User-Agent": "Mozilla/5.0 (X11, Linux)",
+		},
+	}, "naruto-episode-1")
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	command := lines[len(lines)-1]
+	want := "mpv '--force-media-title=naruto-episode-1'" +
+		" '--http-header-fields-append=Referer: https://allanime.to'" +
+		" '--http-header-fields-append=User-Agent: Mozilla/5.0 (X11, Linux)'" +
+		" 'https://host/video.m3u8'"
+	if command != want {
+		t.Fatalf("got\n%s\nwant\n%s", command, want)
+	}
+	if !strings.Contains(b.String(), "header: User-Agent: Mozilla/5.0 (X11, Linux)\n") {
+		t.Fatalf("the headers should be listed:\n%s", b.String())
+	}
+}
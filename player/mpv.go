@@ -0,0 +1,217 @@
+package player
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ani/ani-ar/types"
+	"github.com/goccy/go-json"
+)
+
+// MpvSession is an mpv process controlled through its JSON IPC socket
+type MpvSession struct {
+	Cmd *exec.Cmd
+
+	conn       net.Conn
+	socketPath string
+
+	mu      sync.Mutex
+	nextId  int
+	pending map[int]chan mpvResponse
+	closed  bool
+
+	// closed on the first file-loaded event and when mpv exits
+	fileLoaded     chan struct{}
+	fileLoadedOnce sync.Once
+	done           chan struct{}
+}
+
+type mpvResponse struct {
+	RequestId int             `json:"request_id"`
+	Error     string          `json:"error"`
+	Data      json.RawMessage `json:"data"`
+	Event     string          `json:"event"`
+}
+
+// ErrMpvClosed is returned by the session commands once mpv exits
+var ErrMpvClosed = errors.New("mpv session is closed")
+
+// StartMpvSession runs mpv with an ipc socket and connects to it
+func StartMpvSession(video types.AniVideo, title string, extraArgs ...string) (*MpvSession, error) {
+	if !commandExists("mpv") {
+		return nil, errors.New("mpv is required for this feature, try installing it")
+	}
+	socketPath := filepath.Join(os.TempDir(), fmt.Sprintf("ani-ar-mpv-%d-%d.sock", os.Getpid(), time.Now().UnixNano()))
+
+	args := append([]string{"--title=" + title, "--input-ipc-server=" + socketPath}, mpvHeaderArgs(video.Headers)...)
+	args = append(args, extraArgs...)
+	cmd := exec.Command("mpv", append(args, video.Src)...)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	// mpv creates the socket shortly after starting
+	var conn net.Conn
+	var err error
+	for i := 0; i < 50; i++ {
+		conn, err = net.Dial("unix", socketPath)
+		if err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if err != nil {
+		cmd.Process.Kill()
+		return nil, errors.New("couldn't connect to the mpv ipc socket, reason: " + err.Error())
+	}
+
+	s := newMpvSession(conn)
+	s.Cmd, s.socketPath = cmd, socketPath
+	return s, nil
+}
+
+// newMpvSession controls the mpv connected to conn
+func newMpvSession(conn net.Conn) *MpvSession {
+	s := &MpvSession{
+		conn:       conn,
+		pending:    make(map[int]chan mpvResponse),
+		fileLoaded: make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go s.readLoop()
+	return s
+}
+
+func (s *MpvSession) readLoop() {
+	scanner := bufio.NewScanner(s.conn)
+	for scanner.Scan() {
+		var res mpvResponse
+		if err := json.Unmarshal(scanner.Bytes(), &res); err != nil {
+			continue
+		}
+		if res.Event != "" {
+			if res.Event == "file-loaded" {
+				s.fileLoadedOnce.Do(func() { close(s.fileLoaded) })
+			}
+			continue
+		}
+		s.mu.Lock()
+		ch, ok := s.pending[res.RequestId]
+		delete(s.pending, res.RequestId)
+		s.mu.Unlock()
+		if ok {
+			ch <- res
+		}
+	}
+
+	s.mu.Lock()
+	s.closed = true
+	for id, ch := range s.pending {
+		close(ch)
+		delete(s.pending, id)
+	}
+	s.mu.Unlock()
+	close(s.done)
+}
+
+// WaitFileLoaded blocks until mpv loaded the file, the properties of the file
+// (eg. its chapters) are reset on load so they can only be changed after it
+func (s *MpvSession) WaitFileLoaded() error {
+	// the file may have been loaded before the socket was connected, the
+	// format is only known once it is
+	if _, err := s.Command("get_property", "file-format"); err == nil {
+		return nil
+	}
+	select {
+	case <-s.fileLoaded:
+		return nil
+	case <-s.done:
+		return ErrMpvClosed
+	}
+}
+
+// Command sends a raw mpv ipc command and returns its data
+func (s *MpvSession) Command(args ...interface{}) (json.RawMessage, error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, ErrMpvClosed
+	}
+	s.nextId++
+	id := s.nextId
+	ch := make(chan mpvResponse, 1)
+	s.pending[id] = ch
+	s.mu.Unlock()
+
+	b, err := json.Marshal(map[string]interface{}{"command": args, "request_id": id})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.conn.Write(append(b, '\n')); err != nil {
+		// the socket is gone with mpv
+		return nil, ErrMpvClosed
+	}
+
+	select {
+	case res, ok := <-ch:
+		if !ok {
+			return nil, ErrMpvClosed
+		}
+		if res.Error != "success" {
+			return nil, fmt.Errorf("mpv command %v failed: %s", args[0], res.Error)
+		}
+		return res.Data, nil
+	case <-time.After(5 * time.Second):
+		s.mu.Lock()
+		delete(s.pending, id)
+		s.mu.Unlock()
+		return nil, fmt.Errorf("mpv command %v timed out", args[0])
+	}
+}
+
+func (s *MpvSession) Paused() (bool, error) {
+	data, err := s.Command("get_property", "pause")
+	if err != nil {
+		return false, err
+	}
+	var paused bool
+	err = json.Unmarshal(data, &paused)
+	return paused, err
+}
+
+func (s *MpvSession) SetPause(paused bool) error {
+	_, err := s.Command("set_property", "pause", paused)
+	return err
+}
+
+// Position returns the playback position in seconds
+func (s *MpvSession) Position() (float64, error) {
+	data, err := s.Command("get_property", "time-pos")
+	if err != nil {
+		return 0, err
+	}
+	var pos float64
+	err = json.Unmarshal(data, &pos)
+	return pos, err
+}
+
+// Seek jumps to the absolute position in seconds
+func (s *MpvSession) Seek(position float64) error {
+	_, err := s.Command("seek", position, "absolute")
+	return err
+}
+
+// Wait blocks until mpv exits and cleans the ipc socket
+func (s *MpvSession) Wait() error {
+	err := s.Cmd.Wait()
+	s.conn.Close()
+	os.Remove(s.socketPath)
+	return err
+}
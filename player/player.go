@@ -2,43 +2,70 @@ package player
 
 import (
 	"errors"
+	"net/http"
 	"os/exec"
+	"sort"
+
+	"github.com/ani/ani-ar/types"
 )
 
 type Player struct {
 	bin     string
-	execute func(url, title string) *exec.Cmd
+	execute func(video types.AniVideo, title string) *exec.Cmd
 }
 
 var players []Player = []Player{
 	{
 		bin: "mpv",
-		execute: func(u, t string) *exec.Cmd {
-			cmd := exec.Command(
-				"mpv",
-				"--title="+t,
-				u)
+		execute: func(v types.AniVideo, t string) *exec.Cmd {
+			args := append([]string{"--title=" + t}, mpvHeaderArgs(v.Headers)...)
+			cmd := exec.Command("mpv", append(args, v.Src)...)
 			return cmd
 		},
 	},
 	{
 		bin: "vlc",
-		execute: func(u, t string) *exec.Cmd {
-			cmd := exec.Command(
-				"vlc",
-				"--play-and-exit",
-				"--meta-title="+t,
-				u)
+		execute: func(v types.AniVideo, t string) *exec.Cmd {
+			args := []string{"--play-and-exit", "--meta-title=" + t}
+			// vlc can only send these two headers
+			for k, val := range v.Headers {
+				switch http.CanonicalHeaderKey(k) {
+				case "Referer":
+					args = append(args, "--http-referrer="+val)
+				case "User-Agent":
+					args = append(args, "--http-user-agent="+val)
+				}
+			}
+			cmd := exec.Command("vlc", append(args, v.Src)...)
 			return cmd
 		},
 	},
 }
 
-func RunVideo(url, title string) (*exec.Cmd, error) {
+// mpvHeaderArgs returns the mpv options sending the request headers the video
+// host expects, one option per header so the values can have commas
+func mpvHeaderArgs(headers map[string]string) []string {
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	args := make([]string, 0, len(keys))
+	for _, k := range keys {
+		args = append(args, "--http-header-fields-append="+k+": "+headers[k])
+	}
+	return args
+}
+
+// ErrNoPlayers is returned when neither mpv nor vlc are installed
+var ErrNoPlayers = errors.New("you don't any players to play the episode try installing vlc or mpv")
+
+// RunVideo starts the first installed player with the video and the headers its host expects
+func RunVideo(video types.AniVideo, title string) (*exec.Cmd, error) {
 	for _, player := range players {
 		exist := commandExists(player.bin)
 		if exist {
-			cmd := player.execute(url, title)
+			cmd := player.execute(video, title)
 			err := cmd.Start()
 			if err != nil {
 				return nil, err
@@ -47,7 +74,7 @@ func RunVideo(url, title string) (*exec.Cmd, error) {
 			return cmd, nil
 		}
 	}
-	return nil, errors.New("you don't any players to play the episode try installing vlc or mpv")
+	return nil, ErrNoPlayers
 }
 
 func commandExists(cmd string) bool {
@@ -0,0 +1,93 @@
+package player
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ani/ani-ar/skip"
+	"github.com/ani/ani-ar/types"
+	"github.com/goccy/go-json"
+)
+
+var skipChapterTitles = map[string]string{
+	skip.TypeOpening: "Opening",
+	skip.TypeEnding:  "Ending",
+}
+
+type mpvChapter struct {
+	Title string  `json:"title"`
+	Time  float64 `json:"time"`
+}
+
+// AddSkipChapters adds a chapter at the start and the end of every interval
+// to the chapters of the file, so they can be skipped with the mpv chapter
+// keys. It waits for the file to be loaded
+func (s *MpvSession) AddSkipChapters(intervals []skip.Interval) error {
+	if err := s.WaitFileLoaded(); err != nil {
+		return err
+	}
+	data, err := s.Command("get_property", "chapter-list")
+	if err != nil {
+		return err
+	}
+	var chapters []mpvChapter
+	if err := json.Unmarshal(data, &chapters); err != nil {
+		return err
+	}
+	for _, i := range intervals {
+		title, ok := skipChapterTitles[i.Type]
+		if !ok {
+			title = i.Type
+		}
+		chapters = append(chapters,
+			mpvChapter{Title: title, Time: i.Start},
+			mpvChapter{Title: "Episode", Time: i.End},
+		)
+	}
+	sort.SliceStable(chapters, func(i, j int) bool {
+		return chapters[i].Time < chapters[j].Time
+	})
+	_, err = s.Command("set_property", "chapter-list", chapters)
+	return err
+}
+
+// AutoSkip seeks to the end of an interval once the playback enters it, each
+// interval is skipped once so seeking back into it lets the user watch it.
+// It returns when the session is closed
+func (s *MpvSession) AutoSkip(intervals []skip.Interval) {
+	skipped := make([]bool, len(intervals))
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		pos, err := s.Position()
+		if err == ErrMpvClosed {
+			return
+		}
+		if err != nil {
+			// not playing yet
+			continue
+		}
+		for idx, i := range intervals {
+			if skipped[idx] || pos < i.Start || pos >= i.End-1 {
+				continue
+			}
+			skipped[idx] = true
+			s.Seek(i.End)
+		}
+	}
+}
+
+// RunVideoWithSkipTimes plays the video in mpv and skips the given intervals
+func RunVideoWithSkipTimes(video types.AniVideo, title string, intervals []skip.Interval) (*MpvSession, error) {
+	s, err := StartMpvSession(video, title)
+	if err != nil {
+		return nil, err
+	}
+	if len(intervals) > 0 {
+		// older mpv versions have a read only chapter list, auto skip still works
+		go s.AddSkipChapters(intervals)
+		go s.AutoSkip(intervals)
+	}
+	return s, nil
+}
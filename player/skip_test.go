@@ -0,0 +1,105 @@
+package player
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ani/ani-ar/skip"
+	"github.com/goccy/go-json"
+)
+
+type mpvRequest struct {
+	Command   []interface{} `json:"command"`
+	RequestId int           `json:"request_id"`
+}
+
+// fakeMpv answers the ipc commands like an mpv that loads its file once the
+// chapters are first asked for, the chapter list it ends with is sent on chapters
+func fakeMpv(t *testing.T, conn net.Conn, fileChapters []mpvChapter, chapters chan<- []mpvChapter) {
+	t.Helper()
+	reply := func(v interface{}) {
+		b, _ := json.Marshal(v)
+		conn.Write(append(b, '\n'))
+	}
+	loaded := false
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var req mpvRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			t.Error(err)
+			return
+		}
+		switch req.Command[0] {
+		case "get_property":
+			switch {
+			case req.Command[1] == "file-format" && !loaded:
+				reply(map[string]interface{}{"request_id": req.RequestId, "error": "property unavailable"})
+				// the file finishes loading while the chapters wait for it
+				loaded = true
+				reply(map[string]string{"event": "file-loaded"})
+			case req.Command[1] == "chapter-list":
+				reply(map[string]interface{}{"request_id": req.RequestId, "error": "success", "data": fileChapters})
+			default:
+				reply(map[string]interface{}{"request_id": req.RequestId, "error": "success", "data": "mp4"})
+			}
+		case "set_property":
+			b, _ := json.Marshal(req.Command[2])
+			var list []mpvChapter
+			json.Unmarshal(b, &list)
+			reply(map[string]interface{}{"request_id": req.RequestId, "error": "success"})
+			chapters <- list
+		}
+	}
+}
+
+func TestAddSkipChapters(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	fileChapters := []mpvChapter{{Title: "Part A", Time: 0}, {Title: "Part B", Time: 700}}
+	chapters := make(chan []mpvChapter, 1)
+	go fakeMpv(t, server, fileChapters, chapters)
+
+	s := newMpvSession(client)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- s.AddSkipChapters([]skip.Interval{
+			{Type: skip.TypeOpening, Start: 60, End: 150},
+			{Type: skip.TypeEnding, Start: 1300, End: 1390},
+		})
+	}()
+
+	select {
+	case err := <-errs:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the chapters were never set")
+	}
+	got := <-chapters
+	want := []mpvChapter{
+		{"Part A", 0}, {"Opening", 60}, {"Episode", 150}, {"Part B", 700}, {"Ending", 1300}, {"Episode", 1390},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("chapters %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("chapters %v, want %v", got, want)
+		}
+	}
+}
+
+func TestWaitFileLoadedClosed(t *testing.T) {
+	client, server := net.Pipe()
+	s := newMpvSession(client)
+	// mpv exits before loading the file
+	server.Close()
+	if err := s.WaitFileLoaded(); err != ErrMpvClosed {
+		t.Fatalf("got %v, want ErrMpvClosed", err)
+	}
+}
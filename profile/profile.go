@@ -0,0 +1,204 @@
+package profile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/ani/ani-ar/config"
+	"github.com/goccy/go-json"
+)
+
+// Default is the profile used when none is picked, its files stay in the
+// config folder where they were before profiles existed
+const Default = "default"
+
+// Header is the request header picking the profile on the api server
+const Header = "X-Ani-Profile"
+
+const (
+	TranslationSub = "sub"
+	TranslationDub = "dub"
+)
+
+var (
+	ErrInvalidName        = errors.New("invalid profile name, it can only have letters, digits, - and _")
+	ErrNotFound           = errors.New("profile not found")
+	ErrExists             = errors.New("profile already exists")
+	ErrRemoveDefault      = errors.New("the default profile can't be removed")
+	ErrInvalidTranslation = errors.New("invalid translation, it should be sub or dub")
+	ErrInvalidQuality     = errors.New("invalid quality, it should be a resolution like 1080")
+)
+
+var (
+	nameRe    = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,32}$`)
+	qualityRe = regexp.MustCompile(`^[0-9]{3,4}$`)
+)
+
+// Settings are the preferences of a profile, the empty ones use the defaults
+type Settings struct {
+	// the fetcher the anime are searched in, eg. anime3rb
+	Source string `json:"source,omitempty"`
+	// the preferred video resolution, eg. 1080
+	Quality string `json:"quality,omitempty"`
+	// sub or dub, only allanime has dubbed episodes
+	Translation string `json:"translation,omitempty"`
+}
+
+// Validate checks the quality and the translation, the source is checked by
+// the fetcher package
+func (s Settings) Validate() error {
+	if s.Quality != "" && !qualityRe.MatchString(s.Quality) {
+		return ErrInvalidQuality
+	}
+	if s.Translation != "" && s.Translation != TranslationSub && s.Translation != TranslationDub {
+		return ErrInvalidTranslation
+	}
+	return nil
+}
+
+var (
+	mu       sync.RWMutex
+	active   = Default
+	settings = make(map[string]Settings)
+)
+
+func IsValidName(name string) bool {
+	return nameRe.MatchString(name)
+}
+
+// Active returns the profile picked with --profile or in the tui
+func Active() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return active
+}
+
+// SetActive picks the profile used by the stores and the default fetcher,
+// a new profile is created the first time something is saved in it
+func SetActive(name string) error {
+	if name == "" {
+		name = Default
+	}
+	if !IsValidName(name) {
+		return ErrInvalidName
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	active = name
+	return nil
+}
+
+// Dir returns the folder of the profile files
+func Dir(name string) string {
+	if name == Default {
+		return config.Dir()
+	}
+	return filepath.Join(config.Dir(), "profiles", name)
+}
+
+// Path returns the path of a file of the profile, eg. its history
+func Path(name, file string) string {
+	return filepath.Join(Dir(name), file)
+}
+
+func Exists(name string) bool {
+	if name == Default {
+		return true
+	}
+	if !IsValidName(name) {
+		return false
+	}
+	info, err := os.Stat(Dir(name))
+	return err == nil && info.IsDir()
+}
+
+// List returns the default profile followed by the other ones by name
+func List() []string {
+	names := []string{Default}
+	entries, err := os.ReadDir(filepath.Join(config.Dir(), "profiles"))
+	if err != nil {
+		return names
+	}
+	var others []string
+	for _, e := range entries {
+		if e.IsDir() && IsValidName(e.Name()) && e.Name() != Default {
+			others = append(others, e.Name())
+		}
+	}
+	sort.Strings(others)
+	return append(names, others...)
+}
+
+func Create(name string) error {
+	if !IsValidName(name) {
+		return ErrInvalidName
+	}
+	if Exists(name) {
+		return ErrExists
+	}
+	return os.MkdirAll(Dir(name), os.ModePerm)
+}
+
+// Remove deletes the profile with its history, watchlist and settings
+func Remove(name string) error {
+	if name == Default {
+		return ErrRemoveDefault
+	}
+	if !Exists(name) {
+		return ErrNotFound
+	}
+	mu.Lock()
+	delete(settings, name)
+	if active == name {
+		active = Default
+	}
+	mu.Unlock()
+	return os.RemoveAll(Dir(name))
+}
+
+// GetSettings returns the settings of the profile, a missing or broken
+// settings file gives the default settings
+func GetSettings(name string) Settings {
+	mu.RLock()
+	s, found := settings[name]
+	mu.RUnlock()
+	if found {
+		return s
+	}
+
+	b, err := os.ReadFile(Path(name, "settings.json"))
+	if err == nil {
+		json.Unmarshal(b, &s)
+	}
+	mu.Lock()
+	settings[name] = s
+	mu.Unlock()
+	return s
+}
+
+func SaveSettings(name string, s Settings) error {
+	if !IsValidName(name) {
+		return ErrInvalidName
+	}
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(Dir(name), os.ModePerm); err != nil {
+		return err
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(Path(name, "settings.json"), b, 0644); err != nil {
+		return err
+	}
+	mu.Lock()
+	settings[name] = s
+	mu.Unlock()
+	return nil
+}
@@ -0,0 +1,93 @@
+// Package scraper sends the requests to the anime sources and jikan, the
+// number of them in flight is capped so the clients of the server can't
+// make it hammer the upstreams
+package scraper
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultConcurrency is the number of requests in flight by default
+const DefaultConcurrency = 8
+
+type semaphore chan struct{}
+
+// nil when the requests aren't capped
+var slots atomic.Pointer[semaphore]
+
+func init() {
+	SetConcurrency(DefaultConcurrency)
+}
+
+// SetConcurrency changes the number of requests in flight, 0 removes the cap.
+// The requests already in flight keep their slot
+func SetConcurrency(n int) {
+	if n <= 0 {
+		slots.Store(nil)
+		return
+	}
+	s := make(semaphore, n)
+	slots.Store(&s)
+}
+
+// limitedTransport holds a slot from the request until its body is closed or read
+type limitedTransport struct {
+	next http.RoundTripper
+}
+
+func (t limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	s := slots.Load()
+	if s == nil {
+		return t.next.RoundTrip(req)
+	}
+	select {
+	case *s <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	var once sync.Once
+	release := func() { once.Do(func() { <-*s }) }
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	res.Body = &releasingBody{ReadCloser: res.Body, release: release}
+	return res, nil
+}
+
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil {
+		b.release()
+	}
+	return n, err
+}
+
+func (b *releasingBody) Close() error {
+	b.release()
+	return b.ReadCloser.Close()
+}
+
+// Client is the http client of the scrapers
+var Client = &http.Client{
+	Transport: limitedTransport{next: http.DefaultTransport},
+	Timeout:   time.Minute,
+}
+
+func Get(url string) (*http.Response, error) {
+	return Client.Get(url)
+}
+
+func Post(url, contentType string, body io.Reader) (*http.Response, error) {
+	return Client.Post(url, contentType, body)
+}
@@ -0,0 +1,87 @@
+package skip
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/goccy/go-json"
+	cache "github.com/patrickmn/go-cache"
+)
+
+const defaultAniSkipUrl = "https://api.aniskip.com/v2"
+
+// the playback waits for the skip times, a slow api shouldn't hold it up
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// AniSkipProvider gets the skip times from an AniSkip compatible api
+type AniSkipProvider struct {
+	BaseUrl string
+	C       *cache.Cache
+}
+
+var aniSkipProvider *AniSkipProvider
+
+// GetAniSkipProvider uses `ANI_AR_ANISKIP_URL` as the api url when it's set
+func GetAniSkipProvider() *AniSkipProvider {
+	if aniSkipProvider != nil {
+		return aniSkipProvider
+	}
+	baseUrl := os.Getenv("ANI_AR_ANISKIP_URL")
+	if baseUrl == "" {
+		baseUrl = defaultAniSkipUrl
+	}
+	aniSkipProvider = &AniSkipProvider{
+		BaseUrl: baseUrl,
+		C:       cache.New(time.Hour, 2*time.Hour),
+	}
+	return aniSkipProvider
+}
+
+type aniSkipResponse struct {
+	Found   bool `json:"found"`
+	Results []struct {
+		Interval struct {
+			StartTime float64 `json:"startTime"`
+			EndTime   float64 `json:"endTime"`
+		} `json:"interval"`
+		SkipType string `json:"skipType"`
+	} `json:"results"`
+}
+
+func (a *AniSkipProvider) GetSkipTimes(malId, episode int) ([]Interval, error) {
+	cacheKey := fmt.Sprintf("aniskip.%d.%d", malId, episode)
+	if v, found := a.C.Get(cacheKey); found {
+		return v.([]Interval), nil
+	}
+
+	url := fmt.Sprintf("%s/skip-times/%d/%d?types[]=op&types[]=ed&episodeLength=0", a.BaseUrl, malId, episode)
+	res, err := httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	// the api answers 404 when there are no skip times for the episode
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("aniskip api failed with status %d", res.StatusCode)
+	}
+
+	var response aniSkipResponse
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	var intervals []Interval
+	for _, r := range response.Results {
+		intervals = append(intervals, Interval{
+			Type:  r.SkipType,
+			Start: r.Interval.StartTime,
+			End:   r.Interval.EndTime,
+		})
+	}
+	a.C.Set(cacheKey, intervals, cache.DefaultExpiration)
+	return intervals, nil
+}
@@ -0,0 +1,46 @@
+package skip
+
+import (
+	"errors"
+	"os"
+	"strconv"
+
+	"github.com/ani/ani-ar/config"
+	"github.com/goccy/go-json"
+)
+
+// FileProvider reads the skip times from a local json file, used to
+// override or complete the api results. The file maps the mal id to the
+// episode number (or "*" for every episode) intervals:
+//
+//	{"11061": {"*": [{"type": "op", "start": 0, "end": 90}]}}
+type FileProvider struct {
+	Path string
+}
+
+func GetFileProvider() *FileProvider {
+	return &FileProvider{Path: config.Path("skip-times.json")}
+}
+
+func (f *FileProvider) GetSkipTimes(malId, episode int) ([]Interval, error) {
+	b, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var overrides map[string]map[string][]Interval
+	if err := json.Unmarshal(b, &overrides); err != nil {
+		return nil, errors.New("couldn't parse the skip times file, reason: " + err.Error())
+	}
+	episodes, found := overrides[strconv.Itoa(malId)]
+	if !found {
+		return nil, nil
+	}
+	if intervals, found := episodes[strconv.Itoa(episode)]; found {
+		return intervals, nil
+	}
+	return episodes["*"], nil
+}
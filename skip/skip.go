@@ -0,0 +1,48 @@
+package skip
+
+import (
+	"sort"
+)
+
+const (
+	TypeOpening = "op"
+	TypeEnding  = "ed"
+)
+
+// Interval is a part of the episode that can be skipped, in seconds
+type Interval struct {
+	Type  string  `json:"type"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// Provider returns the opening and ending intervals of an episode
+type Provider interface {
+	GetSkipTimes(malId, episode int) ([]Interval, error)
+}
+
+// Chain asks the providers in order and returns the first found intervals
+type Chain []Provider
+
+func (c Chain) GetSkipTimes(malId, episode int) ([]Interval, error) {
+	var lastErr error
+	for _, p := range c {
+		intervals, err := p.GetSkipTimes(malId, episode)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if len(intervals) > 0 {
+			sort.Slice(intervals, func(i, j int) bool {
+				return intervals[i].Start < intervals[j].Start
+			})
+			return intervals, nil
+		}
+	}
+	return nil, lastErr
+}
+
+// GetDefaultProvider returns the local override file backed by the AniSkip api
+func GetDefaultProvider() Provider {
+	return Chain{GetFileProvider(), GetAniSkipProvider()}
+}
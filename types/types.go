@@ -9,11 +9,38 @@ type AniResult struct {
 type AniVideo struct {
 	Src string `json:"src"`
 	Res string `json:"res"`
+	// extra request headers the video host expects (eg. Referer)
+	Headers map[string]string `json:"headers,omitempty"`
 }
+
+// SelectVideo picks the requested resolution, falling back to the best known one
+func SelectVideo(medias []AniVideo, res string) *AniVideo {
+	preferred := []string{"1080", "720", "480"}
+	if res != "" {
+		preferred = append([]string{res}, preferred...)
+	}
+	for _, r := range preferred {
+		for i := range medias {
+			if medias[i].Res == r {
+				return &medias[i]
+			}
+		}
+	}
+	if len(medias) == 0 {
+		return nil
+	}
+	return &medias[0]
+}
+
 type AniEpisode struct {
-	Anime                 AniResult         `json:"anime"`
-	Number                int               `json:"number"`
-	Url                   string            `json:"url"`
+	Anime  AniResult `json:"anime"`
+	Number int       `json:"number"`
+	Url    string    `json:"url"`
+	// optional details from MyAnimeList
+	Title                 string            `json:"title,omitempty"`
+	Aired                 string            `json:"aired,omitempty"`
+	Filler                bool              `json:"filler,omitempty"`
+	Recap                 bool              `json:"recap,omitempty"`
 	GetPlayerUrl          func() string     `json:"-"`
 	GetPlayersWithQuality func() []AniVideo `json:"-"`
 }
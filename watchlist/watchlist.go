@@ -0,0 +1,257 @@
+package watchlist
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ani/ani-ar/profile"
+	"github.com/goccy/go-json"
+)
+
+const (
+	StatusPlanToWatch = "plan-to-watch"
+	StatusWatching    = "watching"
+	StatusCompleted   = "completed"
+	StatusDropped     = "dropped"
+)
+
+var Statuses = []string{StatusPlanToWatch, StatusWatching, StatusCompleted, StatusDropped}
+
+var (
+	ErrNotFound      = errors.New("anime is not in the watchlist")
+	ErrInvalidStatus = errors.New("invalid status, it should be one of plan-to-watch, watching, completed or dropped")
+)
+
+// Entry is an anime in the watchlist, it's identified by the fetcher it
+// comes from and its id in that fetcher
+type Entry struct {
+	Source    string    `json:"source"`
+	Id        string    `json:"id"`
+	Title     string    `json:"title"`
+	MalId     int       `json:"malId,omitempty"`
+	Status    string    `json:"status"`
+	Favorite  bool      `json:"favorite"`
+	AddedAt   time.Time `json:"addedAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type Store struct {
+	path string
+
+	mu sync.RWMutex
+	// the modification time of the file when it was read, the cli changes
+	// the watchlist while the server or the tui run
+	modTime time.Time
+	Entries []*Entry `json:"entries"`
+}
+
+var (
+	storesMu sync.Mutex
+	stores   = make(map[string]*Store)
+)
+
+// GetStore returns the watchlist of the active profile
+func GetStore() (*Store, error) {
+	return GetProfileStore(profile.Active())
+}
+
+// GetProfileStore returns the watchlist of the profile read again when its
+// file changed. An unreadable watchlist file is an error, so it's never
+// overwritten by an empty watchlist
+func GetProfileStore(name string) (*Store, error) {
+	storesMu.Lock()
+	defer storesMu.Unlock()
+	if s, found := stores[name]; found {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s, s.reload()
+	}
+	s, err := Load(profile.Path(name, "watchlist.json"))
+	if err != nil {
+		return nil, err
+	}
+	stores[name] = s
+	return s, nil
+}
+
+func Load(path string) (*Store, error) {
+	s := &Store{path: path}
+	return s, s.reload()
+}
+
+// reload reads the file again when it changed since it was read, the
+// entries are kept when it can't be read. Must be called with the lock held
+func (s *Store) reload() error {
+	info, err := os.Stat(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		s.Entries, s.modTime = nil, time.Time{}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(s.modTime) {
+		return nil
+	}
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+	var read Store
+	if err := json.Unmarshal(b, &read); err != nil {
+		return errors.New("couldn't parse the watchlist file, reason: " + err.Error())
+	}
+	s.Entries, s.modTime = read.Entries, info.ModTime()
+	return nil
+}
+
+func IsValidStatus(status string) bool {
+	for _, s := range Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// must be called with the lock held
+func (s *Store) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), os.ModePerm); err != nil {
+		return err
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(s.path, b, 0644); err != nil {
+		return err
+	}
+	if info, err := os.Stat(s.path); err == nil {
+		s.modTime = info.ModTime()
+	}
+	return nil
+}
+
+// must be called with the lock held
+func (s *Store) find(source, id string) *Entry {
+	for _, e := range s.Entries {
+		if e.Source == source && e.Id == id {
+			return e
+		}
+	}
+	return nil
+}
+
+// Add puts the anime in the watchlist, an anime already in it gets the new
+// status and details. The status defaults to plan to watch
+func (s *Store) Add(entry Entry) (Entry, error) {
+	if entry.Status == "" {
+		entry.Status = StatusPlanToWatch
+	}
+	if !IsValidStatus(entry.Status) {
+		return Entry{}, ErrInvalidStatus
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.reload(); err != nil {
+		return Entry{}, err
+	}
+
+	now := time.Now()
+	entry.UpdatedAt = now
+	if e := s.find(entry.Source, entry.Id); e != nil {
+		entry.AddedAt = e.AddedAt
+		if entry.MalId == 0 {
+			entry.MalId = e.MalId
+		}
+		*e = entry
+	} else {
+		entry.AddedAt = now
+		e := entry
+		s.Entries = append(s.Entries, &e)
+	}
+	return entry, s.save()
+}
+
+func (s *Store) Remove(source, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.reload(); err != nil {
+		return err
+	}
+	for i, e := range s.Entries {
+		if e.Source == source && e.Id == id {
+			s.Entries = append(s.Entries[:i], s.Entries[i+1:]...)
+			return s.save()
+		}
+	}
+	return ErrNotFound
+}
+
+// Update changes the entry with the change func and saves the watchlist
+func (s *Store) Update(source, id string, change func(*Entry)) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.reload(); err != nil {
+		return Entry{}, err
+	}
+	e := s.find(source, id)
+	if e == nil {
+		return Entry{}, ErrNotFound
+	}
+	updated := *e
+	change(&updated)
+	if !IsValidStatus(updated.Status) {
+		return Entry{}, ErrInvalidStatus
+	}
+	updated.UpdatedAt = time.Now()
+	*e = updated
+	return updated, s.save()
+}
+
+func (s *Store) SetStatus(source, id, status string) (Entry, error) {
+	return s.Update(source, id, func(e *Entry) {
+		e.Status = status
+	})
+}
+
+func (s *Store) SetFavorite(source, id string, favorite bool) (Entry, error) {
+	return s.Update(source, id, func(e *Entry) {
+		e.Favorite = favorite
+	})
+}
+
+func (s *Store) Get(source, id string) (Entry, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e := s.find(source, id)
+	if e == nil {
+		return Entry{}, false
+	}
+	return *e, true
+}
+
+// List returns the entries with the status (all of them when it's empty),
+// the last updated ones first
+func (s *Store) List(status string, favoritesOnly bool) []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entries := []Entry{}
+	for _, e := range s.Entries {
+		if status != "" && e.Status != status {
+			continue
+		}
+		if favoritesOnly && !e.Favorite {
+			continue
+		}
+		entries = append(entries, *e)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].UpdatedAt.After(entries[j].UpdatedAt)
+	})
+	return entries
+}
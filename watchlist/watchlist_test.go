@@ -0,0 +1,92 @@
+package watchlist
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ani/ani-ar/config"
+)
+
+func TestCorruptWatchlistIsNotOverwritten(t *testing.T) {
+	t.Setenv("ANI_AR_CONFIG_DIR", t.TempDir())
+	path := filepath.Join(config.Dir(), "watchlist.json")
+	corrupt := []byte(`{"entries": [{"source": "anime3rb", "id": "naruto"`)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, corrupt, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := GetProfileStore("default"); err == nil {
+		t.Fatal("a corrupt watchlist should be an error")
+	}
+	if b, _ := os.ReadFile(path); string(b) != string(corrupt) {
+		t.Fatalf("the watchlist file was changed: %s", b)
+	}
+
+	// fixing the file makes the watchlist usable again
+	if err := os.WriteFile(path, []byte(`{"entries": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	store, err := GetProfileStore("default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Add(Entry{Source: "anime3rb", Id: "naruto"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// a store holding the file refuses to write over it once it's corrupt
+	if err := os.WriteFile(path, corrupt, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Add(Entry{Source: "anime3rb", Id: "bleach"}); err == nil {
+		t.Fatal("adding to a corrupt watchlist should fail")
+	}
+	if b, _ := os.ReadFile(path); string(b) != string(corrupt) {
+		t.Fatalf("the watchlist file was changed: %s", b)
+	}
+}
+
+func TestWatchlistChangedByAnotherProcess(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watchlist.json")
+	// eg. the server and `ani-ar list add`
+	server, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := server.Add(Entry{Source: "anime3rb", Id: "naruto"}); err != nil {
+		t.Fatal(err)
+	}
+	// the modification times differ even on the file systems with a coarse clock
+	time.Sleep(10 * time.Millisecond)
+	if _, err := cli.Add(Entry{Source: "anime3rb", Id: "bleach", Status: StatusWatching}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if _, err := server.SetFavorite("anime3rb", "naruto", true); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read.List("", false)) != 2 {
+		t.Fatalf("the watchlist should have both anime: %+v", read.List("", false))
+	}
+	if e, found := read.Get("anime3rb", "bleach"); !found || e.Status != StatusWatching {
+		t.Fatalf("the entry added by the cli is lost: %+v", e)
+	}
+	if e, _ := read.Get("anime3rb", "naruto"); !e.Favorite {
+		t.Fatal("the favorite set by the server is lost")
+	}
+}